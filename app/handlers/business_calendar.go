@@ -0,0 +1,255 @@
+package handlers
+
+import (
+	"app/domain/models"
+	"app/helpers"
+	"net/http"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+)
+
+func (r *appRoute) BusinessCalendarRoutes(rg *gin.RouterGroup) {
+	api := rg.Group("/business-calendars")
+	api.Use(r.Middleware.Auth(), r.Middleware.RequireRole(models.RoleAdmin))
+	api.POST("", r.createBusinessCalendar)
+	api.GET("", r.getBusinessCalendars)
+	api.GET("/:id", r.getBusinessCalendarByID)
+	api.PUT("/:id", r.updateBusinessCalendar)
+	api.DELETE("/:id", r.deleteBusinessCalendar)
+	api.POST("/:id/holidays", r.createHoliday)
+	api.DELETE("/:id/holidays/:holiday_id", r.deleteHoliday)
+}
+
+// CreateBusinessCalendar godoc
+// @Summary Create a new business calendar
+// @Description Create a calendar with weekly working hours (weekday 0 = Sunday) and public holidays. Windows of the same weekday must not overlap, a shift past midnight is a window ending at 24:00 and one starting at 00:00 the next weekday. Flagging it is_default makes it the calendar of every timer without an explicit one.
+// @Tags business-calendars
+// @Accept json
+// @Security BearerAuth
+// @Produce json
+// @Param calendar body models.BusinessCalendar true "Business Calendar Data"
+// @Success 201 {object} helpers.Response{data=models.BusinessCalendar}
+// @Failure 400 {object} helpers.Response
+// @Router /business-calendars [post]
+func (r *appRoute) createBusinessCalendar(c *gin.Context) {
+	var calendar models.BusinessCalendar
+	if err := c.ShouldBindJSON(&calendar); err != nil {
+		response := helpers.NewResponse(http.StatusBadRequest, "Invalid request body", nil, nil)
+		c.JSON(http.StatusBadRequest, response)
+		return
+	}
+
+	if err := r.Service.CreateBusinessCalendar(&calendar); err != nil {
+		status := serviceErrorStatus(err, http.StatusInternalServerError)
+		if status != http.StatusInternalServerError {
+			c.JSON(status, helpers.NewResponse(status, err.Error(), validationFields(err), nil))
+			return
+		}
+		response := helpers.NewResponse(http.StatusInternalServerError, "Failed to create business calendar", nil, nil)
+		c.JSON(http.StatusInternalServerError, response)
+		return
+	}
+
+	response := helpers.NewResponse(http.StatusCreated, "Business calendar created successfully", nil, calendar)
+	c.JSON(http.StatusCreated, response)
+}
+
+// GetBusinessCalendars godoc
+// @Summary Get all business calendars
+// @Description Get all business calendars with their working hours and holidays
+// @Tags business-calendars
+// @Security BearerAuth
+// @Produce json
+// @Success 200 {object} helpers.Response{data=[]models.BusinessCalendar}
+// @Router /business-calendars [get]
+func (r *appRoute) getBusinessCalendars(c *gin.Context) {
+	calendars, err := r.Service.GetBusinessCalendars()
+	if err != nil {
+		response := helpers.NewResponse(http.StatusInternalServerError, "Failed to get business calendars", nil, nil)
+		c.JSON(http.StatusInternalServerError, response)
+		return
+	}
+
+	response := helpers.NewResponse(http.StatusOK, "Business calendars retrieved successfully", nil, calendars)
+	c.JSON(http.StatusOK, response)
+}
+
+// GetBusinessCalendarByID godoc
+// @Summary Get a business calendar by ID
+// @Description Get a business calendar with its working hours and holidays
+// @Tags business-calendars
+// @Security BearerAuth
+// @Produce json
+// @Param id path int true "Calendar ID"
+// @Success 200 {object} helpers.Response{data=models.BusinessCalendar}
+// @Failure 404 {object} helpers.Response
+// @Router /business-calendars/{id} [get]
+func (r *appRoute) getBusinessCalendarByID(c *gin.Context) {
+	id, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		response := helpers.NewResponse(http.StatusBadRequest, "Invalid calendar ID", nil, nil)
+		c.JSON(http.StatusBadRequest, response)
+		return
+	}
+
+	calendar, err := r.Service.GetBusinessCalendarByID(id)
+	if err != nil {
+		response := helpers.NewResponse(http.StatusNotFound, "Business calendar not found", nil, nil)
+		c.JSON(http.StatusNotFound, response)
+		return
+	}
+
+	response := helpers.NewResponse(http.StatusOK, "Business calendar retrieved successfully", nil, calendar)
+	c.JSON(http.StatusOK, response)
+}
+
+// UpdateBusinessCalendar godoc
+// @Summary Update a business calendar
+// @Description Update a business calendar and replace its working hours, holidays are managed through the holiday endpoints
+// @Tags business-calendars
+// @Accept json
+// @Security BearerAuth
+// @Produce json
+// @Param id path int true "Calendar ID"
+// @Param calendar body models.BusinessCalendar true "Updated Business Calendar Data"
+// @Success 200 {object} helpers.Response{data=models.BusinessCalendar}
+// @Failure 400 {object} helpers.Response
+// @Router /business-calendars/{id} [put]
+func (r *appRoute) updateBusinessCalendar(c *gin.Context) {
+	id, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		response := helpers.NewResponse(http.StatusBadRequest, "Invalid calendar ID", nil, nil)
+		c.JSON(http.StatusBadRequest, response)
+		return
+	}
+
+	var calendar models.BusinessCalendar
+	if err := c.ShouldBindJSON(&calendar); err != nil {
+		response := helpers.NewResponse(http.StatusBadRequest, "Invalid request body", nil, nil)
+		c.JSON(http.StatusBadRequest, response)
+		return
+	}
+
+	calendar.ID = id
+	calendar.Holidays = nil
+	if err := r.Service.UpdateBusinessCalendar(&calendar); err != nil {
+		status := serviceErrorStatus(err, http.StatusInternalServerError)
+		if status != http.StatusInternalServerError {
+			c.JSON(status, helpers.NewResponse(status, err.Error(), validationFields(err), nil))
+			return
+		}
+		response := helpers.NewResponse(http.StatusInternalServerError, "Failed to update business calendar", nil, nil)
+		c.JSON(http.StatusInternalServerError, response)
+		return
+	}
+
+	response := helpers.NewResponse(http.StatusOK, "Business calendar updated successfully", nil, calendar)
+	c.JSON(http.StatusOK, response)
+}
+
+// DeleteBusinessCalendar godoc
+// @Summary Delete a business calendar
+// @Description Delete a business calendar, SLA policies using it fall back to the default calendar
+// @Tags business-calendars
+// @Security BearerAuth
+// @Produce json
+// @Param id path int true "Calendar ID"
+// @Success 200 {object} helpers.Response
+// @Router /business-calendars/{id} [delete]
+func (r *appRoute) deleteBusinessCalendar(c *gin.Context) {
+	id, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		response := helpers.NewResponse(http.StatusBadRequest, "Invalid calendar ID", nil, nil)
+		c.JSON(http.StatusBadRequest, response)
+		return
+	}
+
+	if err := r.Service.DeleteBusinessCalendar(id); err != nil {
+		response := helpers.NewResponse(http.StatusInternalServerError, "Failed to delete business calendar", nil, nil)
+		c.JSON(http.StatusInternalServerError, response)
+		return
+	}
+
+	response := helpers.NewResponse(http.StatusOK, "Business calendar deleted successfully", nil, nil)
+	c.JSON(http.StatusOK, response)
+}
+
+// CreateHoliday godoc
+// @Summary Add a holiday to a business calendar
+// @Description Add a public holiday (tanggal in YYYY-MM-DD) to a business calendar
+// @Tags business-calendars
+// @Accept json
+// @Security BearerAuth
+// @Produce json
+// @Param id path int true "Calendar ID"
+// @Param holiday body models.Holiday true "Holiday Data"
+// @Success 201 {object} helpers.Response{data=models.Holiday}
+// @Failure 400 {object} helpers.Response
+// @Failure 404 {object} helpers.Response
+// @Router /business-calendars/{id}/holidays [post]
+func (r *appRoute) createHoliday(c *gin.Context) {
+	calendarID, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		response := helpers.NewResponse(http.StatusBadRequest, "Invalid calendar ID", nil, nil)
+		c.JSON(http.StatusBadRequest, response)
+		return
+	}
+
+	var holiday models.Holiday
+	if err := c.ShouldBindJSON(&holiday); err != nil {
+		response := helpers.NewResponse(http.StatusBadRequest, "Invalid request body", nil, nil)
+		c.JSON(http.StatusBadRequest, response)
+		return
+	}
+
+	holiday.ID = 0
+	holiday.CalendarID = calendarID
+	if err := r.Service.CreateHoliday(&holiday); err != nil {
+		status := serviceErrorStatus(err, http.StatusInternalServerError)
+		if status != http.StatusInternalServerError {
+			c.JSON(status, helpers.NewResponse(status, err.Error(), validationFields(err), nil))
+			return
+		}
+		response := helpers.NewResponse(http.StatusInternalServerError, "Failed to create holiday", nil, nil)
+		c.JSON(http.StatusInternalServerError, response)
+		return
+	}
+
+	response := helpers.NewResponse(http.StatusCreated, "Holiday created successfully", nil, holiday)
+	c.JSON(http.StatusCreated, response)
+}
+
+// DeleteHoliday godoc
+// @Summary Remove a holiday from a business calendar
+// @Description Remove a public holiday from a business calendar
+// @Tags business-calendars
+// @Security BearerAuth
+// @Produce json
+// @Param id path int true "Calendar ID"
+// @Param holiday_id path int true "Holiday ID"
+// @Success 200 {object} helpers.Response
+// @Router /business-calendars/{id}/holidays/{holiday_id} [delete]
+func (r *appRoute) deleteHoliday(c *gin.Context) {
+	calendarID, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		response := helpers.NewResponse(http.StatusBadRequest, "Invalid calendar ID", nil, nil)
+		c.JSON(http.StatusBadRequest, response)
+		return
+	}
+	holidayID, err := strconv.Atoi(c.Param("holiday_id"))
+	if err != nil {
+		response := helpers.NewResponse(http.StatusBadRequest, "Invalid holiday ID", nil, nil)
+		c.JSON(http.StatusBadRequest, response)
+		return
+	}
+
+	if err := r.Service.DeleteHoliday(calendarID, holidayID); err != nil {
+		response := helpers.NewResponse(http.StatusInternalServerError, "Failed to delete holiday", nil, nil)
+		c.JSON(http.StatusInternalServerError, response)
+		return
+	}
+
+	response := helpers.NewResponse(http.StatusOK, "Holiday deleted successfully", nil, nil)
+	c.JSON(http.StatusOK, response)
+}
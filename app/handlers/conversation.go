@@ -42,13 +42,21 @@ func (r *appRoute) CreateCustomerConversation(c *gin.Context) {
 // @Security 	 BearerAuth
 // @Tags         conversations
 // @Produce      json
+// @Param        tags       query     string  false  "Comma separated tag IDs"
+// @Param        tags_mode  query     string  false  "Match any (default) or all of the tags"
 // @Success      200  {object}   helpers.Response{data=[]models.Conversation}
 // @Failure      500  {object}   helpers.Response
 // @Router       /conversations [get]
 func (r *appRoute) GetConversations(c *gin.Context) {
 	claim, _ := c.MustGet("userData").(models.User)
 
-	response := r.Service.GetConversations(claim)
+	tags, err := tagFilterFromQuery(c)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, helpers.NewResponse(http.StatusBadRequest, err.Error(), nil, nil))
+		return
+	}
+
+	response := r.Service.GetConversations(claim, tags)
 	c.JSON(response.Status, response)
 }
 
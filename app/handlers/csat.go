@@ -0,0 +1,176 @@
+package handlers
+
+import (
+	"app/domain"
+	"app/domain/models"
+	"app/domain/requests"
+	"app/helpers"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/gin-gonic/gin"
+)
+
+func (r *appRoute) CSATRoutes(rg *gin.RouterGroup) {
+	api := rg.Group("/csat")
+
+	// Public endpoints, the signed survey link authorizes the customer
+	api.GET("/survey", r.getCSATSurvey)
+	api.POST("/responses", r.answerCSATSurvey)
+
+	// Admin-only endpoints
+	api.GET("/report", r.Middleware.Auth(), r.Middleware.RequireRole(models.RoleAdmin), r.getCSATReport)
+}
+
+// GetCSATSurvey godoc
+// @Summary Get a CSAT survey
+// @Description Get the survey a signed survey link points to, used to show the rating form
+// @Tags csat
+// @Produce json
+// @Param token query string true "Survey token from the survey link"
+// @Success 200 {object} helpers.Response{data=requests.CSATSurveyResponse}
+// @Failure 400 {object} helpers.Response
+// @Failure 410 {object} helpers.Response
+// @Router /csat/survey [get]
+func (r *appRoute) getCSATSurvey(c *gin.Context) {
+	survey, err := r.Service.GetCSATSurvey(c.Query("token"))
+	if err != nil {
+		status := serviceErrorStatus(err, http.StatusInternalServerError)
+		if status != http.StatusInternalServerError {
+			response := helpers.NewResponse(status, err.Error(), nil, nil)
+			c.JSON(status, response)
+			return
+		}
+		response := helpers.NewResponse(http.StatusInternalServerError, "Failed to get survey", nil, nil)
+		c.JSON(http.StatusInternalServerError, response)
+		return
+	}
+
+	response := helpers.NewResponse(http.StatusOK, "Survey retrieved successfully", nil, mapCSATSurveyResponse(survey))
+	c.JSON(http.StatusOK, response)
+}
+
+// AnswerCSATSurvey godoc
+// @Summary Rate the support of a resolved ticket
+// @Description Store the rating (1-5) and an optional comment of a survey. A survey can be answered once, until its link expires.
+// @Tags csat
+// @Accept json
+// @Produce json
+// @Param answer body requests.CSATAnswerRequest true "Rating"
+// @Success 201 {object} helpers.Response{data=requests.CSATSurveyResponse}
+// @Failure 400 {object} helpers.Response
+// @Failure 409 {object} helpers.Response
+// @Failure 410 {object} helpers.Response
+// @Router /csat/responses [post]
+func (r *appRoute) answerCSATSurvey(c *gin.Context) {
+	var req requests.CSATAnswerRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		response := helpers.NewResponse(http.StatusBadRequest, "Invalid request body", nil, nil)
+		c.JSON(http.StatusBadRequest, response)
+		return
+	}
+
+	survey, err := r.Service.AnswerCSATSurvey(req.Token, req.Rating, req.Comment)
+	if err != nil {
+		status := serviceErrorStatus(err, http.StatusInternalServerError)
+		if status != http.StatusInternalServerError {
+			response := helpers.NewResponse(status, err.Error(), validationFields(err), nil)
+			c.JSON(status, response)
+			return
+		}
+		response := helpers.NewResponse(http.StatusInternalServerError, "Failed to save rating", nil, nil)
+		c.JSON(http.StatusInternalServerError, response)
+		return
+	}
+
+	response := helpers.NewResponse(http.StatusCreated, "Thank you for your feedback", nil, mapCSATSurveyResponse(survey))
+	c.JSON(http.StatusCreated, response)
+}
+
+// GetCSATReport godoc
+// @Summary Get the CSAT report
+// @Description Aggregate the answered surveys per agent, category or period (Admin only). csat_score is the share of ratings of 4 or 5 in percent.
+// @Tags csat
+// @Security BearerAuth
+// @Produce json
+// @Param group_by query string false "Group by agent, category or period (default)"
+// @Param period query string false "Period length when grouped by period: day, week or month (default)"
+// @Param from query string false "First day answered, YYYY-MM-DD"
+// @Param to query string false "Last day answered, YYYY-MM-DD"
+// @Param id_assignee query int false "Filter by agent"
+// @Param id_category query int false "Filter by category"
+// @Success 200 {object} helpers.Response{data=[]domain.CSATAggregate}
+// @Failure 400 {object} helpers.Response
+// @Router /csat/report [get]
+func (r *appRoute) getCSATReport(c *gin.Context) {
+	filter := domain.CSATFilter{
+		GroupBy: domain.CSATGroupBy(c.DefaultQuery("group_by", string(domain.CSATGroupByPeriod))),
+		Period:  domain.CSATPeriod(c.DefaultQuery("period", string(domain.CSATPeriodMonth))),
+	}
+
+	validation := map[string]string{}
+	switch filter.GroupBy {
+	case domain.CSATGroupByAgent, domain.CSATGroupByCategory, domain.CSATGroupByPeriod:
+	default:
+		validation["group_by"] = "use agent, category or period"
+	}
+	switch filter.Period {
+	case domain.CSATPeriodDay, domain.CSATPeriodWeek, domain.CSATPeriodMonth:
+	default:
+		validation["period"] = "use day, week or month"
+	}
+	if from := c.Query("from"); from != "" {
+		date, err := time.Parse("2006-01-02", from)
+		if err != nil {
+			validation["from"] = "use YYYY-MM-DD"
+		}
+		filter.From = date
+	}
+	if to := c.Query("to"); to != "" {
+		date, err := time.Parse("2006-01-02", to)
+		if err != nil {
+			validation["to"] = "use YYYY-MM-DD"
+		}
+		// The last day is included
+		filter.To = date.AddDate(0, 0, 1)
+	}
+	if len(validation) > 0 {
+		response := helpers.NewResponse(http.StatusBadRequest, "Invalid report filter", validation, nil)
+		c.JSON(http.StatusBadRequest, response)
+		return
+	}
+
+	assigneeID, _ := strconv.ParseUint(c.Query("id_assignee"), 10, 64)
+	categoryID, _ := strconv.Atoi(c.Query("id_category"))
+	filter.AssigneeID = assigneeID
+	filter.CategoryID = categoryID
+
+	report, err := r.Service.GetCSATReport(filter)
+	if err != nil {
+		response := helpers.NewResponse(http.StatusInternalServerError, "Failed to get CSAT report", nil, nil)
+		c.JSON(http.StatusInternalServerError, response)
+		return
+	}
+
+	response := helpers.NewResponse(http.StatusOK, "CSAT report retrieved successfully", nil, report)
+	c.JSON(http.StatusOK, response)
+}
+
+func mapCSATSurveyResponse(survey *models.CSATSurvey) requests.CSATSurveyResponse {
+	resp := requests.CSATSurveyResponse{
+		SurveyID:    survey.ID,
+		ExpiresAt:   survey.ExpiresAt,
+		Rating:      survey.Rating,
+		Comment:     survey.Comment,
+		RespondedAt: survey.RespondedAt,
+	}
+	if survey.Ticket != nil {
+		resp.KodeTiket = survey.Ticket.KodeTiket
+		resp.Judul = survey.Ticket.Judul
+	}
+	if survey.Assignee != nil {
+		resp.AgentName = survey.Assignee.Username
+	}
+	return resp
+}
@@ -0,0 +1,164 @@
+package handlers
+
+import (
+	"app/domain/models"
+	"app/helpers"
+	"net/http"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+)
+
+func (r *appRoute) CustomFieldRoutes(rg *gin.RouterGroup) {
+	api := rg.Group("/ticket-categories/:id/custom-fields")
+	api.GET("", r.getCustomFieldDefinitions)
+	api.POST("", r.Middleware.Auth(), r.Middleware.RequireRole(models.RoleAdmin), r.createCustomFieldDefinition)
+	api.PUT("/:field_id", r.Middleware.Auth(), r.Middleware.RequireRole(models.RoleAdmin), r.updateCustomFieldDefinition)
+	api.DELETE("/:field_id", r.Middleware.Auth(), r.Middleware.RequireRole(models.RoleAdmin), r.deleteCustomFieldDefinition)
+}
+
+// CreateCustomFieldDefinition godoc
+// @Summary Define a custom field for a category
+// @Description Define a custom field collected on the tickets of a category. field_type is text, number, boolean, date (YYYY-MM-DD) or select; select fields need options and text fields may set a regex.
+// @Tags custom-fields
+// @Accept json
+// @Security BearerAuth
+// @Produce json
+// @Param id path int true "Category ID"
+// @Param field body models.CustomFieldDefinition true "Custom Field Definition"
+// @Success 201 {object} helpers.Response{data=models.CustomFieldDefinition}
+// @Failure 400 {object} helpers.Response
+// @Router /ticket-categories/{id}/custom-fields [post]
+func (r *appRoute) createCustomFieldDefinition(c *gin.Context) {
+	categoryID, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		response := helpers.NewResponse(http.StatusBadRequest, "Invalid category ID", nil, nil)
+		c.JSON(http.StatusBadRequest, response)
+		return
+	}
+
+	var definition models.CustomFieldDefinition
+	if err := c.ShouldBindJSON(&definition); err != nil {
+		response := helpers.NewResponse(http.StatusBadRequest, "Invalid request body", nil, nil)
+		c.JSON(http.StatusBadRequest, response)
+		return
+	}
+
+	definition.ID = 0
+	definition.CategoryID = categoryID
+	if err := r.Service.CreateCustomFieldDefinition(&definition); err != nil {
+		response := helpers.NewResponse(http.StatusBadRequest, "Failed to create custom field: "+err.Error(), nil, nil)
+		c.JSON(http.StatusBadRequest, response)
+		return
+	}
+
+	response := helpers.NewResponse(http.StatusCreated, "Custom field created successfully", nil, definition)
+	c.JSON(http.StatusCreated, response)
+}
+
+// GetCustomFieldDefinitions godoc
+// @Summary Get the custom fields of a category
+// @Description Get the custom fields defined for a category in display order
+// @Tags custom-fields
+// @Produce json
+// @Param id path int true "Category ID"
+// @Success 200 {object} helpers.Response{data=[]models.CustomFieldDefinition}
+// @Router /ticket-categories/{id}/custom-fields [get]
+func (r *appRoute) getCustomFieldDefinitions(c *gin.Context) {
+	categoryID, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		response := helpers.NewResponse(http.StatusBadRequest, "Invalid category ID", nil, nil)
+		c.JSON(http.StatusBadRequest, response)
+		return
+	}
+
+	definitions, err := r.Service.GetCustomFieldDefinitions(categoryID)
+	if err != nil {
+		response := helpers.NewResponse(http.StatusInternalServerError, "Failed to get custom fields", nil, nil)
+		c.JSON(http.StatusInternalServerError, response)
+		return
+	}
+
+	response := helpers.NewResponse(http.StatusOK, "Custom fields retrieved successfully", nil, definitions)
+	c.JSON(http.StatusOK, response)
+}
+
+// UpdateCustomFieldDefinition godoc
+// @Summary Update a custom field
+// @Description Update a custom field of a category, values stored on existing tickets are kept
+// @Tags custom-fields
+// @Accept json
+// @Security BearerAuth
+// @Produce json
+// @Param id path int true "Category ID"
+// @Param field_id path int true "Custom Field ID"
+// @Param field body models.CustomFieldDefinition true "Updated Custom Field Definition"
+// @Success 200 {object} helpers.Response{data=models.CustomFieldDefinition}
+// @Failure 400 {object} helpers.Response
+// @Router /ticket-categories/{id}/custom-fields/{field_id} [put]
+func (r *appRoute) updateCustomFieldDefinition(c *gin.Context) {
+	categoryID, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		response := helpers.NewResponse(http.StatusBadRequest, "Invalid category ID", nil, nil)
+		c.JSON(http.StatusBadRequest, response)
+		return
+	}
+	fieldID, err := strconv.Atoi(c.Param("field_id"))
+	if err != nil {
+		response := helpers.NewResponse(http.StatusBadRequest, "Invalid custom field ID", nil, nil)
+		c.JSON(http.StatusBadRequest, response)
+		return
+	}
+
+	var definition models.CustomFieldDefinition
+	if err := c.ShouldBindJSON(&definition); err != nil {
+		response := helpers.NewResponse(http.StatusBadRequest, "Invalid request body", nil, nil)
+		c.JSON(http.StatusBadRequest, response)
+		return
+	}
+
+	definition.ID = fieldID
+	definition.CategoryID = categoryID
+	if err := r.Service.UpdateCustomFieldDefinition(&definition); err != nil {
+		response := helpers.NewResponse(http.StatusBadRequest, "Failed to update custom field: "+err.Error(), nil, nil)
+		c.JSON(http.StatusBadRequest, response)
+		return
+	}
+
+	response := helpers.NewResponse(http.StatusOK, "Custom field updated successfully", nil, definition)
+	c.JSON(http.StatusOK, response)
+}
+
+// DeleteCustomFieldDefinition godoc
+// @Summary Delete a custom field
+// @Description Delete a custom field of a category
+// @Tags custom-fields
+// @Security BearerAuth
+// @Produce json
+// @Param id path int true "Category ID"
+// @Param field_id path int true "Custom Field ID"
+// @Success 200 {object} helpers.Response
+// @Router /ticket-categories/{id}/custom-fields/{field_id} [delete]
+func (r *appRoute) deleteCustomFieldDefinition(c *gin.Context) {
+	categoryID, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		response := helpers.NewResponse(http.StatusBadRequest, "Invalid category ID", nil, nil)
+		c.JSON(http.StatusBadRequest, response)
+		return
+	}
+	fieldID, err := strconv.Atoi(c.Param("field_id"))
+	if err != nil {
+		response := helpers.NewResponse(http.StatusBadRequest, "Invalid custom field ID", nil, nil)
+		c.JSON(http.StatusBadRequest, response)
+		return
+	}
+
+	if err := r.Service.DeleteCustomFieldDefinition(categoryID, fieldID); err != nil {
+		response := helpers.NewResponse(http.StatusNotFound, "Failed to delete custom field: "+err.Error(), nil, nil)
+		c.JSON(http.StatusNotFound, response)
+		return
+	}
+
+	response := helpers.NewResponse(http.StatusOK, "Custom field deleted successfully", nil, nil)
+	c.JSON(http.StatusOK, response)
+}
@@ -0,0 +1,49 @@
+package handlers
+
+import (
+	"app/domain"
+	"errors"
+	"net/http"
+)
+
+// serviceErrorStatus maps errors returned by the service layer to an HTTP status,
+// falling back to the given status for unexpected errors
+func serviceErrorStatus(err error, fallback int) int {
+	switch {
+	case errors.As(err, new(*domain.ValidationError)),
+		errors.Is(err, domain.ErrInvalidStatusTransition),
+		errors.Is(err, domain.ErrTicketMergeSelf),
+		errors.Is(err, domain.ErrInvalidTicketLink),
+		errors.Is(err, domain.ErrInvalidSurveyToken):
+		return http.StatusBadRequest
+	case errors.Is(err, domain.ErrTransitionForbidden),
+		errors.Is(err, domain.ErrNotTicketOwner),
+		errors.Is(err, domain.ErrNotViewOwner),
+		errors.Is(err, domain.ErrTicketAccessDenied):
+		return http.StatusForbidden
+	case errors.Is(err, domain.ErrTicketNotFound),
+		errors.Is(err, domain.ErrRecordNotFound),
+		errors.Is(err, domain.ErrTicketLinkNotFound),
+		errors.Is(err, domain.ErrTagNotFound):
+		return http.StatusNotFound
+	case errors.Is(err, domain.ErrTicketAlreadyMerged),
+		errors.Is(err, domain.ErrTicketLinkExists),
+		errors.Is(err, domain.ErrVersionConflict),
+		errors.Is(err, domain.ErrTicketCodeExists),
+		errors.Is(err, domain.ErrSurveyAnswered):
+		return http.StatusConflict
+	case errors.Is(err, domain.ErrSurveyExpired):
+		return http.StatusGone
+	default:
+		return fallback
+	}
+}
+
+// validationFields returns the per-field messages of a validation error, nil for other errors
+func validationFields(err error) map[string]string {
+	var validation *domain.ValidationError
+	if errors.As(err, &validation) {
+		return validation.Fields
+	}
+	return nil
+}
@@ -0,0 +1,186 @@
+package handlers
+
+import (
+	"app/domain/models"
+	"app/helpers"
+	"net/http"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+)
+
+func (r *appRoute) EscalationRuleRoutes(rg *gin.RouterGroup) {
+	api := rg.Group("/escalation-rules")
+	api.Use(r.Middleware.Auth(), r.Middleware.RequireRole(models.RoleAdmin))
+	api.POST("", r.createEscalationRule)
+	api.GET("", r.getEscalationRules)
+	api.GET("/:id", r.getEscalationRuleByID)
+	api.PUT("/:id", r.updateEscalationRule)
+	api.DELETE("/:id", r.deleteEscalationRule)
+}
+
+// CreateEscalationRule godoc
+// @Summary Create a new escalation rule
+// @Description Create a rule escalating tickets no admin or support user commented on for no_comment_hours, counted in the working hours of the default business calendar. Empty status_id, priority_id or category_id match every ticket, a rule without status_id only matches tickets in a status that is neither terminal, resolved nor pausing the SLA. A matching ticket gets raise_priority_id, is reassigned to reassign_to_id and its watchers are notified when notify is set, once per rule.
+// @Tags escalation-rules
+// @Accept json
+// @Security BearerAuth
+// @Produce json
+// @Param rule body models.EscalationRule true "Escalation Rule Data"
+// @Success 201 {object} helpers.Response{data=models.EscalationRule}
+// @Failure 400 {object} helpers.Response
+// @Router /escalation-rules [post]
+func (r *appRoute) createEscalationRule(c *gin.Context) {
+	// A rule is active and notifies unless is_active or notify are sent as false
+	rule := models.EscalationRule{Notify: true, IsActive: true}
+	if err := c.ShouldBindJSON(&rule); err != nil {
+		response := helpers.NewResponse(http.StatusBadRequest, "Invalid request body", nil, nil)
+		c.JSON(http.StatusBadRequest, response)
+		return
+	}
+
+	if err := r.Service.CreateEscalationRule(&rule); err != nil {
+		status := serviceErrorStatus(err, http.StatusInternalServerError)
+		if status != http.StatusInternalServerError {
+			c.JSON(status, helpers.NewResponse(status, err.Error(), validationFields(err), nil))
+			return
+		}
+		response := helpers.NewResponse(http.StatusInternalServerError, "Failed to create escalation rule", nil, nil)
+		c.JSON(http.StatusInternalServerError, response)
+		return
+	}
+
+	response := helpers.NewResponse(http.StatusCreated, "Escalation rule created successfully", nil, rule)
+	c.JSON(http.StatusCreated, response)
+}
+
+// GetEscalationRules godoc
+// @Summary Get all escalation rules
+// @Description Get a list of all escalation rules
+// @Tags escalation-rules
+// @Security BearerAuth
+// @Produce json
+// @Success 200 {object} helpers.Response{data=[]models.EscalationRule}
+// @Router /escalation-rules [get]
+func (r *appRoute) getEscalationRules(c *gin.Context) {
+	rules, err := r.Service.GetEscalationRules()
+	if err != nil {
+		response := helpers.NewResponse(http.StatusInternalServerError, "Failed to get escalation rules", nil, nil)
+		c.JSON(http.StatusInternalServerError, response)
+		return
+	}
+
+	response := helpers.NewResponse(http.StatusOK, "Escalation rules retrieved successfully", nil, rules)
+	c.JSON(http.StatusOK, response)
+}
+
+// GetEscalationRuleByID godoc
+// @Summary Get an escalation rule by ID
+// @Description Get an escalation rule by its ID
+// @Tags escalation-rules
+// @Security BearerAuth
+// @Produce json
+// @Param id path int true "Rule ID"
+// @Success 200 {object} helpers.Response{data=models.EscalationRule}
+// @Failure 404 {object} helpers.Response
+// @Router /escalation-rules/{id} [get]
+func (r *appRoute) getEscalationRuleByID(c *gin.Context) {
+	id, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		response := helpers.NewResponse(http.StatusBadRequest, "Invalid rule ID", nil, nil)
+		c.JSON(http.StatusBadRequest, response)
+		return
+	}
+
+	rule, err := r.Service.GetEscalationRuleByID(id)
+	if err != nil {
+		status := serviceErrorStatus(err, http.StatusInternalServerError)
+		if status != http.StatusInternalServerError {
+			c.JSON(status, helpers.NewResponse(status, "Escalation rule not found", nil, nil))
+			return
+		}
+		response := helpers.NewResponse(http.StatusInternalServerError, "Failed to get escalation rule", nil, nil)
+		c.JSON(http.StatusInternalServerError, response)
+		return
+	}
+
+	response := helpers.NewResponse(http.StatusOK, "Escalation rule retrieved successfully", nil, rule)
+	c.JSON(http.StatusOK, response)
+}
+
+// UpdateEscalationRule godoc
+// @Summary Update an escalation rule
+// @Description Update an escalation rule by its ID, tickets the rule already escalated are not escalated again
+// @Tags escalation-rules
+// @Accept json
+// @Security BearerAuth
+// @Produce json
+// @Param id path int true "Rule ID"
+// @Param rule body models.EscalationRule true "Updated Escalation Rule Data"
+// @Success 200 {object} helpers.Response{data=models.EscalationRule}
+// @Failure 400 {object} helpers.Response
+// @Failure 404 {object} helpers.Response
+// @Router /escalation-rules/{id} [put]
+func (r *appRoute) updateEscalationRule(c *gin.Context) {
+	id, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		response := helpers.NewResponse(http.StatusBadRequest, "Invalid rule ID", nil, nil)
+		c.JSON(http.StatusBadRequest, response)
+		return
+	}
+
+	var rule models.EscalationRule
+	if err := c.ShouldBindJSON(&rule); err != nil {
+		response := helpers.NewResponse(http.StatusBadRequest, "Invalid request body", nil, nil)
+		c.JSON(http.StatusBadRequest, response)
+		return
+	}
+
+	rule.ID = id
+	if err := r.Service.UpdateEscalationRule(&rule); err != nil {
+		status := serviceErrorStatus(err, http.StatusInternalServerError)
+		if status != http.StatusInternalServerError {
+			c.JSON(status, helpers.NewResponse(status, err.Error(), validationFields(err), nil))
+			return
+		}
+		response := helpers.NewResponse(http.StatusInternalServerError, "Failed to update escalation rule", nil, nil)
+		c.JSON(http.StatusInternalServerError, response)
+		return
+	}
+
+	response := helpers.NewResponse(http.StatusOK, "Escalation rule updated successfully", nil, rule)
+	c.JSON(http.StatusOK, response)
+}
+
+// DeleteEscalationRule godoc
+// @Summary Delete an escalation rule
+// @Description Delete an escalation rule by its ID
+// @Tags escalation-rules
+// @Security BearerAuth
+// @Produce json
+// @Param id path int true "Rule ID"
+// @Success 200 {object} helpers.Response
+// @Failure 404 {object} helpers.Response
+// @Router /escalation-rules/{id} [delete]
+func (r *appRoute) deleteEscalationRule(c *gin.Context) {
+	id, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		response := helpers.NewResponse(http.StatusBadRequest, "Invalid rule ID", nil, nil)
+		c.JSON(http.StatusBadRequest, response)
+		return
+	}
+
+	if err := r.Service.DeleteEscalationRule(id); err != nil {
+		status := serviceErrorStatus(err, http.StatusInternalServerError)
+		if status != http.StatusInternalServerError {
+			c.JSON(status, helpers.NewResponse(status, err.Error(), nil, nil))
+			return
+		}
+		response := helpers.NewResponse(http.StatusInternalServerError, "Failed to delete escalation rule", nil, nil)
+		c.JSON(http.StatusInternalServerError, response)
+		return
+	}
+
+	response := helpers.NewResponse(http.StatusOK, "Escalation rule deleted successfully", nil, nil)
+	c.JSON(http.StatusOK, response)
+}
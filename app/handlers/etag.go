@@ -0,0 +1,45 @@
+package handlers
+
+import (
+	"app/helpers"
+	"errors"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+)
+
+var errInvalidIfMatch = errors.New("invalid If-Match header, send the ETag of the record")
+
+// setETag sends the version of a record as its entity tag
+func setETag(c *gin.Context, version int) {
+	c.Header("ETag", strconv.Quote(strconv.Itoa(version)))
+}
+
+// ifMatchVersion returns the version required by the If-Match header, 0 when the header
+// is absent or "*" so the write is unconditional
+func ifMatchVersion(c *gin.Context) (int, error) {
+	header := strings.TrimSpace(c.GetHeader("If-Match"))
+	if header == "" || header == "*" {
+		return 0, nil
+	}
+
+	tag, err := strconv.Unquote(strings.TrimPrefix(header, "W/"))
+	if err != nil {
+		return 0, errInvalidIfMatch
+	}
+	version, err := strconv.Atoi(tag)
+	if err != nil || version < 1 {
+		return 0, errInvalidIfMatch
+	}
+	return version, nil
+}
+
+// respondVersionConflict answers a write based on an outdated version with 409 and the
+// current state of the record, so the client can merge its change and retry
+func respondVersionConflict(c *gin.Context, err error, version int, current interface{}) {
+	setETag(c, version)
+	response := helpers.NewResponse(http.StatusConflict, err.Error(), nil, current)
+	c.JSON(http.StatusConflict, response)
+}
@@ -25,13 +25,23 @@ func App(service domain.AppService, route *gin.Engine, middleware middleware.App
 	handler.MessageRoute("/conversations/:id/messages")
 	handler.AdminConversationStatesRoute("/conversations/notifications")
 	handler.TicketCategoryRoutes(handler.Route)
+	handler.CustomFieldRoutes(handler.Route)
 	handler.TicketPriorityRoutes(handler.Route)
 	handler.TicketStatusRoutes(handler.Route)
+	handler.TicketStatusTransitionRoutes(handler.Route)
+	handler.BusinessCalendarRoutes(handler.Route)
+	handler.SLAPolicyRoutes(handler.Route)
+	handler.EscalationRuleRoutes(handler.Route)
 	handler.TicketRoutes(handler.Route)
+	handler.TicketLinkRoutes(handler.Route)
+	handler.TagRoutes(handler.Route)
+	handler.TicketWatcherRoutes(handler.Route)
 	handler.TicketAssignmentRoutes(handler.Route)
 	handler.TicketAttachmentRoutes(handler.Route)
 	handler.TicketCommentRoutes(handler.Route)
 	handler.TicketLogRoutes(handler.Route)
+	handler.SavedTicketViewRoutes(handler.Route)
+	handler.CSATRoutes(handler.Route)
 	handler.Route.GET("/me", handler.Middleware.Auth(), handler.GetCurrentUser)
     handler.Route.GET("/users/support", handler.Middleware.Auth(), handler.GetSupportUsers)
 }
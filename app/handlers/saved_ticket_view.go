@@ -0,0 +1,178 @@
+package handlers
+
+import (
+	"app/domain/models"
+	"app/domain/requests"
+	"app/helpers"
+	"net/http"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+)
+
+func (r *appRoute) SavedTicketViewRoutes(rg *gin.RouterGroup) {
+	api := rg.Group("/ticket-views")
+	api.Use(r.Middleware.Auth(), r.Middleware.RequireAdminOrSupport())
+	api.POST("", r.createSavedTicketView)
+	api.GET("", r.getSavedTicketViews)
+	api.GET("/:id/tickets", r.runSavedTicketView)
+	api.DELETE("/:id", r.deleteSavedTicketView)
+}
+
+// CreateSavedTicketView godoc
+// @Summary Save a ticket view
+// @Description Save a ticket list filter under a name. The filter takes the ticket list query parameters (code, role, status, priority, category, min_reopen_count, include_pending, sla, tags, tags_mode) and custom_fields by key. sort is newest (default), oldest or updated. A shared view is listed for every agent.
+// @Tags ticket-views
+// @Accept json
+// @Security BearerAuth
+// @Produce json
+// @Param view body requests.CreateSavedTicketViewRequest true "View Data"
+// @Success 201 {object} helpers.Response{data=models.SavedTicketView}
+// @Failure 400 {object} helpers.Response
+// @Router /ticket-views [post]
+func (r *appRoute) createSavedTicketView(c *gin.Context) {
+	var req requests.CreateSavedTicketViewRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		response := helpers.NewResponse(http.StatusBadRequest, "Invalid request body", nil, nil)
+		c.JSON(http.StatusBadRequest, response)
+		return
+	}
+
+	user, _ := c.Get("userData")
+	claim, _ := user.(models.User)
+	view := models.SavedTicketView{
+		NamaView: req.NamaView,
+		Filter:   req.Filter,
+		Sort:     req.Sort,
+		IsShared: req.IsShared,
+	}
+	if err := r.Service.CreateSavedTicketView(&view, claim); err != nil {
+		status := serviceErrorStatus(err, http.StatusInternalServerError)
+		if status != http.StatusInternalServerError {
+			c.JSON(status, helpers.NewResponse(status, err.Error(), validationFields(err), nil))
+			return
+		}
+		response := helpers.NewResponse(http.StatusInternalServerError, "Failed to save ticket view", nil, nil)
+		c.JSON(http.StatusInternalServerError, response)
+		return
+	}
+
+	response := helpers.NewResponse(http.StatusCreated, "Ticket view saved successfully", nil, view)
+	c.JSON(http.StatusCreated, response)
+}
+
+// GetSavedTicketViews godoc
+// @Summary Get ticket views
+// @Description Get the ticket views of the current agent and the views shared by others, each with the live count of the tickets it matches. Support agents only count the tickets assigned to them.
+// @Tags ticket-views
+// @Security BearerAuth
+// @Produce json
+// @Success 200 {object} helpers.Response{data=[]domain.SavedTicketViewSummary}
+// @Router /ticket-views [get]
+func (r *appRoute) getSavedTicketViews(c *gin.Context) {
+	user, _ := c.Get("userData")
+	claim, _ := user.(models.User)
+	views, err := r.Service.GetSavedTicketViews(claim)
+	if err != nil {
+		response := helpers.NewResponse(http.StatusInternalServerError, "Failed to get ticket views", nil, nil)
+		c.JSON(http.StatusInternalServerError, response)
+		return
+	}
+
+	response := helpers.NewResponse(http.StatusOK, "Ticket views retrieved successfully", nil, views)
+	c.JSON(http.StatusOK, response)
+}
+
+// RunSavedTicketView godoc
+// @Summary Get the tickets of a view
+// @Description Get the tickets matching a saved view in the order of the view, cursor-based pagination. meta.count is the number of tickets the view matches. Support agents only get the tickets assigned to them.
+// @Tags ticket-views
+// @Security BearerAuth
+// @Produce json
+// @Param id path int true "View ID"
+// @Param limit query int false "Items per page (default: 10)"
+// @Param cursor query string false "Cursor for next page"
+// @Success 200 {object} helpers.Response{data=requests.TicketListResponse}
+// @Failure 404 {object} helpers.Response
+// @Router /ticket-views/{id}/tickets [get]
+func (r *appRoute) runSavedTicketView(c *gin.Context) {
+	id, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		response := helpers.NewResponse(http.StatusBadRequest, "Invalid view ID", nil, nil)
+		c.JSON(http.StatusBadRequest, response)
+		return
+	}
+
+	limit, _ := strconv.Atoi(c.DefaultQuery("limit", "10"))
+	if limit < 1 || limit > 100 {
+		limit = 10
+	}
+	cursor := c.Query("cursor")
+
+	user, _ := c.Get("userData")
+	claim, _ := user.(models.User)
+	view, tickets, nextCursor, err := r.Service.RunSavedTicketView(id, limit, cursor, claim)
+	if err != nil {
+		status := serviceErrorStatus(err, http.StatusInternalServerError)
+		if status != http.StatusInternalServerError {
+			c.JSON(status, helpers.NewResponse(status, "Ticket view not found", nil, nil))
+			return
+		}
+		response := helpers.NewResponse(http.StatusInternalServerError, "Failed to get tickets", nil, nil)
+		c.JSON(http.StatusInternalServerError, response)
+		return
+	}
+
+	resp := make([]requests.TicketResponse, 0, len(tickets))
+	for _, ticket := range tickets {
+		resp = append(resp, mapTicketResponse(&ticket))
+	}
+
+	responseData := map[string]interface{}{
+		"data": resp,
+		"meta": map[string]interface{}{
+			"next_cursor": nextCursor,
+			"limit":       limit,
+			"count":       view.Count,
+			"view":        view.SavedTicketView,
+		},
+	}
+	response := helpers.NewResponse(http.StatusOK, "Tickets retrieved successfully", nil, responseData)
+	c.JSON(http.StatusOK, response)
+}
+
+// DeleteSavedTicketView godoc
+// @Summary Delete a ticket view
+// @Description Delete a ticket view of the current agent, admins may delete any shared view
+// @Tags ticket-views
+// @Security BearerAuth
+// @Produce json
+// @Param id path int true "View ID"
+// @Success 200 {object} helpers.Response
+// @Failure 403 {object} helpers.Response
+// @Failure 404 {object} helpers.Response
+// @Router /ticket-views/{id} [delete]
+func (r *appRoute) deleteSavedTicketView(c *gin.Context) {
+	id, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		response := helpers.NewResponse(http.StatusBadRequest, "Invalid view ID", nil, nil)
+		c.JSON(http.StatusBadRequest, response)
+		return
+	}
+
+	user, _ := c.Get("userData")
+	claim, _ := user.(models.User)
+	if err := r.Service.DeleteSavedTicketView(id, claim); err != nil {
+		status := serviceErrorStatus(err, http.StatusInternalServerError)
+		if status != http.StatusInternalServerError {
+			c.JSON(status, helpers.NewResponse(status, err.Error(), nil, nil))
+			return
+		}
+		response := helpers.NewResponse(http.StatusInternalServerError, "Failed to delete ticket view", nil, nil)
+		c.JSON(http.StatusInternalServerError, response)
+		return
+	}
+
+	response := helpers.NewResponse(http.StatusOK, "Ticket view deleted successfully", nil, nil)
+	c.JSON(http.StatusOK, response)
+}
@@ -0,0 +1,162 @@
+package handlers
+
+import (
+	"app/domain/models"
+	"app/helpers"
+	"net/http"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+)
+
+func (r *appRoute) SLAPolicyRoutes(rg *gin.RouterGroup) {
+	api := rg.Group("/sla-policies")
+	api.Use(r.Middleware.Auth(), r.Middleware.RequireRole(models.RoleAdmin))
+	api.POST("", r.createSLAPolicy)
+	api.GET("", r.getSLAPolicies)
+	api.GET("/:id", r.getSLAPolicyByID)
+	api.PUT("/:id", r.updateSLAPolicy)
+	api.DELETE("/:id", r.deleteSLAPolicy)
+}
+
+// CreateSLAPolicy godoc
+// @Summary Create a new SLA policy
+// @Description Create a new SLA policy. Empty priority_id, category_id or tipe_pengaduan match every ticket, the most specific active policy is applied.
+// @Tags sla-policies
+// @Accept json
+// @Security BearerAuth
+// @Produce json
+// @Param policy body models.SLAPolicy true "SLA Policy Data"
+// @Success 201 {object} helpers.Response{data=models.SLAPolicy}
+// @Failure 400 {object} helpers.Response
+// @Router /sla-policies [post]
+func (r *appRoute) createSLAPolicy(c *gin.Context) {
+	// A policy is active unless is_active is sent as false
+	policy := models.SLAPolicy{IsActive: true}
+	if err := c.ShouldBindJSON(&policy); err != nil {
+		response := helpers.NewResponse(http.StatusBadRequest, "Invalid request body", nil, nil)
+		c.JSON(http.StatusBadRequest, response)
+		return
+	}
+
+	if err := r.Service.CreateSLAPolicy(&policy); err != nil {
+		response := helpers.NewResponse(http.StatusBadRequest, "Failed to create SLA policy: "+err.Error(), nil, nil)
+		c.JSON(http.StatusBadRequest, response)
+		return
+	}
+
+	response := helpers.NewResponse(http.StatusCreated, "SLA policy created successfully", nil, policy)
+	c.JSON(http.StatusCreated, response)
+}
+
+// GetSLAPolicies godoc
+// @Summary Get all SLA policies
+// @Description Get a list of all SLA policies
+// @Tags sla-policies
+// @Security BearerAuth
+// @Produce json
+// @Success 200 {object} helpers.Response{data=[]models.SLAPolicy}
+// @Router /sla-policies [get]
+func (r *appRoute) getSLAPolicies(c *gin.Context) {
+	policies, err := r.Service.GetSLAPolicies()
+	if err != nil {
+		response := helpers.NewResponse(http.StatusInternalServerError, "Failed to get SLA policies", nil, nil)
+		c.JSON(http.StatusInternalServerError, response)
+		return
+	}
+
+	response := helpers.NewResponse(http.StatusOK, "SLA policies retrieved successfully", nil, policies)
+	c.JSON(http.StatusOK, response)
+}
+
+// GetSLAPolicyByID godoc
+// @Summary Get an SLA policy by ID
+// @Description Get an SLA policy by its ID
+// @Tags sla-policies
+// @Security BearerAuth
+// @Produce json
+// @Param id path int true "Policy ID"
+// @Success 200 {object} helpers.Response{data=models.SLAPolicy}
+// @Router /sla-policies/{id} [get]
+func (r *appRoute) getSLAPolicyByID(c *gin.Context) {
+	id, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		response := helpers.NewResponse(http.StatusBadRequest, "Invalid policy ID", nil, nil)
+		c.JSON(http.StatusBadRequest, response)
+		return
+	}
+
+	policy, err := r.Service.GetSLAPolicyByID(id)
+	if err != nil {
+		response := helpers.NewResponse(http.StatusNotFound, "SLA policy not found", nil, nil)
+		c.JSON(http.StatusNotFound, response)
+		return
+	}
+
+	response := helpers.NewResponse(http.StatusOK, "SLA policy retrieved successfully", nil, policy)
+	c.JSON(http.StatusOK, response)
+}
+
+// UpdateSLAPolicy godoc
+// @Summary Update an SLA policy
+// @Description Update an SLA policy by its ID, tickets pick up the new targets on their next change
+// @Tags sla-policies
+// @Accept json
+// @Security BearerAuth
+// @Produce json
+// @Param id path int true "Policy ID"
+// @Param policy body models.SLAPolicy true "Updated SLA Policy Data"
+// @Success 200 {object} helpers.Response{data=models.SLAPolicy}
+// @Router /sla-policies/{id} [put]
+func (r *appRoute) updateSLAPolicy(c *gin.Context) {
+	id, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		response := helpers.NewResponse(http.StatusBadRequest, "Invalid policy ID", nil, nil)
+		c.JSON(http.StatusBadRequest, response)
+		return
+	}
+
+	var policy models.SLAPolicy
+	if err := c.ShouldBindJSON(&policy); err != nil {
+		response := helpers.NewResponse(http.StatusBadRequest, "Invalid request body", nil, nil)
+		c.JSON(http.StatusBadRequest, response)
+		return
+	}
+
+	policy.ID = id
+	if err := r.Service.UpdateSLAPolicy(&policy); err != nil {
+		response := helpers.NewResponse(http.StatusBadRequest, "Failed to update SLA policy: "+err.Error(), nil, nil)
+		c.JSON(http.StatusBadRequest, response)
+		return
+	}
+
+	response := helpers.NewResponse(http.StatusOK, "SLA policy updated successfully", nil, policy)
+	c.JSON(http.StatusOK, response)
+}
+
+// DeleteSLAPolicy godoc
+// @Summary Delete an SLA policy
+// @Description Delete an SLA policy by its ID
+// @Tags sla-policies
+// @Security BearerAuth
+// @Produce json
+// @Param id path int true "Policy ID"
+// @Success 200 {object} helpers.Response
+// @Router /sla-policies/{id} [delete]
+func (r *appRoute) deleteSLAPolicy(c *gin.Context) {
+	id, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		response := helpers.NewResponse(http.StatusBadRequest, "Invalid policy ID", nil, nil)
+		c.JSON(http.StatusBadRequest, response)
+		return
+	}
+
+	if err := r.Service.DeleteSLAPolicy(id); err != nil {
+		response := helpers.NewResponse(http.StatusInternalServerError, "Failed to delete SLA policy", nil, nil)
+		c.JSON(http.StatusInternalServerError, response)
+		return
+	}
+
+	response := helpers.NewResponse(http.StatusOK, "SLA policy deleted successfully", nil, nil)
+	c.JSON(http.StatusOK, response)
+}
@@ -0,0 +1,356 @@
+package handlers
+
+import (
+	"app/app/middleware"
+	"app/domain"
+	"app/domain/models"
+	"app/domain/requests"
+	"app/helpers"
+	"errors"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+)
+
+func (r *appRoute) TagRoutes(rg *gin.RouterGroup) {
+	api := rg.Group("/tags")
+	api.POST("", r.Middleware.Auth(), r.Middleware.RequireRole(models.RoleAdmin), r.createTag)
+	api.GET("", r.getTags)
+	api.GET("/:id", r.getTagByID)
+	api.PUT("/:id", r.Middleware.Auth(), r.Middleware.RequireRole(models.RoleAdmin), r.updateTag)
+	api.DELETE("/:id", r.Middleware.Auth(), r.Middleware.RequireRole(models.RoleAdmin), r.deleteTag)
+
+	tickets := rg.Group("/tickets/:id/tags")
+	tickets.Use(r.Middleware.Auth(), r.Middleware.RequireAdminOrSupport(), r.Middleware.RequireTicketAccess(middleware.TicketFromParam("id")))
+	tickets.POST("", r.addTicketTag)
+	tickets.DELETE("/:tag_id", r.removeTicketTag)
+
+	conversations := rg.Group("/conversations/:id/tags")
+	conversations.Use(r.Middleware.Auth(), r.Middleware.RequireAdminOrSupport())
+	conversations.POST("", r.addConversationTag)
+	conversations.DELETE("/:tag_id", r.removeConversationTag)
+}
+
+// CreateTag godoc
+// @Summary Create a new tag
+// @Description Create a new tag for tickets and conversations
+// @Tags tags
+// @Accept json
+// @Security BearerAuth
+// @Produce json
+// @Param tag body models.Tag true "Tag Data"
+// @Success 201 {object} helpers.Response{data=models.Tag}
+// @Failure 400 {object} helpers.Response
+// @Router /tags [post]
+func (r *appRoute) createTag(c *gin.Context) {
+	var tag models.Tag
+	if err := c.ShouldBindJSON(&tag); err != nil {
+		response := helpers.NewResponse(http.StatusBadRequest, "Invalid request body", nil, nil)
+		c.JSON(http.StatusBadRequest, response)
+		return
+	}
+
+	tag.ID = 0
+	if err := r.Service.CreateTag(&tag); err != nil {
+		status := serviceErrorStatus(err, http.StatusBadRequest)
+		response := helpers.NewResponse(status, "Failed to create tag: "+err.Error(), validationFields(err), nil)
+		c.JSON(status, response)
+		return
+	}
+
+	response := helpers.NewResponse(http.StatusCreated, "Tag created successfully", nil, tag)
+	c.JSON(http.StatusCreated, response)
+}
+
+// GetTags godoc
+// @Summary Get all tags
+// @Description Get a list of all tags
+// @Tags tags
+// @Produce json
+// @Success 200 {object} helpers.Response{data=[]models.Tag}
+// @Router /tags [get]
+func (r *appRoute) getTags(c *gin.Context) {
+	tags, err := r.Service.GetTags()
+	if err != nil {
+		response := helpers.NewResponse(http.StatusInternalServerError, "Failed to get tags", nil, nil)
+		c.JSON(http.StatusInternalServerError, response)
+		return
+	}
+
+	response := helpers.NewResponse(http.StatusOK, "Tags retrieved successfully", nil, tags)
+	c.JSON(http.StatusOK, response)
+}
+
+// GetTagByID godoc
+// @Summary Get a tag by ID
+// @Description Get a tag by its ID
+// @Tags tags
+// @Produce json
+// @Param id path int true "Tag ID"
+// @Success 200 {object} helpers.Response{data=models.Tag}
+// @Failure 404 {object} helpers.Response
+// @Router /tags/{id} [get]
+func (r *appRoute) getTagByID(c *gin.Context) {
+	id, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		response := helpers.NewResponse(http.StatusBadRequest, "Invalid tag ID", nil, nil)
+		c.JSON(http.StatusBadRequest, response)
+		return
+	}
+
+	tag, err := r.Service.GetTagByID(id)
+	if err != nil {
+		response := helpers.NewResponse(http.StatusNotFound, "Tag not found", nil, nil)
+		c.JSON(http.StatusNotFound, response)
+		return
+	}
+
+	response := helpers.NewResponse(http.StatusOK, "Tag retrieved successfully", nil, tag)
+	c.JSON(http.StatusOK, response)
+}
+
+// UpdateTag godoc
+// @Summary Update a tag
+// @Description Update a tag by its ID
+// @Tags tags
+// @Accept json
+// @Security BearerAuth
+// @Produce json
+// @Param id path int true "Tag ID"
+// @Param tag body models.Tag true "Updated Tag Data"
+// @Success 200 {object} helpers.Response{data=models.Tag}
+// @Failure 400 {object} helpers.Response
+// @Router /tags/{id} [put]
+func (r *appRoute) updateTag(c *gin.Context) {
+	id, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		response := helpers.NewResponse(http.StatusBadRequest, "Invalid tag ID", nil, nil)
+		c.JSON(http.StatusBadRequest, response)
+		return
+	}
+
+	var tag models.Tag
+	if err := c.ShouldBindJSON(&tag); err != nil {
+		response := helpers.NewResponse(http.StatusBadRequest, "Invalid request body", nil, nil)
+		c.JSON(http.StatusBadRequest, response)
+		return
+	}
+
+	tag.ID = id
+	if err := r.Service.UpdateTag(&tag); err != nil {
+		status := serviceErrorStatus(err, http.StatusBadRequest)
+		response := helpers.NewResponse(status, "Failed to update tag: "+err.Error(), validationFields(err), nil)
+		c.JSON(status, response)
+		return
+	}
+
+	response := helpers.NewResponse(http.StatusOK, "Tag updated successfully", nil, tag)
+	c.JSON(http.StatusOK, response)
+}
+
+// DeleteTag godoc
+// @Summary Delete a tag
+// @Description Delete a tag and remove it from every ticket and conversation
+// @Tags tags
+// @Security BearerAuth
+// @Produce json
+// @Param id path int true "Tag ID"
+// @Success 200 {object} helpers.Response
+// @Router /tags/{id} [delete]
+func (r *appRoute) deleteTag(c *gin.Context) {
+	id, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		response := helpers.NewResponse(http.StatusBadRequest, "Invalid tag ID", nil, nil)
+		c.JSON(http.StatusBadRequest, response)
+		return
+	}
+
+	if err := r.Service.DeleteTag(id); err != nil {
+		response := helpers.NewResponse(http.StatusInternalServerError, "Failed to delete tag", nil, nil)
+		c.JSON(http.StatusInternalServerError, response)
+		return
+	}
+
+	response := helpers.NewResponse(http.StatusOK, "Tag deleted successfully", nil, nil)
+	c.JSON(http.StatusOK, response)
+}
+
+// AddTicketTag godoc
+// @Summary Tag a ticket
+// @Description Add a tag to a ticket (Admin and Support only)
+// @Tags tags
+// @Accept json
+// @Security BearerAuth
+// @Produce json
+// @Param id path int true "Ticket ID"
+// @Param tag body requests.TagAssignRequest true "Tag"
+// @Success 200 {object} helpers.Response{data=models.Tag}
+// @Failure 404 {object} helpers.Response
+// @Router /tickets/{id}/tags [post]
+func (r *appRoute) addTicketTag(c *gin.Context) {
+	id, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		response := helpers.NewResponse(http.StatusBadRequest, "Invalid ticket ID", nil, nil)
+		c.JSON(http.StatusBadRequest, response)
+		return
+	}
+
+	var req requests.TagAssignRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		response := helpers.NewResponse(http.StatusBadRequest, "Invalid request body", nil, nil)
+		c.JSON(http.StatusBadRequest, response)
+		return
+	}
+
+	user, _ := c.Get("userData")
+	claim, _ := user.(models.User)
+
+	tag, err := r.Service.AddTicketTag(id, req.TagID, claim)
+	if err != nil {
+		status := serviceErrorStatus(err, http.StatusInternalServerError)
+		response := helpers.NewResponse(status, "Failed to tag ticket: "+err.Error(), nil, nil)
+		c.JSON(status, response)
+		return
+	}
+
+	response := helpers.NewResponse(http.StatusOK, "Tag added to ticket successfully", nil, tag)
+	c.JSON(http.StatusOK, response)
+}
+
+// RemoveTicketTag godoc
+// @Summary Untag a ticket
+// @Description Remove a tag from a ticket (Admin and Support only)
+// @Tags tags
+// @Security BearerAuth
+// @Produce json
+// @Param id path int true "Ticket ID"
+// @Param tag_id path int true "Tag ID"
+// @Success 200 {object} helpers.Response
+// @Router /tickets/{id}/tags/{tag_id} [delete]
+func (r *appRoute) removeTicketTag(c *gin.Context) {
+	id, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		response := helpers.NewResponse(http.StatusBadRequest, "Invalid ticket ID", nil, nil)
+		c.JSON(http.StatusBadRequest, response)
+		return
+	}
+	tagID, err := strconv.Atoi(c.Param("tag_id"))
+	if err != nil {
+		response := helpers.NewResponse(http.StatusBadRequest, "Invalid tag ID", nil, nil)
+		c.JSON(http.StatusBadRequest, response)
+		return
+	}
+
+	user, _ := c.Get("userData")
+	claim, _ := user.(models.User)
+
+	if err := r.Service.RemoveTicketTag(id, tagID, claim); err != nil {
+		status := serviceErrorStatus(err, http.StatusInternalServerError)
+		response := helpers.NewResponse(status, "Failed to untag ticket: "+err.Error(), nil, nil)
+		c.JSON(status, response)
+		return
+	}
+
+	response := helpers.NewResponse(http.StatusOK, "Tag removed from ticket successfully", nil, nil)
+	c.JSON(http.StatusOK, response)
+}
+
+// AddConversationTag godoc
+// @Summary Tag a conversation
+// @Description Add a tag to a conversation (Admin and Support only)
+// @Tags tags
+// @Accept json
+// @Security BearerAuth
+// @Produce json
+// @Param id path int true "Conversation ID"
+// @Param tag body requests.TagAssignRequest true "Tag"
+// @Success 200 {object} helpers.Response{data=models.Tag}
+// @Failure 404 {object} helpers.Response
+// @Router /conversations/{id}/tags [post]
+func (r *appRoute) addConversationTag(c *gin.Context) {
+	conversationID, err := strconv.ParseUint(c.Param("id"), 10, 64)
+	if err != nil {
+		response := helpers.NewResponse(http.StatusBadRequest, "Invalid conversation ID", nil, nil)
+		c.JSON(http.StatusBadRequest, response)
+		return
+	}
+
+	var req requests.TagAssignRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		response := helpers.NewResponse(http.StatusBadRequest, "Invalid request body", nil, nil)
+		c.JSON(http.StatusBadRequest, response)
+		return
+	}
+
+	tag, err := r.Service.AddConversationTag(conversationID, req.TagID)
+	if err != nil {
+		status := serviceErrorStatus(err, http.StatusInternalServerError)
+		response := helpers.NewResponse(status, "Failed to tag conversation: "+err.Error(), nil, nil)
+		c.JSON(status, response)
+		return
+	}
+
+	response := helpers.NewResponse(http.StatusOK, "Tag added to conversation successfully", nil, tag)
+	c.JSON(http.StatusOK, response)
+}
+
+// RemoveConversationTag godoc
+// @Summary Untag a conversation
+// @Description Remove a tag from a conversation (Admin and Support only)
+// @Tags tags
+// @Security BearerAuth
+// @Produce json
+// @Param id path int true "Conversation ID"
+// @Param tag_id path int true "Tag ID"
+// @Success 200 {object} helpers.Response
+// @Router /conversations/{id}/tags/{tag_id} [delete]
+func (r *appRoute) removeConversationTag(c *gin.Context) {
+	conversationID, err := strconv.ParseUint(c.Param("id"), 10, 64)
+	if err != nil {
+		response := helpers.NewResponse(http.StatusBadRequest, "Invalid conversation ID", nil, nil)
+		c.JSON(http.StatusBadRequest, response)
+		return
+	}
+	tagID, err := strconv.Atoi(c.Param("tag_id"))
+	if err != nil {
+		response := helpers.NewResponse(http.StatusBadRequest, "Invalid tag ID", nil, nil)
+		c.JSON(http.StatusBadRequest, response)
+		return
+	}
+
+	if err := r.Service.RemoveConversationTag(conversationID, tagID); err != nil {
+		response := helpers.NewResponse(http.StatusInternalServerError, "Failed to untag conversation", nil, nil)
+		c.JSON(http.StatusInternalServerError, response)
+		return
+	}
+
+	response := helpers.NewResponse(http.StatusOK, "Tag removed from conversation successfully", nil, nil)
+	c.JSON(http.StatusOK, response)
+}
+
+// tagFilterFromQuery reads the tags filter, a comma separated list of tag IDs matched
+// with any (default) or all semantics through tags_mode
+func tagFilterFromQuery(c *gin.Context) (domain.TagFilter, error) {
+	var filter domain.TagFilter
+	if raw := c.Query("tags"); raw != "" {
+		for _, part := range strings.Split(raw, ",") {
+			tagID, err := strconv.Atoi(strings.TrimSpace(part))
+			if err != nil || tagID <= 0 {
+				return filter, errors.New("Invalid tags filter, use a comma separated list of tag IDs")
+			}
+			filter.TagIDs = append(filter.TagIDs, tagID)
+		}
+	}
+
+	switch c.DefaultQuery("tags_mode", "any") {
+	case "any":
+	case "all":
+		filter.MatchAll = true
+	default:
+		return filter, errors.New("Invalid tags_mode, use any or all")
+	}
+
+	return filter, nil
+}
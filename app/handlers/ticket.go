@@ -1,11 +1,17 @@
 package handlers
 
 import (
+	"app/app/middleware"
+	"app/domain"
 	"app/domain/models"
 	"app/domain/requests"
 	"app/helpers"
+	"errors"
+	"fmt"
 	"net/http"
 	"strconv"
+	"strings"
+	"time"
 
 	"github.com/gin-gonic/gin"
 )
@@ -13,22 +19,36 @@ import (
 func (r *appRoute) TicketRoutes(rg *gin.RouterGroup) {
 	api := rg.Group("/tickets")
 
-	// Public endpoints (no auth required)
-	api.GET("/:id", r.getTicketByID)
-
 	// Authenticated user endpoints
 	api.POST("", r.Middleware.Auth(), r.createTicket)
 	api.GET("/my-tickets", r.Middleware.Auth(), r.getMyTickets)
-	api.PUT("/:id", r.Middleware.Auth(), r.updateTicket)
-	api.DELETE("/:id", r.Middleware.Auth(), r.deleteTicket)
+	api.GET("/search", r.Middleware.Auth(), r.Middleware.RequireAdminOrSupport(), r.searchTickets)
+	api.GET("/code/:code", r.Middleware.Auth(), r.getTicketByCode)
+	api.POST("/bulk", r.Middleware.Auth(), r.Middleware.RequireAdminOrSupport(), r.bulkUpdateTickets)
+
+	// Ticket owner, assignee or admin endpoints
+	ticketAccess := r.Middleware.RequireTicketAccess(middleware.TicketFromParam("id"))
+	api.GET("/:id", r.Middleware.Auth(), ticketAccess, r.getTicketByID)
+	api.PUT("/:id", r.Middleware.Auth(), ticketAccess, r.updateTicket)
+	api.PATCH("/:id", r.Middleware.Auth(), ticketAccess, r.patchTicket)
+	api.DELETE("/:id", r.Middleware.Auth(), ticketAccess, r.deleteTicket)
+	api.POST("/:id/merge", r.Middleware.Auth(), r.Middleware.RequireAdminOrSupport(), ticketAccess, r.mergeTicket)
+	api.POST("/:id/reopen", r.Middleware.Auth(), ticketAccess, r.reopenTicket)
+	api.GET("/:id/timeline", r.Middleware.Auth(), ticketAccess, r.getTicketTimeline)
+	api.POST("/:id/snooze", r.Middleware.Auth(), r.Middleware.RequireAdminOrSupport(), ticketAccess, r.snoozeTicket)
+	api.POST("/:id/wake", r.Middleware.Auth(), r.Middleware.RequireAdminOrSupport(), ticketAccess, r.wakeTicket)
 
 	// Admin-only endpoints
 	api.GET("", r.Middleware.Auth(), r.Middleware.RequireRole(models.RoleAdmin), r.getTickets)
+	api.GET("/export", r.Middleware.Auth(), r.Middleware.RequireRole(models.RoleAdmin), r.exportTickets)
+	api.POST("/import", r.Middleware.Auth(), r.Middleware.RequireRole(models.RoleAdmin), r.importTickets)
+	api.GET("/trash", r.Middleware.Auth(), r.Middleware.RequireRole(models.RoleAdmin), r.getDeletedTickets)
+	api.POST("/:id/restore", r.Middleware.Auth(), r.Middleware.RequireRole(models.RoleAdmin), r.restoreTicket)
 }
 
 // CreateTicket godoc
 // @Summary Create a new ticket
-// @Description Create a new ticket (tipe_pengaduan will be auto-filled based on user role)
+// @Description Create a new ticket (tipe_pengaduan will be auto-filled based on user role). custom_fields are validated against the fields defined for the category.
 // @Tags tickets
 // @Security BearerAuth
 // @Accept json
@@ -72,11 +92,22 @@ func (r *appRoute) createTicket(c *gin.Context) {
 		Deskripsi:     req.Deskripsi,
 		CategoryID:    req.CategoryID,
 		PriorityID:    3, // Default priority ID
-		StatusID:      1, // Default status ID
+		// StatusID is set to the initial workflow status in service
 		TipePengaduan: tipePengaduan,
+		CustomFields:  req.CustomFields,
 	}
 
 	if err := r.Service.CreateTicket(&ticket); err != nil {
+		if fields := validationFields(err); fields != nil {
+			response := helpers.NewResponse(http.StatusBadRequest, "Invalid custom fields", fields, nil)
+			c.JSON(http.StatusBadRequest, response)
+			return
+		}
+		if status := serviceErrorStatus(err, http.StatusInternalServerError); status != http.StatusInternalServerError {
+			response := helpers.NewResponse(status, err.Error(), nil, nil)
+			c.JSON(status, response)
+			return
+		}
 		response := helpers.NewResponse(http.StatusInternalServerError, "Failed to create ticket", nil, nil)
 		c.JSON(http.StatusInternalServerError, response)
 		return
@@ -94,6 +125,7 @@ func (r *appRoute) createTicket(c *gin.Context) {
 		TipePengaduan:     ticket.TipePengaduan,
 		TanggalDibuat:     ticket.TanggalDibuat.Format("2006-01-02T15:04:05Z07:00"),
 		TanggalDiperbarui: ticket.TanggalDiperbarui.Format("2006-01-02T15:04:05Z07:00"),
+		CustomFields:      ticket.CustomFields,
 	}
 
 	response := helpers.NewResponse(http.StatusCreated, "Ticket created successfully", nil, resp)
@@ -106,10 +138,18 @@ func (r *appRoute) createTicket(c *gin.Context) {
 // @Tags tickets
 // @Produce json
 // @Security BearerAuth
+// @Param code query string false "Filter by ticket code prefix, e.g. CUS-2026"
 // @Param role query string false "Filter by tipe_pengaduan (customer, seller, admin, support)"
 // @Param status query int false "Filter by status ID"
 // @Param priority query int false "Filter by priority ID"
 // @Param category query int false "Filter by category ID"
+// @Param min_reopen_count query int false "Filter by tickets reopened at least this many times"
+// @Param include_pending query bool false "Include snoozed tickets, left out unless filtering by status"
+// @Param sla query string false "Filter by SLA state (at_risk, breached)"
+// @Param tags query string false "Filter by comma separated tag IDs"
+// @Param tags_mode query string false "Match any (default) or all of the tags"
+// @Param cf.key query string false "Filter by a custom field value, e.g. cf.nomor_pesanan=INV-001"
+// @Param sort query string false "Order of the list (newest, oldest, updated), default newest"
 // @Param limit query int false "Items per page (default: 10)"
 // @Param cursor query string false "Cursor for next page"
 // @Success 200 {object} helpers.Response{data=requests.TicketListResponse}
@@ -120,15 +160,25 @@ func (r *appRoute) getTickets(c *gin.Context) {
 		limit = 10
 	}
 	cursor := c.Query("cursor")
-	filterType := c.Query("role")
 
-	// Get filter params for status, priority, and category
-	statusID, _ := strconv.Atoi(c.Query("status"))
-	priorityID, _ := strconv.Atoi(c.Query("priority"))
-	categoryID, _ := strconv.Atoi(c.Query("category"))
+	filter, err := ticketFilterFromQuery(c)
+	if err != nil {
+		response := helpers.NewResponse(http.StatusBadRequest, err.Error(), nil, nil)
+		c.JSON(http.StatusBadRequest, response)
+		return
+	}
+
+	switch sort := models.TicketSort(c.DefaultQuery("sort", string(models.TicketSortNewest))); sort {
+	case models.TicketSortNewest, models.TicketSortOldest, models.TicketSortUpdated:
+		filter.Sort = sort
+	default:
+		response := helpers.NewResponse(http.StatusBadRequest, "Invalid sort, use newest, oldest or updated", nil, nil)
+		c.JSON(http.StatusBadRequest, response)
+		return
+	}
 
 	// Call service with all filters - filtering happens at DB level
-	tickets, nextCursor, err := r.Service.GetTicketsCursor(limit, cursor, filterType, statusID, priorityID, categoryID)
+	tickets, nextCursor, err := r.Service.GetTicketsCursor(limit, cursor, filter)
 	if err != nil {
 		response := helpers.NewResponse(500, "Failed to get tickets", nil, nil)
 		c.JSON(500, response)
@@ -138,24 +188,7 @@ func (r *appRoute) getTickets(c *gin.Context) {
 	// No need to filter here anymore - already filtered by database
 	var resp []requests.TicketResponse
 	for _, ticket := range tickets {
-		username := ""
-		if ticket.User.Username != "" {
-			username = ticket.User.Username
-		}
-		resp = append(resp, requests.TicketResponse{
-			ID:                ticket.ID,
-			KodeTiket:         ticket.KodeTiket,
-			UserID:            ticket.UserID,
-			Username:          username,
-			Judul:             ticket.Judul,
-			Deskripsi:         ticket.Deskripsi,
-			CategoryID:        ticket.CategoryID,
-			PriorityID:        ticket.PriorityID,
-			StatusID:          ticket.StatusID,
-			TipePengaduan:     ticket.TipePengaduan,
-			TanggalDibuat:     ticket.TanggalDibuat.Format("2006-01-02T15:04:05Z07:00"),
-			TanggalDiperbarui: ticket.TanggalDiperbarui.Format("2006-01-02T15:04:05Z07:00"),
-		})
+		resp = append(resp, mapTicketResponse(&ticket))
 	}
 
 	responseData := map[string]interface{}{
@@ -171,7 +204,7 @@ func (r *appRoute) getTickets(c *gin.Context) {
 
 // GetTicketByID godoc
 // @Summary Get a ticket by ID
-// @Description Get a ticket by its ID
+// @Description Get a ticket by its ID with its linked tickets, a merged ticket returns the ticket it was merged into
 // @Tags tickets
 // @Produce json
 // @Param id path int true "Ticket ID"
@@ -192,40 +225,68 @@ func (r *appRoute) getTicketByID(c *gin.Context) {
 		return
 	}
 
-	username := ""
-	if ticket.User.Username != "" {
-		username = ticket.User.Username
+	resp := mapTicketResponse(ticket)
+	if links, err := r.Service.GetTicketLinks(ticket.ID); err == nil {
+		resp.Links = mapTicketLinks(ticket.ID, links)
 	}
 
-	resp := requests.TicketResponse{
-		ID:                ticket.ID,
-		KodeTiket:         ticket.KodeTiket,
-		UserID:            ticket.UserID,
-		Username:          username,
-		Judul:             ticket.Judul,
-		Deskripsi:         ticket.Deskripsi,
-		CategoryID:        ticket.CategoryID,
-		PriorityID:        ticket.PriorityID,
-		StatusID:          ticket.StatusID,
-		TipePengaduan:     ticket.TipePengaduan,
-		TanggalDibuat:     ticket.TanggalDibuat.Format("2006-01-02T15:04:05Z07:00"),
-		TanggalDiperbarui: ticket.TanggalDiperbarui.Format("2006-01-02T15:04:05Z07:00"),
+	// Merged tickets resolve to the surviving ticket
+	message := "Ticket retrieved successfully"
+	if ticket.ID != id {
+		message = fmt.Sprintf("Ticket %d was merged into %s", id, ticket.KodeTiket)
 	}
 
+	setETag(c, ticket.Version)
+	response := helpers.NewResponse(http.StatusOK, message, nil, resp)
+	c.JSON(http.StatusOK, response)
+}
+
+// GetTicketByCode godoc
+// @Summary Get a ticket by code
+// @Description Get a ticket by its code such as CUS-2026-000123, case-insensitive. A merged ticket returns the ticket it was merged into.
+// @Tags tickets
+// @Security BearerAuth
+// @Produce json
+// @Param code path string true "Ticket code"
+// @Success 200 {object} helpers.Response{data=requests.TicketResponse}
+// @Failure 403 {object} helpers.Response
+// @Failure 404 {object} helpers.Response
+// @Router /tickets/code/{code} [get]
+func (r *appRoute) getTicketByCode(c *gin.Context) {
+	ticket, err := r.Service.GetTicketByCode(c.Param("code"))
+	if err != nil {
+		response := helpers.NewResponse(http.StatusNotFound, "Ticket not found", nil, nil)
+		c.JSON(http.StatusNotFound, response)
+		return
+	}
+
+	// Only the owner, assignee or an admin may see the ticket
+	if !r.Middleware.AuthorizeTicket(c, ticket.ID) {
+		return
+	}
+
+	resp := mapTicketResponse(ticket)
+	if links, err := r.Service.GetTicketLinks(ticket.ID); err == nil {
+		resp.Links = mapTicketLinks(ticket.ID, links)
+	}
+
+	setETag(c, ticket.Version)
 	response := helpers.NewResponse(http.StatusOK, "Ticket retrieved successfully", nil, resp)
 	c.JSON(http.StatusOK, response)
 }
 
 // UpdateTicket godoc
 // @Summary Update a ticket
-// @Description Update a ticket by its ID (tipe_pengaduan will be auto-filled based on user role)
+// @Description Update a ticket by its ID. id_user and tipe_pengaduan are kept from the ticket creation and ignored here, kode_tiket cannot be changed and may be left empty.
 // @Tags tickets
 // @Security BearerAuth
 // @Accept json
 // @Produce json
 // @Param id path int true "Ticket ID"
+// @Param If-Match header string false "ETag of the ticket the change is based on"
 // @Param ticket body requests.TicketCreateRequest true "Updated Ticket Data"
 // @Success 200 {object} helpers.Response{data=requests.TicketResponse}
+// @Failure 409 {object} helpers.Response{data=requests.TicketResponse} "The ticket changed, data holds the current ticket"
 // @Router /tickets/{id} [put]
 func (r *appRoute) updateTicket(c *gin.Context) {
 	id, err := strconv.Atoi(c.Param("id"))
@@ -242,39 +303,40 @@ func (r *appRoute) updateTicket(c *gin.Context) {
 		return
 	}
 
-	// Ambil user dari context
-	userData, exists := c.Get("userData")
-	var tipePengaduan models.UserRole
-	var userID uint64
-	if exists {
-		user, ok := userData.(models.User)
-		if ok && user.Role != "" {
-			tipePengaduan = user.Role
-			userID = user.ID
-		} else {
-			// fallback ke default customer jika role kosong
-			tipePengaduan = models.RoleCustomer
-			userID = req.UserID
-		}
-	} else {
-		// fallback jika tidak ada user di context - default customer
-		tipePengaduan = models.RoleCustomer
-		userID = req.UserID
+	version, err := ifMatchVersion(c)
+	if err != nil {
+		response := helpers.NewResponse(http.StatusBadRequest, err.Error(), nil, nil)
+		c.JSON(http.StatusBadRequest, response)
+		return
 	}
 
+	user, _ := c.Get("userData")
+	claim, _ := user.(models.User)
+
+	// The owner and tipe_pengaduan are set when the ticket is created and never change
 	ticket := models.Ticket{
-		ID:            id,
-		KodeTiket:     req.KodeTiket,
-		UserID:        userID,
-		Judul:         req.Judul,
-		Deskripsi:     req.Deskripsi,
-		CategoryID:    req.CategoryID,
-		PriorityID:    req.PriorityID,
-		StatusID:      req.StatusID,
-		TipePengaduan: tipePengaduan,
+		ID:           id,
+		KodeTiket:    req.KodeTiket,
+		Judul:        req.Judul,
+		Deskripsi:    req.Deskripsi,
+		CategoryID:   req.CategoryID,
+		PriorityID:   req.PriorityID,
+		StatusID:     req.StatusID,
+		CustomFields: req.CustomFields,
+		Version:      version,
 	}
 
-	if err := r.Service.UpdateTicket(&ticket); err != nil {
+	if err := r.Service.UpdateTicket(&ticket, claim); err != nil {
+		if errors.Is(err, domain.ErrVersionConflict) {
+			r.respondTicketConflict(c, id, err)
+			return
+		}
+		status := serviceErrorStatus(err, http.StatusInternalServerError)
+		if status != http.StatusInternalServerError {
+			response := helpers.NewResponse(status, err.Error(), validationFields(err), nil)
+			c.JSON(status, response)
+			return
+		}
 		response := helpers.NewResponse(http.StatusInternalServerError, "Failed to update ticket", nil, nil)
 		c.JSON(http.StatusInternalServerError, response)
 		return
@@ -292,15 +354,94 @@ func (r *appRoute) updateTicket(c *gin.Context) {
 		TipePengaduan:     ticket.TipePengaduan,
 		TanggalDibuat:     ticket.TanggalDibuat.Format("2006-01-02T15:04:05Z07:00"),
 		TanggalDiperbarui: ticket.TanggalDiperbarui.Format("2006-01-02T15:04:05Z07:00"),
+		CustomFields:      ticket.CustomFields,
+		Version:           ticket.Version,
+		ReopenCount:       ticket.ReopenCount,
+		PendingUntil:      ticket.PendingUntil,
+		PendingReason:     ticket.PendingReason,
 	}
 
+	setETag(c, ticket.Version)
 	response := helpers.NewResponse(http.StatusOK, "Ticket updated successfully", nil, resp)
 	c.JSON(http.StatusOK, response)
 }
 
+// PatchTicket godoc
+// @Summary Partially update a ticket
+// @Description Apply a JSON merge patch (RFC 7386) to a ticket: only the fields present change and null resets a field. Patchable fields are judul, deskripsi, id_category, id_priority, id_status and custom_fields. Every changed field is recorded in the ticket log.
+// @Tags tickets
+// @Security BearerAuth
+// @Accept json
+// @Produce json
+// @Param id path int true "Ticket ID"
+// @Param If-Match header string false "ETag of the ticket the change is based on"
+// @Param patch body object true "Fields to change"
+// @Success 200 {object} helpers.Response{data=requests.TicketResponse}
+// @Failure 400 {object} helpers.Response
+// @Failure 404 {object} helpers.Response
+// @Failure 409 {object} helpers.Response{data=requests.TicketResponse} "The ticket changed, data holds the current ticket"
+// @Router /tickets/{id} [patch]
+func (r *appRoute) patchTicket(c *gin.Context) {
+	id, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		response := helpers.NewResponse(http.StatusBadRequest, "Invalid ticket ID", nil, nil)
+		c.JSON(http.StatusBadRequest, response)
+		return
+	}
+
+	patch, err := c.GetRawData()
+	if err != nil {
+		response := helpers.NewResponse(http.StatusBadRequest, "Invalid request body", nil, nil)
+		c.JSON(http.StatusBadRequest, response)
+		return
+	}
+
+	version, err := ifMatchVersion(c)
+	if err != nil {
+		response := helpers.NewResponse(http.StatusBadRequest, err.Error(), nil, nil)
+		c.JSON(http.StatusBadRequest, response)
+		return
+	}
+
+	user, _ := c.Get("userData")
+	claim, _ := user.(models.User)
+
+	ticket, err := r.Service.PatchTicket(id, patch, version, claim)
+	if err != nil {
+		if errors.Is(err, domain.ErrVersionConflict) {
+			r.respondTicketConflict(c, id, err)
+			return
+		}
+		status := serviceErrorStatus(err, http.StatusInternalServerError)
+		if status != http.StatusInternalServerError {
+			response := helpers.NewResponse(status, err.Error(), validationFields(err), nil)
+			c.JSON(status, response)
+			return
+		}
+		response := helpers.NewResponse(http.StatusInternalServerError, "Failed to update ticket", nil, nil)
+		c.JSON(http.StatusInternalServerError, response)
+		return
+	}
+
+	setETag(c, ticket.Version)
+	response := helpers.NewResponse(http.StatusOK, "Ticket updated successfully", nil, mapTicketResponse(ticket))
+	c.JSON(http.StatusOK, response)
+}
+
+// respondTicketConflict answers an outdated ticket update with the current ticket
+func (r *appRoute) respondTicketConflict(c *gin.Context, id int, err error) {
+	current, getErr := r.Service.GetTicketByID(id)
+	if getErr != nil {
+		response := helpers.NewResponse(http.StatusConflict, err.Error(), nil, nil)
+		c.JSON(http.StatusConflict, response)
+		return
+	}
+	respondVersionConflict(c, err, current.Version, mapTicketResponse(current))
+}
+
 // DeleteTicket godoc
 // @Summary Delete a ticket
-// @Description Delete a ticket by its ID
+// @Description Move a ticket to the trash. It is hidden from every list and detail query, restorable by an admin and purged after the retention period.
 // @Tags tickets
 // @Produce json
 // @Param id path int true "Ticket ID"
@@ -314,7 +455,16 @@ func (r *appRoute) deleteTicket(c *gin.Context) {
 		return
 	}
 
-	if err := r.Service.DeleteTicket(id); err != nil {
+	user, _ := c.Get("userData")
+	claim, _ := user.(models.User)
+
+	if err := r.Service.DeleteTicket(id, claim); err != nil {
+		status := serviceErrorStatus(err, http.StatusInternalServerError)
+		if status != http.StatusInternalServerError {
+			response := helpers.NewResponse(status, err.Error(), nil, nil)
+			c.JSON(status, response)
+			return
+		}
 		response := helpers.NewResponse(http.StatusInternalServerError, "Failed to delete ticket", nil, nil)
 		c.JSON(http.StatusInternalServerError, response)
 		return
@@ -324,6 +474,404 @@ func (r *appRoute) deleteTicket(c *gin.Context) {
 	c.JSON(http.StatusOK, response)
 }
 
+// GetDeletedTickets godoc
+// @Summary Get deleted tickets
+// @Description Get the tickets in the trash, most recently deleted first (Admin only)
+// @Tags tickets
+// @Security BearerAuth
+// @Produce json
+// @Success 200 {object} helpers.Response{data=[]requests.TicketResponse}
+// @Router /tickets/trash [get]
+func (r *appRoute) getDeletedTickets(c *gin.Context) {
+	tickets, err := r.Service.GetDeletedTickets()
+	if err != nil {
+		response := helpers.NewResponse(http.StatusInternalServerError, "Failed to get deleted tickets", nil, nil)
+		c.JSON(http.StatusInternalServerError, response)
+		return
+	}
+
+	resp := make([]requests.TicketResponse, 0, len(tickets))
+	for i := range tickets {
+		resp = append(resp, mapTicketResponse(&tickets[i]))
+	}
+
+	response := helpers.NewResponse(http.StatusOK, "Deleted tickets retrieved successfully", nil, resp)
+	c.JSON(http.StatusOK, response)
+}
+
+// RestoreTicket godoc
+// @Summary Restore a deleted ticket
+// @Description Bring a ticket back from the trash (Admin only)
+// @Tags tickets
+// @Security BearerAuth
+// @Produce json
+// @Param id path int true "Ticket ID"
+// @Success 200 {object} helpers.Response{data=requests.TicketResponse}
+// @Failure 404 {object} helpers.Response
+// @Router /tickets/{id}/restore [post]
+func (r *appRoute) restoreTicket(c *gin.Context) {
+	id, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		response := helpers.NewResponse(http.StatusBadRequest, "Invalid ticket ID", nil, nil)
+		c.JSON(http.StatusBadRequest, response)
+		return
+	}
+
+	user, _ := c.Get("userData")
+	claim, _ := user.(models.User)
+
+	ticket, err := r.Service.RestoreTicket(id, claim)
+	if err != nil {
+		status := serviceErrorStatus(err, http.StatusInternalServerError)
+		if status != http.StatusInternalServerError {
+			response := helpers.NewResponse(status, err.Error(), nil, nil)
+			c.JSON(status, response)
+			return
+		}
+		response := helpers.NewResponse(http.StatusInternalServerError, "Failed to restore ticket", nil, nil)
+		c.JSON(http.StatusInternalServerError, response)
+		return
+	}
+
+	response := helpers.NewResponse(http.StatusOK, "Ticket restored successfully", nil, mapTicketResponse(ticket))
+	c.JSON(http.StatusOK, response)
+}
+
+// MergeTicket godoc
+// @Summary Merge a duplicate ticket
+// @Description Merge a duplicate ticket into this ticket (Admin and Support only). Comments, attachments, assignments and logs move to this ticket and the duplicate is closed with a reference to it.
+// @Tags tickets
+// @Security BearerAuth
+// @Accept json
+// @Produce json
+// @Param id path int true "Primary Ticket ID"
+// @Param merge body requests.TicketMergeRequest true "Duplicate ticket"
+// @Success 200 {object} helpers.Response{data=requests.TicketResponse}
+// @Failure 400 {object} helpers.Response
+// @Failure 409 {object} helpers.Response
+// @Router /tickets/{id}/merge [post]
+func (r *appRoute) mergeTicket(c *gin.Context) {
+	id, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		response := helpers.NewResponse(http.StatusBadRequest, "Invalid ticket ID", nil, nil)
+		c.JSON(http.StatusBadRequest, response)
+		return
+	}
+
+	var req requests.TicketMergeRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		response := helpers.NewResponse(http.StatusBadRequest, "Invalid request body", nil, nil)
+		c.JSON(http.StatusBadRequest, response)
+		return
+	}
+
+	// The duplicate is modified as well
+	if !r.Middleware.AuthorizeTicket(c, req.SecondaryTicketID) {
+		return
+	}
+
+	user, _ := c.Get("userData")
+	claim, _ := user.(models.User)
+
+	ticket, err := r.Service.MergeTickets(id, req.SecondaryTicketID, claim)
+	if err != nil {
+		status := serviceErrorStatus(err, http.StatusInternalServerError)
+		if status != http.StatusInternalServerError {
+			response := helpers.NewResponse(status, err.Error(), nil, nil)
+			c.JSON(status, response)
+			return
+		}
+		response := helpers.NewResponse(http.StatusInternalServerError, "Failed to merge tickets: "+err.Error(), nil, nil)
+		c.JSON(http.StatusInternalServerError, response)
+		return
+	}
+
+	response := helpers.NewResponse(http.StatusOK, "Tickets merged successfully", nil, mapTicketResponse(ticket))
+	c.JSON(http.StatusOK, response)
+}
+
+// ReopenTicket godoc
+// @Summary Reopen a resolved ticket
+// @Description Move a resolved or closed ticket back to open when its problem came back (Ticket owner only). The reason is logged, the current assignee is notified and the reopen counter of the ticket is raised.
+// @Tags tickets
+// @Security BearerAuth
+// @Accept json
+// @Produce json
+// @Param id path int true "Ticket ID"
+// @Param reopen body requests.TicketReopenRequest true "Reopen reason"
+// @Success 200 {object} helpers.Response{data=requests.TicketResponse}
+// @Failure 400 {object} helpers.Response
+// @Failure 403 {object} helpers.Response
+// @Failure 404 {object} helpers.Response
+// @Router /tickets/{id}/reopen [post]
+func (r *appRoute) reopenTicket(c *gin.Context) {
+	id, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		response := helpers.NewResponse(http.StatusBadRequest, "Invalid ticket ID", nil, nil)
+		c.JSON(http.StatusBadRequest, response)
+		return
+	}
+
+	var req requests.TicketReopenRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		response := helpers.NewResponse(http.StatusBadRequest, "Invalid request body, a reason is required", nil, nil)
+		c.JSON(http.StatusBadRequest, response)
+		return
+	}
+
+	user, _ := c.Get("userData")
+	claim, _ := user.(models.User)
+
+	ticket, err := r.Service.ReopenTicket(id, req.Reason, claim)
+	if err != nil {
+		status := serviceErrorStatus(err, http.StatusInternalServerError)
+		if status != http.StatusInternalServerError {
+			response := helpers.NewResponse(status, err.Error(), validationFields(err), nil)
+			c.JSON(status, response)
+			return
+		}
+		response := helpers.NewResponse(http.StatusInternalServerError, "Failed to reopen ticket", nil, nil)
+		c.JSON(http.StatusInternalServerError, response)
+		return
+	}
+
+	response := helpers.NewResponse(http.StatusOK, "Ticket reopened successfully", nil, mapTicketResponse(ticket))
+	c.JSON(http.StatusOK, response)
+}
+
+// SnoozeTicket godoc
+// @Summary Snooze a ticket
+// @Description Park a ticket in the pending status until pending_until while support waits on someone else (Admin and Support only). Pending tickets are left out of the queues and move back to open when the timer expires or the customer comments, the assignee is notified over the websocket.
+// @Tags tickets
+// @Security BearerAuth
+// @Accept json
+// @Produce json
+// @Param id path int true "Ticket ID"
+// @Param snooze body requests.TicketSnoozeRequest true "Wake-up time and reason"
+// @Success 200 {object} helpers.Response{data=requests.TicketResponse}
+// @Failure 400 {object} helpers.Response
+// @Failure 403 {object} helpers.Response
+// @Failure 404 {object} helpers.Response
+// @Router /tickets/{id}/snooze [post]
+func (r *appRoute) snoozeTicket(c *gin.Context) {
+	id, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		response := helpers.NewResponse(http.StatusBadRequest, "Invalid ticket ID", nil, nil)
+		c.JSON(http.StatusBadRequest, response)
+		return
+	}
+
+	var req requests.TicketSnoozeRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		response := helpers.NewResponse(http.StatusBadRequest, "Invalid request body, pending_until and reason are required", nil, nil)
+		c.JSON(http.StatusBadRequest, response)
+		return
+	}
+
+	user, _ := c.Get("userData")
+	claim, _ := user.(models.User)
+
+	ticket, err := r.Service.SnoozeTicket(id, req.PendingUntil, req.Reason, claim)
+	if err != nil {
+		status := serviceErrorStatus(err, http.StatusInternalServerError)
+		if status != http.StatusInternalServerError {
+			response := helpers.NewResponse(status, err.Error(), validationFields(err), nil)
+			c.JSON(status, response)
+			return
+		}
+		response := helpers.NewResponse(http.StatusInternalServerError, "Failed to snooze ticket", nil, nil)
+		c.JSON(http.StatusInternalServerError, response)
+		return
+	}
+
+	response := helpers.NewResponse(http.StatusOK, "Ticket snoozed successfully", nil, mapTicketResponse(ticket))
+	c.JSON(http.StatusOK, response)
+}
+
+// WakeTicket godoc
+// @Summary Wake a snoozed ticket
+// @Description Move a pending ticket back to open before its timer expires (Admin and Support only)
+// @Tags tickets
+// @Security BearerAuth
+// @Produce json
+// @Param id path int true "Ticket ID"
+// @Success 200 {object} helpers.Response{data=requests.TicketResponse}
+// @Failure 400 {object} helpers.Response
+// @Failure 403 {object} helpers.Response
+// @Failure 404 {object} helpers.Response
+// @Router /tickets/{id}/wake [post]
+func (r *appRoute) wakeTicket(c *gin.Context) {
+	id, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		response := helpers.NewResponse(http.StatusBadRequest, "Invalid ticket ID", nil, nil)
+		c.JSON(http.StatusBadRequest, response)
+		return
+	}
+
+	user, _ := c.Get("userData")
+	claim, _ := user.(models.User)
+
+	ticket, err := r.Service.WakeTicket(id, claim)
+	if err != nil {
+		status := serviceErrorStatus(err, http.StatusInternalServerError)
+		if status != http.StatusInternalServerError {
+			response := helpers.NewResponse(status, err.Error(), validationFields(err), nil)
+			c.JSON(status, response)
+			return
+		}
+		response := helpers.NewResponse(http.StatusInternalServerError, "Failed to wake ticket", nil, nil)
+		c.JSON(http.StatusInternalServerError, response)
+		return
+	}
+
+	response := helpers.NewResponse(http.StatusOK, "Ticket woken up successfully", nil, mapTicketResponse(ticket))
+	c.JSON(http.StatusOK, response)
+}
+
+// GetTicketTimeline godoc
+// @Summary Get the timeline of a ticket
+// @Description Get the comments, logs, attachments, assignments and status changes of a ticket as one stream, oldest first. Customers and sellers only see comments, attachments and status changes.
+// @Tags tickets
+// @Security BearerAuth
+// @Produce json
+// @Param id path int true "Ticket ID"
+// @Param type query string false "Comma separated event types (comment, log, attachment, assignment, status_change)"
+// @Param limit query int false "Items per page (default: 20)"
+// @Param cursor query string false "Cursor for next page"
+// @Success 200 {object} helpers.Response{data=[]domain.TimelineEvent}
+// @Failure 400 {object} helpers.Response
+// @Failure 404 {object} helpers.Response
+// @Router /tickets/{id}/timeline [get]
+func (r *appRoute) getTicketTimeline(c *gin.Context) {
+	id, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		response := helpers.NewResponse(http.StatusBadRequest, "Invalid ticket ID", nil, nil)
+		c.JSON(http.StatusBadRequest, response)
+		return
+	}
+
+	limit, _ := strconv.Atoi(c.DefaultQuery("limit", "20"))
+	if limit < 1 || limit > 100 {
+		limit = 20
+	}
+	cursor := c.Query("cursor")
+
+	var types []domain.TimelineEventType
+	for _, eventType := range strings.Split(c.Query("type"), ",") {
+		if eventType = strings.TrimSpace(eventType); eventType != "" {
+			types = append(types, domain.TimelineEventType(eventType))
+		}
+	}
+
+	user, _ := c.Get("userData")
+	claim, _ := user.(models.User)
+
+	events, nextCursor, err := r.Service.GetTicketTimeline(id, types, limit, cursor, claim)
+	if err != nil {
+		status := serviceErrorStatus(err, http.StatusInternalServerError)
+		if status != http.StatusInternalServerError {
+			response := helpers.NewResponse(status, err.Error(), validationFields(err), nil)
+			c.JSON(status, response)
+			return
+		}
+		response := helpers.NewResponse(http.StatusInternalServerError, "Failed to get ticket timeline", nil, nil)
+		c.JSON(http.StatusInternalServerError, response)
+		return
+	}
+
+	responseData := map[string]interface{}{
+		"data": events,
+		"meta": map[string]interface{}{
+			"next_cursor": nextCursor,
+			"limit":       limit,
+		},
+	}
+	response := helpers.NewResponse(http.StatusOK, "Ticket timeline retrieved successfully", nil, responseData)
+	c.JSON(http.StatusOK, response)
+}
+
+// BulkUpdateTickets godoc
+// @Summary Apply an action to many tickets
+// @Description Set the status, priority, category or assignee of a list of up to 500 distinct tickets, or delete them, in one transaction (Admin and Support only). A list with duplicate IDs is rejected. Each ticket runs the same access checks and validations as the single ticket endpoints and the result is reported per ticket, with the status the single ticket endpoint would answer a failure with (e.g. 403 for a ticket the user may not access).
+// @Tags tickets
+// @Security BearerAuth
+// @Accept json
+// @Produce json
+// @Param bulk body requests.TicketBulkRequest true "Bulk operation"
+// @Success 200 {object} helpers.Response{data=[]domain.BulkTicketResult}
+// @Failure 400 {object} helpers.Response
+// @Router /tickets/bulk [post]
+func (r *appRoute) bulkUpdateTickets(c *gin.Context) {
+	var req requests.TicketBulkRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		response := helpers.NewResponse(http.StatusBadRequest, "Invalid request body", nil, nil)
+		c.JSON(http.StatusBadRequest, response)
+		return
+	}
+
+	op := domain.BulkTicketOperation{
+		TicketIDs:  req.TicketIDs,
+		Action:     domain.BulkTicketAction(req.Action),
+		StatusID:   req.StatusID,
+		PriorityID: req.PriorityID,
+		CategoryID: req.CategoryID,
+		AdminID:    req.AdminID,
+	}
+
+	var missing string
+	switch op.Action {
+	case domain.BulkTicketActionStatus:
+		if op.StatusID <= 0 {
+			missing = "id_status"
+		}
+	case domain.BulkTicketActionPriority:
+		if op.PriorityID <= 0 {
+			missing = "id_priority"
+		}
+	case domain.BulkTicketActionCategory:
+		if op.CategoryID <= 0 {
+			missing = "id_category"
+		}
+	case domain.BulkTicketActionAssign:
+		if op.AdminID <= 0 {
+			missing = "id_admin"
+		}
+	case domain.BulkTicketActionDelete:
+	default:
+		response := helpers.NewResponse(http.StatusBadRequest, "Invalid action, use status, priority, category, assign or delete", nil, nil)
+		c.JSON(http.StatusBadRequest, response)
+		return
+	}
+	if missing != "" {
+		response := helpers.NewResponse(http.StatusBadRequest, "Invalid request body", map[string]string{missing: "required for action " + req.Action}, nil)
+		c.JSON(http.StatusBadRequest, response)
+		return
+	}
+
+	user, _ := c.Get("userData")
+	claim, _ := user.(models.User)
+
+	results, err := r.Service.BulkUpdateTickets(op, claim)
+	if err != nil {
+		response := helpers.NewResponse(http.StatusInternalServerError, "Failed to update tickets", nil, nil)
+		c.JSON(http.StatusInternalServerError, response)
+		return
+	}
+
+	succeeded := 0
+	for i, result := range results {
+		if result.Success {
+			succeeded++
+			continue
+		}
+		results[i].Status = serviceErrorStatus(result.Err, http.StatusInternalServerError)
+	}
+
+	message := fmt.Sprintf("%d of %d tickets updated", succeeded, len(results))
+	response := helpers.NewResponse(http.StatusOK, message, nil, results)
+	c.JSON(http.StatusOK, response)
+}
+
 // GetMyTickets godoc
 // @Summary Get current user's tickets
 // @Description Get all tickets belonging to the authenticated user
@@ -378,3 +926,178 @@ func (r *appRoute) getMyTickets(c *gin.Context) {
 	response := helpers.NewResponse(http.StatusOK, "User tickets retrieved successfully", nil, resp)
 	c.JSON(http.StatusOK, response)
 }
+
+// SearchTickets godoc
+// @Summary Search tickets
+// @Description Full-text search on ticket code, title, description and comments (Admin and Support only, support users only find the tickets assigned to them), ranked by relevance with highlighted snippets. A snippet is HTML: the ticket text is escaped and the matches are wrapped in <mark>. Accepts the same filters as the ticket list.
+// @Tags tickets
+// @Produce json
+// @Security BearerAuth
+// @Param q query string true "Search terms, supports \"quoted phrases\", OR and -excluded words"
+// @Param code query string false "Filter by ticket code prefix, e.g. CUS-2026"
+// @Param role query string false "Filter by tipe_pengaduan (customer, seller, admin, support)"
+// @Param status query int false "Filter by status ID"
+// @Param priority query int false "Filter by priority ID"
+// @Param category query int false "Filter by category ID"
+// @Param min_reopen_count query int false "Filter by tickets reopened at least this many times"
+// @Param include_pending query bool false "Include snoozed tickets, left out unless filtering by status"
+// @Param sla query string false "Filter by SLA state (at_risk, breached)"
+// @Param tags query string false "Filter by comma separated tag IDs"
+// @Param tags_mode query string false "Match any (default) or all of the tags"
+// @Param cf.key query string false "Filter by a custom field value, e.g. cf.nomor_pesanan=INV-001"
+// @Param limit query int false "Items per page (default: 10)"
+// @Param cursor query string false "Cursor for next page"
+// @Success 200 {object} helpers.Response{data=[]requests.TicketSearchResponse}
+// @Failure 400 {object} helpers.Response
+// @Router /tickets/search [get]
+func (r *appRoute) searchTickets(c *gin.Context) {
+	query := strings.TrimSpace(c.Query("q"))
+	if query == "" {
+		response := helpers.NewResponse(http.StatusBadRequest, "Search query q is required", nil, nil)
+		c.JSON(http.StatusBadRequest, response)
+		return
+	}
+
+	limit, _ := strconv.Atoi(c.DefaultQuery("limit", "10"))
+	if limit < 1 || limit > 100 {
+		limit = 10
+	}
+	cursor := c.Query("cursor")
+
+	filter, err := ticketFilterFromQuery(c)
+	if err != nil {
+		response := helpers.NewResponse(http.StatusBadRequest, err.Error(), nil, nil)
+		c.JSON(http.StatusBadRequest, response)
+		return
+	}
+
+	user, _ := c.Get("userData")
+	claim, _ := user.(models.User)
+	results, nextCursor, err := r.Service.SearchTickets(query, limit, cursor, filter, claim)
+	if err != nil {
+		response := helpers.NewResponse(http.StatusInternalServerError, "Failed to search tickets", nil, nil)
+		c.JSON(http.StatusInternalServerError, response)
+		return
+	}
+
+	resp := make([]requests.TicketSearchResponse, 0, len(results))
+	for _, result := range results {
+		resp = append(resp, requests.TicketSearchResponse{
+			TicketResponse: mapTicketResponse(&result.Ticket),
+			Rank:           result.Rank,
+			Snippet:        result.Snippet,
+		})
+	}
+
+	responseData := map[string]interface{}{
+		"data": resp,
+		"meta": map[string]interface{}{
+			"next_cursor": nextCursor,
+			"limit":       limit,
+		},
+	}
+	response := helpers.NewResponse(http.StatusOK, "Tickets retrieved successfully", nil, responseData)
+	c.JSON(http.StatusOK, response)
+}
+
+// ticketFilterFromQuery reads the ticket list filters shared by the list and search endpoints
+func ticketFilterFromQuery(c *gin.Context) (domain.TicketFilter, error) {
+	// Get filter params for status, priority, and category
+	statusID, _ := strconv.Atoi(c.Query("status"))
+	priorityID, _ := strconv.Atoi(c.Query("priority"))
+	categoryID, _ := strconv.Atoi(c.Query("category"))
+	minReopenCount, _ := strconv.Atoi(c.Query("min_reopen_count"))
+	includePending, _ := strconv.ParseBool(c.Query("include_pending"))
+
+	filter := domain.TicketFilter{
+		KodeTiket:      strings.TrimSpace(c.Query("code")),
+		TipePengaduan:  c.Query("role"),
+		StatusID:       statusID,
+		PriorityID:     priorityID,
+		CategoryID:     categoryID,
+		MinReopenCount: minReopenCount,
+		IncludePending: includePending,
+	}
+
+	switch slaState := models.SLAState(c.Query("sla")); slaState {
+	case "":
+	case models.SLAStateAtRisk, models.SLAStateBreached:
+		filter.SLAState = slaState
+	default:
+		return filter, errors.New("Invalid sla filter, use at_risk or breached")
+	}
+
+	tags, err := tagFilterFromQuery(c)
+	if err != nil {
+		return filter, err
+	}
+	filter.Tags = tags
+
+	// cf.<key>=value filters on a custom field
+	for param, values := range c.Request.URL.Query() {
+		key, isCustomField := strings.CutPrefix(param, "cf.")
+		if !isCustomField || len(values) == 0 {
+			continue
+		}
+		if !models.CustomFieldKeyPattern.MatchString(key) {
+			return filter, fmt.Errorf("Invalid custom field filter %q", param)
+		}
+		if filter.CustomFields == nil {
+			filter.CustomFields = make(map[string]string)
+		}
+		filter.CustomFields[key] = values[0]
+	}
+
+	return filter, nil
+}
+
+// mapTicketResponse maps a ticket with its loaded user to the API response
+func mapTicketResponse(ticket *models.Ticket) requests.TicketResponse {
+	return requests.TicketResponse{
+		ID:                ticket.ID,
+		KodeTiket:         ticket.KodeTiket,
+		UserID:            ticket.UserID,
+		Username:          ticket.User.Username,
+		Judul:             ticket.Judul,
+		Deskripsi:         ticket.Deskripsi,
+		CategoryID:        ticket.CategoryID,
+		PriorityID:        ticket.PriorityID,
+		StatusID:          ticket.StatusID,
+		TipePengaduan:     ticket.TipePengaduan,
+		TanggalDibuat:     ticket.TanggalDibuat.Format("2006-01-02T15:04:05Z07:00"),
+		TanggalDiperbarui: ticket.TanggalDiperbarui.Format("2006-01-02T15:04:05Z07:00"),
+		Version:           ticket.Version,
+		ReopenCount:       ticket.ReopenCount,
+		PendingUntil:      ticket.PendingUntil,
+		PendingReason:     ticket.PendingReason,
+		SLA:               mapTicketSLA(ticket),
+		MergedIntoID:      ticket.MergedIntoID,
+		Tags:              ticket.Tags,
+		CustomFields:      ticket.CustomFields,
+		DeletedAt:         mapTicketDeletedAt(ticket),
+	}
+}
+
+func mapTicketDeletedAt(ticket *models.Ticket) *time.Time {
+	if !ticket.DeletedAt.Valid {
+		return nil
+	}
+	return &ticket.DeletedAt.Time
+}
+
+// mapTicketSLA maps the SLA fields of a ticket, nil when no SLA policy applies
+func mapTicketSLA(ticket *models.Ticket) *requests.TicketSLAResponse {
+	if ticket.SLAPolicyID == nil {
+		return nil
+	}
+	return &requests.TicketSLAResponse{
+		PolicyID:           ticket.SLAPolicyID,
+		FirstResponseDueAt: ticket.FirstResponseDueAt,
+		FirstRespondedAt:   ticket.FirstRespondedAt,
+		NextResponseDueAt:  ticket.NextResponseDueAt,
+		ResolutionDueAt:    ticket.ResolutionDueAt,
+		Paused:             ticket.SLAPausedAt != nil,
+		AtRisk:             ticket.SLAAtRisk,
+		Breached:           ticket.SLABreached,
+	}
+}
@@ -1,9 +1,12 @@
 package handlers
 
 import (
+	"app/app/middleware"
+	"app/domain"
 	"app/domain/models"
 	"app/domain/requests"
 	"app/helpers"
+	"errors"
 	"net/http"
 	"strconv"
 	"time"
@@ -14,24 +17,29 @@ import (
 func (r *appRoute) TicketAssignmentRoutes(rg *gin.RouterGroup) {
 	api := rg.Group("/ticket-assignments")
 	api.Use(r.Middleware.Auth())
-	api.POST("", r.createTicketAssignment)
-	api.GET("", r.getTicketAssignments)
+	api.POST("", r.Middleware.RequireAdminOrSupport(), r.createTicketAssignment)
+	api.GET("", r.Middleware.RequireRole(models.RoleAdmin), r.getTicketAssignments)
 	api.GET("/my-assignments", r.getMySupportAssignments)
 	api.GET("/my-counts", r.Middleware.RequireRole(models.RoleSupport), r.getMyAssignedTicketCounts) // New route for support users
-	api.GET("/:id", r.getTicketAssignmentByID)
-	api.PUT("/:id", r.updateTicketAssignment)
-	api.DELETE("/:id", r.deleteTicketAssignment)
+
+	// Ticket owner, assignee or admin endpoints, only agents change assignments
+	assignmentAccess := r.Middleware.RequireTicketAccess(middleware.AssignmentTicket("id"))
+	api.GET("/:id", assignmentAccess, r.getTicketAssignmentByID)
+	api.PUT("/:id", r.Middleware.RequireAdminOrSupport(), assignmentAccess, r.updateTicketAssignment)
+	api.PATCH("/:id", r.Middleware.RequireAdminOrSupport(), assignmentAccess, r.patchTicketAssignment)
+	api.DELETE("/:id", r.Middleware.RequireAdminOrSupport(), assignmentAccess, r.deleteTicketAssignment)
 }
 
 // CreateTicketAssignment godoc
 // @Summary Create a new ticket assignment
-// @Description Create a new ticket assignment
+// @Description Create a new ticket assignment (Admin or support only)
 // @Tags ticket-assignments
 // @Accept json
 // @Produce json
 // @Security BearerAuth
 // @Param assignment body requests.CreateTicketAssignmentRequest true "Ticket Assignment Data"
 // @Success 201 {object} helpers.Response{data=requests.TicketAssignmentResponse}
+// @Failure 403 {object} helpers.Response
 // @Router /ticket-assignments [post]
 func (r *appRoute) createTicketAssignment(c *gin.Context) {
 	var req requests.CreateTicketAssignmentRequest
@@ -48,6 +56,10 @@ func (r *appRoute) createTicketAssignment(c *gin.Context) {
 		return
 	}
 
+	if !r.Middleware.AuthorizeTicket(c, req.TicketID) {
+		return
+	}
+
 	assignment := models.TicketAssignment{
 		TicketID:          req.TicketID,
 		AdminID:           req.AdminID,
@@ -55,7 +67,26 @@ func (r *appRoute) createTicketAssignment(c *gin.Context) {
 		TanggalDitugaskan: tglDitugaskan,
 	}
 
-	if err := r.Service.CreateTicketAssignment(&assignment); err != nil {
+	userData, exists := c.Get("userData")
+	if !exists {
+		response := helpers.NewResponse(http.StatusUnauthorized, "User authentication required", nil, nil)
+		c.JSON(http.StatusUnauthorized, response)
+		return
+	}
+	user, ok := userData.(models.User)
+	if !ok {
+		response := helpers.NewResponse(http.StatusUnauthorized, "Invalid user data", nil, nil)
+		c.JSON(http.StatusUnauthorized, response)
+		return
+	}
+
+	if err := r.Service.CreateTicketAssignment(&assignment, user); err != nil {
+		status := serviceErrorStatus(err, http.StatusInternalServerError)
+		if status != http.StatusInternalServerError {
+			response := helpers.NewResponse(status, err.Error(), nil, nil)
+			c.JSON(status, response)
+			return
+		}
 		response := helpers.NewResponse(http.StatusInternalServerError, "Failed to create ticket assignment", nil, nil)
 		c.JSON(http.StatusInternalServerError, response)
 		return
@@ -274,6 +305,7 @@ func (r *appRoute) getTicketAssignmentByID(c *gin.Context) {
 		return
 	}
 
+	setETag(c, assignment.Version)
 	resp := mapTicketAssignmentToResponse(assignment)
 	response := helpers.NewResponse(http.StatusOK, "Ticket assignment retrieved successfully", nil, resp)
 	c.JSON(http.StatusOK, response)
@@ -287,6 +319,7 @@ func mapTicketAssignmentToResponse(a *models.TicketAssignment) requests.TicketAs
 		AdminID:           a.AdminID,
 		PriorityID:        a.PriorityID,
 		TanggalDitugaskan: a.TanggalDitugaskan.Format("2006-01-02T15:04:05Z"),
+		Version:           a.Version,
 	}
 
 	// Map Priority if exists
@@ -308,6 +341,7 @@ func mapTicketAssignmentToResponseWithTicket(a *models.TicketAssignment, ticket
 		AdminID:           a.AdminID,
 		PriorityID:        a.PriorityID,
 		TanggalDitugaskan: a.TanggalDitugaskan.Format("2006-01-02T15:04:05Z"),
+		Version:           a.Version,
 		Ticket: &requests.TicketResponse{
 			ID:                ticket.ID,
 			KodeTiket:         ticket.KodeTiket,
@@ -336,14 +370,17 @@ func mapTicketAssignmentToResponseWithTicket(a *models.TicketAssignment, ticket
 
 // UpdateTicketAssignment godoc
 // @Summary Update a ticket assignment
-// @Description Update a ticket assignment by its ID
+// @Description Update a ticket assignment by its ID (Admin or support only)
 // @Tags ticket-assignments
 // @Accept json
 // @Produce json
 // @Security BearerAuth
 // @Param id path int true "Assignment ID"
+// @Param If-Match header string false "ETag of the assignment the change is based on"
 // @Param assignment body requests.CreateTicketAssignmentRequest true "Updated Assignment Data"
 // @Success 200 {object} helpers.Response{data=requests.TicketAssignmentResponse}
+// @Failure 409 {object} helpers.Response{data=requests.TicketAssignmentResponse} "The assignment changed, data holds the current assignment"
+// @Failure 403 {object} helpers.Response
 // @Router /ticket-assignments/{id} [put]
 func (r *appRoute) updateTicketAssignment(c *gin.Context) {
 	id, err := strconv.Atoi(c.Param("id"))
@@ -367,33 +404,140 @@ func (r *appRoute) updateTicketAssignment(c *gin.Context) {
 		return
 	}
 
+	version, err := ifMatchVersion(c)
+	if err != nil {
+		response := helpers.NewResponse(http.StatusBadRequest, err.Error(), nil, nil)
+		c.JSON(http.StatusBadRequest, response)
+		return
+	}
+
 	assignment := models.TicketAssignment{
 		ID:                id,
 		TicketID:          req.TicketID,
 		AdminID:           req.AdminID,
 		PriorityID:        req.PriorityID,
 		TanggalDitugaskan: tglDitugaskan,
+		Version:           version,
 	}
 
-	if err := r.Service.UpdateTicketAssignment(&assignment); err != nil {
+	userData, exists := c.Get("userData")
+	if !exists {
+		response := helpers.NewResponse(http.StatusUnauthorized, "User authentication required", nil, nil)
+		c.JSON(http.StatusUnauthorized, response)
+		return
+	}
+	user, ok := userData.(models.User)
+	if !ok {
+		response := helpers.NewResponse(http.StatusUnauthorized, "Invalid user data", nil, nil)
+		c.JSON(http.StatusUnauthorized, response)
+		return
+	}
+
+	if err := r.Service.UpdateTicketAssignment(&assignment, user); err != nil {
+		if errors.Is(err, domain.ErrVersionConflict) {
+			r.respondTicketAssignmentConflict(c, id, err)
+			return
+		}
+		status := serviceErrorStatus(err, http.StatusInternalServerError)
+		if status != http.StatusInternalServerError {
+			response := helpers.NewResponse(status, err.Error(), nil, nil)
+			c.JSON(status, response)
+			return
+		}
 		response := helpers.NewResponse(http.StatusInternalServerError, "Failed to update ticket assignment", nil, nil)
 		c.JSON(http.StatusInternalServerError, response)
 		return
 	}
 
+	setETag(c, assignment.Version)
 	resp := mapTicketAssignmentToResponse(&assignment)
 	response := helpers.NewResponse(http.StatusOK, "Ticket assignment updated successfully", nil, resp)
 	c.JSON(http.StatusOK, response)
 }
 
+// PatchTicketAssignment godoc
+// @Summary Partially update a ticket assignment
+// @Description Apply a JSON merge patch (RFC 7386) to an assignment: only the fields present change and null resets a field. Patchable fields are id_admin, priority_id and tanggal_ditugaskan. Changes are recorded in the ticket log. Admin or support only.
+// @Tags ticket-assignments
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param id path int true "Assignment ID"
+// @Param If-Match header string false "ETag of the assignment the change is based on"
+// @Param patch body object true "Fields to change"
+// @Success 200 {object} helpers.Response{data=requests.TicketAssignmentResponse}
+// @Failure 400 {object} helpers.Response
+// @Failure 403 {object} helpers.Response
+// @Failure 404 {object} helpers.Response
+// @Failure 409 {object} helpers.Response{data=requests.TicketAssignmentResponse} "The assignment changed, data holds the current assignment"
+// @Router /ticket-assignments/{id} [patch]
+func (r *appRoute) patchTicketAssignment(c *gin.Context) {
+	id, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		response := helpers.NewResponse(http.StatusBadRequest, "Invalid assignment ID", nil, nil)
+		c.JSON(http.StatusBadRequest, response)
+		return
+	}
+
+	patch, err := c.GetRawData()
+	if err != nil {
+		response := helpers.NewResponse(http.StatusBadRequest, "Invalid request body", nil, nil)
+		c.JSON(http.StatusBadRequest, response)
+		return
+	}
+
+	version, err := ifMatchVersion(c)
+	if err != nil {
+		response := helpers.NewResponse(http.StatusBadRequest, err.Error(), nil, nil)
+		c.JSON(http.StatusBadRequest, response)
+		return
+	}
+
+	user, _ := c.Get("userData")
+	claim, _ := user.(models.User)
+
+	assignment, err := r.Service.PatchTicketAssignment(id, patch, version, claim)
+	if err != nil {
+		if errors.Is(err, domain.ErrVersionConflict) {
+			r.respondTicketAssignmentConflict(c, id, err)
+			return
+		}
+		status := serviceErrorStatus(err, http.StatusInternalServerError)
+		if status != http.StatusInternalServerError {
+			response := helpers.NewResponse(status, err.Error(), validationFields(err), nil)
+			c.JSON(status, response)
+			return
+		}
+		response := helpers.NewResponse(http.StatusInternalServerError, "Failed to update ticket assignment", nil, nil)
+		c.JSON(http.StatusInternalServerError, response)
+		return
+	}
+
+	setETag(c, assignment.Version)
+	response := helpers.NewResponse(http.StatusOK, "Ticket assignment updated successfully", nil, mapTicketAssignmentToResponse(assignment))
+	c.JSON(http.StatusOK, response)
+}
+
+// respondTicketAssignmentConflict answers an outdated assignment update with the current assignment
+func (r *appRoute) respondTicketAssignmentConflict(c *gin.Context, id int, err error) {
+	current, getErr := r.Service.GetTicketAssignmentByID(id)
+	if getErr != nil {
+		response := helpers.NewResponse(http.StatusConflict, err.Error(), nil, nil)
+		c.JSON(http.StatusConflict, response)
+		return
+	}
+	respondVersionConflict(c, err, current.Version, mapTicketAssignmentToResponse(current))
+}
+
 // DeleteTicketAssignment godoc
 // @Summary Delete a ticket assignment
-// @Description Delete a ticket assignment by its ID
+// @Description Delete a ticket assignment by its ID (Admin or support only)
 // @Tags ticket-assignments
 // @Produce json
 // @Security BearerAuth
 // @Param id path int true "Assignment ID"
 // @Success 200 {object} helpers.Response
+// @Failure 403 {object} helpers.Response
 // @Router /ticket-assignments/{id} [delete]
 func (r *appRoute) deleteTicketAssignment(c *gin.Context) {
 	id, err := strconv.Atoi(c.Param("id"))
@@ -403,7 +547,9 @@ func (r *appRoute) deleteTicketAssignment(c *gin.Context) {
 		return
 	}
 
-	if err := r.Service.DeleteTicketAssignment(id); err != nil {
+	user, _ := c.Get("userData")
+	claim, _ := user.(models.User)
+	if err := r.Service.DeleteTicketAssignment(id, claim); err != nil {
 		response := helpers.NewResponse(http.StatusInternalServerError, "Failed to delete ticket assignment", nil, nil)
 		c.JSON(http.StatusInternalServerError, response)
 		return
@@ -1,6 +1,7 @@
 package handlers
 
 import (
+	"app/app/middleware"
 	"app/domain/models"
 	"app/helpers"
 	"log"
@@ -14,9 +15,9 @@ import (
 func (r *appRoute) TicketAttachmentRoutes(rg *gin.RouterGroup) {
 	api := rg.Group("/ticket-attachments")
 	api.POST("", r.Middleware.Auth(), r.createTicketAttachment)
-	api.GET("/ticket/:ticket_id", r.getTicketAttachmentsByTicketID) // New dedicated endpoint
-	api.GET("/:id", r.Middleware.Auth(), r.getTicketAttachmentByID)
-	api.PUT("/:id", r.Middleware.Auth(), r.updateTicketAttachment)
+	api.GET("/ticket/:ticket_id", r.Middleware.Auth(), r.Middleware.RequireTicketAccess(middleware.TicketFromParam("ticket_id")), r.getTicketAttachmentsByTicketID) // New dedicated endpoint
+	api.GET("/:id", r.Middleware.Auth(), r.Middleware.RequireTicketAccess(middleware.AttachmentTicket("id")), r.getTicketAttachmentByID)
+	api.PUT("/:id", r.Middleware.Auth(), r.Middleware.RequireTicketAccess(middleware.AttachmentTicket("id")), r.updateTicketAttachment)
 	api.DELETE("/:id", r.Middleware.Auth(), r.Middleware.RequireRole(models.RoleAdmin), r.deleteTicketAttachment)
 }
 
@@ -53,7 +54,13 @@ func (r *appRoute) createTicketAttachment(c *gin.Context) {
 		return
 	}
 
-	attachment, err := r.Service.CreateTicketAttachment(ticketID, file)
+	if !r.Middleware.AuthorizeTicket(c, ticketID) {
+		return
+	}
+
+	user, _ := c.Get("userData")
+	claim, _ := user.(models.User)
+	attachment, err := r.Service.CreateTicketAttachment(ticketID, file, claim)
 	if err != nil {
 		response := helpers.NewResponse(http.StatusInternalServerError, "Failed to create attachment", nil, nil)
 		c.JSON(http.StatusInternalServerError, response)
@@ -217,7 +224,9 @@ func (r *appRoute) updateTicketAttachment(c *gin.Context) {
 		file = f
 	}
 
-	attachment, err := r.Service.UpdateTicketAttachment(id, ticketID, file)
+	user, _ := c.Get("userData")
+	claim, _ := user.(models.User)
+	attachment, err := r.Service.UpdateTicketAttachment(id, ticketID, file, claim)
 	if err != nil {
 		if err.Error() == "record not found" {
 			response := helpers.NewResponse(http.StatusNotFound, "Attachment not found", nil, nil)
@@ -252,7 +261,9 @@ func (r *appRoute) deleteTicketAttachment(c *gin.Context) {
 		return
 	}
 
-	if err := r.Service.DeleteTicketAttachment(id); err != nil {
+	user, _ := c.Get("userData")
+	claim, _ := user.(models.User)
+	if err := r.Service.DeleteTicketAttachment(id, claim); err != nil {
 		response := helpers.NewResponse(http.StatusInternalServerError, "Failed to delete attachment", nil, nil)
 		c.JSON(http.StatusInternalServerError, response)
 		return
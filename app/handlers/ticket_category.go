@@ -15,6 +15,7 @@ func (r *appRoute) TicketCategoryRoutes(rg *gin.RouterGroup) {
 	api.GET("", r.getTicketCategories)
 	api.GET("/:id", r.getTicketCategoryByID)
 	api.PUT("/:id", r.Middleware.Auth(), r.Middleware.RequireRole(models.RoleAdmin),  r.updateTicketCategory)
+	api.PATCH("/:id", r.Middleware.Auth(), r.Middleware.RequireRole(models.RoleAdmin), r.patchTicketCategory)
 	api.DELETE("/:id", r.Middleware.Auth(), r.Middleware.RequireRole(models.RoleAdmin),  r.deleteTicketCategory)
 }
 
@@ -129,6 +130,51 @@ func (r *appRoute) updateTicketCategory(c *gin.Context) {
 	c.JSON(http.StatusOK, response)
 }
 
+// PatchTicketCategory godoc
+// @Summary Partially update a ticket category
+// @Description Apply a JSON merge patch (RFC 7386) to a ticket category, the patchable field is nama_category
+// @Tags ticket-categories
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param id path int true "Category ID"
+// @Param patch body object true "Fields to change"
+// @Success 200 {object} helpers.Response{data=models.TicketCategory}
+// @Failure 400 {object} helpers.Response
+// @Failure 404 {object} helpers.Response
+// @Router /ticket-categories/{id} [patch]
+func (r *appRoute) patchTicketCategory(c *gin.Context) {
+	id, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		response := helpers.NewResponse(http.StatusBadRequest, "Invalid category ID", nil, nil)
+		c.JSON(http.StatusBadRequest, response)
+		return
+	}
+
+	patch, err := c.GetRawData()
+	if err != nil {
+		response := helpers.NewResponse(http.StatusBadRequest, "Invalid request body", nil, nil)
+		c.JSON(http.StatusBadRequest, response)
+		return
+	}
+
+	category, err := r.Service.PatchTicketCategory(id, patch)
+	if err != nil {
+		status := serviceErrorStatus(err, http.StatusInternalServerError)
+		if status != http.StatusInternalServerError {
+			response := helpers.NewResponse(status, err.Error(), validationFields(err), nil)
+			c.JSON(status, response)
+			return
+		}
+		response := helpers.NewResponse(http.StatusInternalServerError, "Failed to update ticket category", nil, nil)
+		c.JSON(http.StatusInternalServerError, response)
+		return
+	}
+
+	response := helpers.NewResponse(http.StatusOK, "Ticket category updated successfully", nil, category)
+	c.JSON(http.StatusOK, response)
+}
+
 // DeleteTicketCategory godoc
 // @Summary Delete a ticket category
 // @Description Delete a ticket category by its ID
@@ -1,9 +1,12 @@
 package handlers
 
 import (
+	"app/app/middleware"
+	"app/domain"
 	"app/domain/models"
 	"app/domain/requests"
 	"app/helpers"
+	"errors"
 	"net/http"
 	"strconv"
 	"time"
@@ -13,20 +16,24 @@ import (
 
 func (r *appRoute) TicketCommentRoutes(rg *gin.RouterGroup) {
 	api := rg.Group("/ticket-comments")
-	
-	// Public/authenticated user can view comments
-	api.GET("", r.Middleware.Auth(), r.getTicketComments)
-	api.GET("/:id", r.Middleware.Auth(), r.getTicketCommentByID)
-	
-	// Only admin and support can create, update, and delete comments
-	api.POST("", r.Middleware.Auth(), r.Middleware.RequireAdminOrSupport(), r.createTicketComment)
-	api.PUT("/:id", r.Middleware.Auth(), r.Middleware.RequireAdminOrSupport(), r.updateTicketComment)
-	api.DELETE("/:id", r.Middleware.Auth(), r.Middleware.RequireAdminOrSupport(), r.deleteTicketComment)
+	commentAccess := r.Middleware.RequireTicketAccess(middleware.CommentTicket("id"))
+
+	// Users with access to the ticket can view comments, all comments need an admin
+	api.GET("", r.Middleware.Auth(), r.Middleware.RequireTicketAccess(middleware.TicketFromQuery("ticket_id")), r.getTicketComments)
+	api.GET("/:id", r.Middleware.Auth(), commentAccess, r.getTicketCommentByID)
+
+	// Users with access to the ticket can comment, a reply from the owner reopens a resolved ticket
+	api.POST("", r.Middleware.Auth(), r.createTicketComment)
+
+	// Only admin and support can update and delete comments
+	api.PUT("/:id", r.Middleware.Auth(), r.Middleware.RequireAdminOrSupport(), commentAccess, r.updateTicketComment)
+	api.PATCH("/:id", r.Middleware.Auth(), r.Middleware.RequireAdminOrSupport(), commentAccess, r.patchTicketComment)
+	api.DELETE("/:id", r.Middleware.Auth(), r.Middleware.RequireAdminOrSupport(), commentAccess, r.deleteTicketComment)
 }
 
 // CreateTicketComment godoc
 // @Summary Create a new ticket comment
-// @Description Create a new comment on a ticket (Admin and Support only)
+// @Description Create a new comment on a ticket. Users with access to the ticket can comment, a reply from the customer moves a resolved ticket back to open
 // @Tags ticket-comments
 // @Accept json
 // @Produce json
@@ -45,6 +52,10 @@ func (r *appRoute) createTicketComment(c *gin.Context) {
         return
     }
 
+    if !r.Middleware.AuthorizeTicket(c, req.TicketID) {
+        return
+    }
+
     // Get user from context (required - no fallback)
     userData, exists := c.Get("userData")
     if !exists {
@@ -63,9 +74,16 @@ func (r *appRoute) createTicketComment(c *gin.Context) {
         UserID:        int(user.ID), // Use authenticated user's ID from JWT
         IsiPesan:      req.IsiPesan,
         TanggalDibuat: time.Now(), // Set current timestamp
+
+        CascadeToChildren: req.CascadeToChildren,
     }
 
     if err := r.Service.CreateTicketComment(&comment); err != nil {
+        status := serviceErrorStatus(err, http.StatusInternalServerError)
+        if status != http.StatusInternalServerError {
+            c.JSON(status, helpers.NewResponse(status, err.Error(), nil, nil))
+            return
+        }
         c.JSON(http.StatusInternalServerError, helpers.NewResponse(http.StatusInternalServerError, "Failed to create comment: "+err.Error(), nil, nil))
         return
     }
@@ -76,6 +94,7 @@ func (r *appRoute) createTicketComment(c *gin.Context) {
         UserID:        comment.UserID,
         IsiPesan:      comment.IsiPesan,
         TanggalDibuat: comment.TanggalDibuat,
+        Version:       comment.Version,
     }
 
     c.JSON(http.StatusCreated, helpers.NewResponse(http.StatusCreated, "Comment created successfully", nil, resp))
@@ -117,6 +136,7 @@ func (r *appRoute) getTicketComments(c *gin.Context) {
 			UserID:        comment.UserID,
 			IsiPesan:      comment.IsiPesan,
 			TanggalDibuat: comment.TanggalDibuat,
+			Version:       comment.Version,
 		})
 	}
 
@@ -153,8 +173,10 @@ func (r *appRoute) getTicketCommentByID(c *gin.Context) {
 		UserID:        comment.UserID,
 		IsiPesan:      comment.IsiPesan,
 		TanggalDibuat: comment.TanggalDibuat,
+		Version:       comment.Version,
 	}
 
+	setETag(c, comment.Version)
 	c.JSON(http.StatusOK, helpers.NewResponse(http.StatusOK, "Comment retrieved successfully", nil, resp))
 }
 
@@ -166,12 +188,14 @@ func (r *appRoute) getTicketCommentByID(c *gin.Context) {
 // @Produce json
 // @Security BearerAuth
 // @Param id path int true "Comment ID"
+// @Param If-Match header string false "ETag of the comment the change is based on"
 // @Param comment body requests.UpdateTicketCommentRequest true "Updated Comment Data"
 // @Success 200 {object} helpers.Response{data=requests.TicketCommentResponse}
 // @Failure 400 {object} helpers.Response
 // @Failure 401 {object} helpers.Response
 // @Failure 403 {object} helpers.Response
 // @Failure 404 {object} helpers.Response
+// @Failure 409 {object} helpers.Response{data=requests.TicketCommentResponse} "The comment changed, data holds the current comment"
 // @Failure 500 {object} helpers.Response
 // @Router /ticket-comments/{id} [put]
 func (r *appRoute) updateTicketComment(c *gin.Context) {
@@ -187,6 +211,12 @@ func (r *appRoute) updateTicketComment(c *gin.Context) {
 		return
 	}
 
+	version, err := ifMatchVersion(c)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, helpers.NewResponse(http.StatusBadRequest, err.Error(), nil, nil))
+		return
+	}
+
 	// Get user from context
 	userData, exists := c.Get("userData")
 	if !exists {
@@ -205,10 +235,15 @@ func (r *appRoute) updateTicketComment(c *gin.Context) {
 		TicketID: req.TicketID,
 		UserID:   int(user.ID), // Use authenticated user's ID
 		IsiPesan: req.IsiPesan,
+		Version:  version,
 		// TanggalDibuat should not be updated
 	}
 
-	if err := r.Service.UpdateTicketComment(&comment); err != nil {
+	if err := r.Service.UpdateTicketComment(&comment, user); err != nil {
+		if errors.Is(err, domain.ErrVersionConflict) {
+			r.respondTicketCommentConflict(c, id, err)
+			return
+		}
 		c.JSON(http.StatusInternalServerError, helpers.NewResponse(http.StatusInternalServerError, "Failed to update comment", nil, nil))
 		return
 	}
@@ -219,11 +254,95 @@ func (r *appRoute) updateTicketComment(c *gin.Context) {
 		UserID:        comment.UserID,
 		IsiPesan:      comment.IsiPesan,
 		TanggalDibuat: comment.TanggalDibuat,
+		Version:       comment.Version,
 	}
 
+	setETag(c, comment.Version)
 	c.JSON(http.StatusOK, helpers.NewResponse(http.StatusOK, "Comment updated successfully", nil, resp))
 }
 
+// PatchTicketComment godoc
+// @Summary Partially update a ticket comment
+// @Description Apply a JSON merge patch (RFC 7386) to a comment, the patchable field is isi_pesan (Admin and Support only). The change is recorded in the ticket log.
+// @Tags ticket-comments
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param id path int true "Comment ID"
+// @Param If-Match header string false "ETag of the comment the change is based on"
+// @Param patch body object true "Fields to change"
+// @Success 200 {object} helpers.Response{data=requests.TicketCommentResponse}
+// @Failure 400 {object} helpers.Response
+// @Failure 404 {object} helpers.Response
+// @Failure 409 {object} helpers.Response{data=requests.TicketCommentResponse} "The comment changed, data holds the current comment"
+// @Router /ticket-comments/{id} [patch]
+func (r *appRoute) patchTicketComment(c *gin.Context) {
+	id, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, helpers.NewResponse(http.StatusBadRequest, "Invalid comment ID", nil, nil))
+		return
+	}
+
+	patch, err := c.GetRawData()
+	if err != nil {
+		c.JSON(http.StatusBadRequest, helpers.NewResponse(http.StatusBadRequest, "Invalid request body", nil, nil))
+		return
+	}
+
+	version, err := ifMatchVersion(c)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, helpers.NewResponse(http.StatusBadRequest, err.Error(), nil, nil))
+		return
+	}
+
+	user, _ := c.Get("userData")
+	claim, _ := user.(models.User)
+
+	comment, err := r.Service.PatchTicketComment(id, patch, version, claim)
+	if err != nil {
+		if errors.Is(err, domain.ErrVersionConflict) {
+			r.respondTicketCommentConflict(c, id, err)
+			return
+		}
+		status := serviceErrorStatus(err, http.StatusInternalServerError)
+		if status != http.StatusInternalServerError {
+			c.JSON(status, helpers.NewResponse(status, err.Error(), validationFields(err), nil))
+			return
+		}
+		c.JSON(http.StatusInternalServerError, helpers.NewResponse(http.StatusInternalServerError, "Failed to update comment", nil, nil))
+		return
+	}
+
+	resp := requests.TicketCommentResponse{
+		CommentID:     comment.ID,
+		TicketID:      comment.TicketID,
+		UserID:        comment.UserID,
+		IsiPesan:      comment.IsiPesan,
+		TanggalDibuat: comment.TanggalDibuat,
+		Version:       comment.Version,
+	}
+
+	setETag(c, comment.Version)
+	c.JSON(http.StatusOK, helpers.NewResponse(http.StatusOK, "Comment updated successfully", nil, resp))
+}
+
+// respondTicketCommentConflict answers an outdated comment update with the current comment
+func (r *appRoute) respondTicketCommentConflict(c *gin.Context, id int, err error) {
+	current, getErr := r.Service.GetTicketCommentByID(id)
+	if getErr != nil {
+		c.JSON(http.StatusConflict, helpers.NewResponse(http.StatusConflict, err.Error(), nil, nil))
+		return
+	}
+	respondVersionConflict(c, err, current.Version, requests.TicketCommentResponse{
+		CommentID:     current.ID,
+		TicketID:      current.TicketID,
+		UserID:        current.UserID,
+		IsiPesan:      current.IsiPesan,
+		TanggalDibuat: current.TanggalDibuat,
+		Version:       current.Version,
+	})
+}
+
 // DeleteTicketComment godoc
 // @Summary Delete a ticket comment
 // @Description Delete an existing comment (Admin and Support only)
@@ -243,7 +362,9 @@ func (r *appRoute) deleteTicketComment(c *gin.Context) {
 		return
 	}
 
-	if err := r.Service.DeleteTicketComment(id); err != nil {
+	user, _ := c.Get("userData")
+	claim, _ := user.(models.User)
+	if err := r.Service.DeleteTicketComment(id, claim); err != nil {
 		c.JSON(http.StatusInternalServerError, helpers.NewResponse(http.StatusInternalServerError, "Failed to delete comment", nil, nil))
 		return
 	}
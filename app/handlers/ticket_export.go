@@ -0,0 +1,74 @@
+package handlers
+
+import (
+	"app/domain"
+	"app/helpers"
+	"fmt"
+	"log"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/gin-gonic/gin"
+)
+
+var ticketExportContentTypes = map[domain.TicketExportFormat]string{
+	domain.TicketExportCSV:  "text/csv; charset=utf-8",
+	domain.TicketExportXLSX: "application/vnd.openxmlformats-officedocument.spreadsheetml.sheet",
+}
+
+// ExportTickets godoc
+// @Summary Export tickets
+// @Description Download the tickets matching the list filters as a CSV or XLSX file (Admin only), newest first. Rows carry the username, category, priority, status and assignee names.
+// @Tags tickets
+// @Produce text/csv
+// @Produce application/vnd.openxmlformats-officedocument.spreadsheetml.sheet
+// @Security BearerAuth
+// @Param format query string false "File format (csv, xlsx), default csv"
+// @Param code query string false "Filter by ticket code prefix, e.g. CUS-2026"
+// @Param role query string false "Filter by tipe_pengaduan (customer, seller, admin, support)"
+// @Param status query int false "Filter by status ID"
+// @Param priority query int false "Filter by priority ID"
+// @Param category query int false "Filter by category ID"
+// @Param min_reopen_count query int false "Filter by tickets reopened at least this many times"
+// @Param include_pending query bool false "Include snoozed tickets, left out unless filtering by status"
+// @Param sla query string false "Filter by SLA state (at_risk, breached)"
+// @Param tags query string false "Filter by comma separated tag IDs"
+// @Param tags_mode query string false "Match any (default) or all of the tags"
+// @Param cf.key query string false "Filter by a custom field value, e.g. cf.nomor_pesanan=INV-001"
+// @Success 200 {file} file
+// @Failure 400 {object} helpers.Response
+// @Router /tickets/export [get]
+func (r *appRoute) exportTickets(c *gin.Context) {
+	format := domain.TicketExportFormat(strings.ToLower(c.DefaultQuery("format", string(domain.TicketExportCSV))))
+	contentType, supported := ticketExportContentTypes[format]
+	if !supported {
+		response := helpers.NewResponse(http.StatusBadRequest, "Invalid format, use csv or xlsx", nil, nil)
+		c.JSON(http.StatusBadRequest, response)
+		return
+	}
+
+	filter, err := ticketFilterFromQuery(c)
+	if err != nil {
+		response := helpers.NewResponse(http.StatusBadRequest, err.Error(), nil, nil)
+		c.JSON(http.StatusBadRequest, response)
+		return
+	}
+
+	filename := fmt.Sprintf("tickets-%s.%s", time.Now().Format("20060102-150405"), format)
+	c.Header("Content-Type", contentType)
+	c.Header("Content-Disposition", fmt.Sprintf("attachment; filename=%q", filename))
+	c.Status(http.StatusOK)
+
+	if err := r.Service.ExportTickets(filter, format, c.Writer); err != nil {
+		log.Printf("[ticket-export] failed to export tickets as %s: %v", format, err)
+		if c.Writer.Written() {
+			// The download already started, cutting it short is all that is left
+			c.Abort()
+			return
+		}
+		c.Header("Content-Disposition", "")
+		response := helpers.NewResponse(http.StatusInternalServerError, "Failed to export tickets", nil, nil)
+		c.JSON(http.StatusInternalServerError, response)
+	}
+}
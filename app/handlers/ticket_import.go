@@ -0,0 +1,87 @@
+package handlers
+
+import (
+	"app/domain"
+	"app/domain/models"
+	"app/helpers"
+	"encoding/json"
+	"net/http"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+)
+
+// maxTicketImportSize is the largest CSV file accepted by the ticket import
+const maxTicketImportSize = 20 << 20
+
+// ImportTickets godoc
+// @Summary Import tickets from CSV
+// @Description Import tickets of a legacy helpdesk from a CSV file (Admin only), keeping the original dates. Columns are read by the ticket field they are named like (kode_tiket, user, judul, deskripsi, category, priority, status, tipe_pengaduan, tanggal_dibuat, tanggal_diperbarui, assignee, comment, comment_user, comment_date) unless mapping maps a field to another column, e.g. {"judul":"Subject"}. user, judul, category, priority, status and tanggal_dibuat are required. Rows sharing a kode_tiket belong to one ticket, each row may add a comment. Category, priority and status are matched by name, users by username or email. Nothing is imported when a row is invalid, dry_run only validates the rows.
+// @Tags tickets
+// @Accept multipart/form-data
+// @Produce json
+// @Security BearerAuth
+// @Param file formData file true "CSV file"
+// @Param mapping formData string false "JSON object mapping ticket fields to CSV columns"
+// @Param dry_run formData bool false "Only validate the rows"
+// @Success 200 {object} helpers.Response{data=domain.TicketImportResult} "Dry run"
+// @Success 201 {object} helpers.Response{data=domain.TicketImportResult}
+// @Failure 400 {object} helpers.Response{data=domain.TicketImportResult}
+// @Router /tickets/import [post]
+func (r *appRoute) importTickets(c *gin.Context) {
+	fileHeader, err := c.FormFile("file")
+	if err != nil {
+		response := helpers.NewResponse(http.StatusBadRequest, "No file uploaded", nil, nil)
+		c.JSON(http.StatusBadRequest, response)
+		return
+	}
+	if fileHeader.Size > maxTicketImportSize {
+		response := helpers.NewResponse(http.StatusBadRequest, "File is too large, split it into files of at most 20 MB", nil, nil)
+		c.JSON(http.StatusBadRequest, response)
+		return
+	}
+
+	var mapping domain.TicketImportMapping
+	if raw := c.PostForm("mapping"); raw != "" {
+		if err := json.Unmarshal([]byte(raw), &mapping); err != nil {
+			response := helpers.NewResponse(http.StatusBadRequest, "Invalid mapping, expected a JSON object of ticket fields to CSV columns", nil, nil)
+			c.JSON(http.StatusBadRequest, response)
+			return
+		}
+	}
+	dryRun, _ := strconv.ParseBool(c.PostForm("dry_run"))
+
+	file, err := fileHeader.Open()
+	if err != nil {
+		response := helpers.NewResponse(http.StatusBadRequest, "Failed to read the uploaded file", nil, nil)
+		c.JSON(http.StatusBadRequest, response)
+		return
+	}
+	defer file.Close()
+
+	user, _ := c.Get("userData")
+	claim, _ := user.(models.User)
+	result, err := r.Service.ImportTickets(file, mapping, dryRun, claim)
+	if err != nil {
+		status := serviceErrorStatus(err, http.StatusInternalServerError)
+		if status != http.StatusInternalServerError {
+			c.JSON(status, helpers.NewResponse(status, err.Error(), validationFields(err), nil))
+			return
+		}
+		response := helpers.NewResponse(http.StatusInternalServerError, "Failed to import tickets", nil, nil)
+		c.JSON(http.StatusInternalServerError, response)
+		return
+	}
+
+	switch {
+	case len(result.Errors) > 0 && !dryRun:
+		response := helpers.NewResponse(http.StatusBadRequest, "Import has invalid rows, nothing was imported", nil, result)
+		c.JSON(http.StatusBadRequest, response)
+	case dryRun:
+		response := helpers.NewResponse(http.StatusOK, "Import validated, nothing was imported", nil, result)
+		c.JSON(http.StatusOK, response)
+	default:
+		response := helpers.NewResponse(http.StatusCreated, "Tickets imported successfully", nil, result)
+		c.JSON(http.StatusCreated, response)
+	}
+}
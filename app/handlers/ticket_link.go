@@ -0,0 +1,179 @@
+package handlers
+
+import (
+	"app/app/middleware"
+	"app/domain/models"
+	"app/domain/requests"
+	"app/helpers"
+	"net/http"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+)
+
+func (r *appRoute) TicketLinkRoutes(rg *gin.RouterGroup) {
+	api := rg.Group("/tickets/:id/links")
+	api.Use(r.Middleware.Auth(), r.Middleware.RequireTicketAccess(middleware.TicketFromParam("id")))
+	api.GET("", r.getTicketLinks)
+	api.POST("", r.Middleware.RequireAdminOrSupport(), r.createTicketLink)
+	api.DELETE("/:link_id", r.Middleware.RequireAdminOrSupport(), r.deleteTicketLink)
+}
+
+// CreateTicketLink godoc
+// @Summary Link another ticket
+// @Description Link another ticket to this ticket (Admin and Support only). link_type is what the other ticket is to this one: parent, child, related, blocks or blocked_by. A ticket has at most one parent.
+// @Tags ticket-links
+// @Security BearerAuth
+// @Accept json
+// @Produce json
+// @Param id path int true "Ticket ID"
+// @Param link body requests.TicketLinkCreateRequest true "Link Data"
+// @Success 201 {object} helpers.Response{data=requests.TicketLinkResponse}
+// @Failure 400 {object} helpers.Response
+// @Failure 409 {object} helpers.Response
+// @Router /tickets/{id}/links [post]
+func (r *appRoute) createTicketLink(c *gin.Context) {
+	id, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		response := helpers.NewResponse(http.StatusBadRequest, "Invalid ticket ID", nil, nil)
+		c.JSON(http.StatusBadRequest, response)
+		return
+	}
+
+	var req requests.TicketLinkCreateRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		response := helpers.NewResponse(http.StatusBadRequest, "Invalid request body", nil, nil)
+		c.JSON(http.StatusBadRequest, response)
+		return
+	}
+
+	user, _ := c.Get("userData")
+	claim, _ := user.(models.User)
+
+	link, err := r.Service.CreateTicketLink(id, req.TicketID, req.LinkType, claim)
+	if err != nil {
+		status := serviceErrorStatus(err, http.StatusInternalServerError)
+		if status != http.StatusInternalServerError {
+			response := helpers.NewResponse(status, err.Error(), nil, nil)
+			c.JSON(status, response)
+			return
+		}
+		response := helpers.NewResponse(http.StatusInternalServerError, "Failed to create ticket link", nil, nil)
+		c.JSON(http.StatusInternalServerError, response)
+		return
+	}
+
+	// Reload the link with both tickets for the response
+	links, err := r.Service.GetTicketLinks(id)
+	if err != nil {
+		response := helpers.NewResponse(http.StatusInternalServerError, "Failed to get ticket links", nil, nil)
+		c.JSON(http.StatusInternalServerError, response)
+		return
+	}
+	var resp requests.TicketLinkResponse
+	for _, linked := range mapTicketLinks(id, links) {
+		if linked.LinkID == link.ID {
+			resp = linked
+		}
+	}
+
+	response := helpers.NewResponse(http.StatusCreated, "Ticket link created successfully", nil, resp)
+	c.JSON(http.StatusCreated, response)
+}
+
+// GetTicketLinks godoc
+// @Summary Get linked tickets
+// @Description Get the tickets linked to a ticket, link_type is what the linked ticket is to this one
+// @Tags ticket-links
+// @Security BearerAuth
+// @Produce json
+// @Param id path int true "Ticket ID"
+// @Success 200 {object} helpers.Response{data=[]requests.TicketLinkResponse}
+// @Router /tickets/{id}/links [get]
+func (r *appRoute) getTicketLinks(c *gin.Context) {
+	id, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		response := helpers.NewResponse(http.StatusBadRequest, "Invalid ticket ID", nil, nil)
+		c.JSON(http.StatusBadRequest, response)
+		return
+	}
+
+	links, err := r.Service.GetTicketLinks(id)
+	if err != nil {
+		response := helpers.NewResponse(http.StatusInternalServerError, "Failed to get ticket links", nil, nil)
+		c.JSON(http.StatusInternalServerError, response)
+		return
+	}
+
+	response := helpers.NewResponse(http.StatusOK, "Ticket links retrieved successfully", nil, mapTicketLinks(id, links))
+	c.JSON(http.StatusOK, response)
+}
+
+// DeleteTicketLink godoc
+// @Summary Remove a ticket link
+// @Description Remove a link between two tickets (Admin and Support only)
+// @Tags ticket-links
+// @Security BearerAuth
+// @Produce json
+// @Param id path int true "Ticket ID"
+// @Param link_id path int true "Link ID"
+// @Success 200 {object} helpers.Response
+// @Failure 404 {object} helpers.Response
+// @Router /tickets/{id}/links/{link_id} [delete]
+func (r *appRoute) deleteTicketLink(c *gin.Context) {
+	id, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		response := helpers.NewResponse(http.StatusBadRequest, "Invalid ticket ID", nil, nil)
+		c.JSON(http.StatusBadRequest, response)
+		return
+	}
+	linkID, err := strconv.Atoi(c.Param("link_id"))
+	if err != nil {
+		response := helpers.NewResponse(http.StatusBadRequest, "Invalid link ID", nil, nil)
+		c.JSON(http.StatusBadRequest, response)
+		return
+	}
+
+	user, _ := c.Get("userData")
+	claim, _ := user.(models.User)
+
+	if err := r.Service.DeleteTicketLink(id, linkID, claim); err != nil {
+		status := serviceErrorStatus(err, http.StatusInternalServerError)
+		response := helpers.NewResponse(status, "Failed to delete ticket link: "+err.Error(), nil, nil)
+		c.JSON(status, response)
+		return
+	}
+
+	response := helpers.NewResponse(http.StatusOK, "Ticket link deleted successfully", nil, nil)
+	c.JSON(http.StatusOK, response)
+}
+
+// mapTicketLinks maps the links of a ticket to the linked tickets as seen from that ticket
+func mapTicketLinks(ticketID int, links []models.TicketLink) []requests.TicketLinkResponse {
+	resp := make([]requests.TicketLinkResponse, 0, len(links))
+	for _, link := range links {
+		// Stored links describe the source from the target's side
+		linkType, other := link.LinkType, link.SourceTicket
+		if link.SourceTicketID == ticketID {
+			other = link.TargetTicket
+			switch link.LinkType {
+			case models.LinkTypeParent:
+				linkType = models.LinkTypeChild
+			case models.LinkTypeBlocks:
+				linkType = models.LinkTypeBlockedBy
+			}
+		}
+		if other == nil {
+			continue
+		}
+		resp = append(resp, requests.TicketLinkResponse{
+			LinkID:    link.ID,
+			LinkType:  linkType,
+			TicketID:  other.ID,
+			KodeTiket: other.KodeTiket,
+			Judul:     other.Judul,
+			StatusID:  other.StatusID,
+		})
+	}
+	return resp
+}
@@ -1,7 +1,9 @@
 package handlers
 
 import (
+	"app/app/middleware"
 	"app/domain/models"
+	"app/domain/requests"
 	"app/helpers"
 	"net/http"
 	"strconv"
@@ -12,30 +14,41 @@ import (
 func (r *appRoute) TicketLogRoutes(rg *gin.RouterGroup) {
 	api := rg.Group("/ticket-logs")
 	api.POST("", r.Middleware.Auth(), r.Middleware.RequireRole(models.RoleAdmin), r.createTicketLog)
-	api.GET("", r.getTicketLogs)
-	api.GET("/:id", r.getTicketLogByID)
+	api.GET("", r.Middleware.Auth(), r.Middleware.RequireTicketAccess(middleware.TicketFromQuery("ticket_id")), r.getTicketLogs)
+	api.GET("/:id", r.Middleware.Auth(), r.Middleware.RequireTicketAccess(middleware.LogTicket("id")), r.getTicketLogByID)
 }
 
 // CreateTicketLog godoc
-// @Summary Create a new ticket log
-// @Description Create a new activity log for a ticket
+// @Summary Add a note to a ticket log
+// @Description Add a manual note to the activity log of a ticket. Entries recording the mutations are written by the system and are read-only.
 // @Tags ticket-logs
 // @Accept json
 // @Security BearerAuth
 // @Produce json
-// @Param log body models.TicketLog true "Log Data"
+// @Param log body requests.CreateTicketLogRequest true "Log Data"
 // @Success 201 {object} helpers.Response{data=models.TicketLog}
 // @Failure 400 {object} helpers.Response
+// @Failure 403 {object} helpers.Response
+// @Failure 404 {object} helpers.Response
 // @Failure 500 {object} helpers.Response
 // @Router /ticket-logs [post]
 func (r *appRoute) createTicketLog(c *gin.Context) {
-	var log models.TicketLog
-	if err := c.ShouldBindJSON(&log); err != nil {
+	var req requests.CreateTicketLogRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
 		c.JSON(http.StatusBadRequest, helpers.NewResponse(http.StatusBadRequest, "Invalid request body", nil, nil))
 		return
 	}
 
-	if err := r.Service.CreateTicketLog(&log); err != nil {
+	user, _ := c.Get("userData")
+	claim, _ := user.(models.User)
+
+	log, err := r.Service.CreateTicketLog(req.TicketID, req.Aktivitas, claim)
+	if err != nil {
+		status := serviceErrorStatus(err, http.StatusInternalServerError)
+		if status != http.StatusInternalServerError {
+			c.JSON(status, helpers.NewResponse(status, err.Error(), validationFields(err), nil))
+			return
+		}
 		c.JSON(http.StatusInternalServerError, helpers.NewResponse(http.StatusInternalServerError, "Failed to create log", nil, nil))
 		return
 	}
@@ -45,11 +58,15 @@ func (r *appRoute) createTicketLog(c *gin.Context) {
 
 // GetTicketLogs godoc
 // @Summary Get all ticket logs
-// @Description Get all activity logs, optionally filtered by ticket ID
+// @Description Get the activity logs of a ticket the user may access. Without ticket_id all logs are returned, which is limited to admins.
 // @Tags ticket-logs
+// @Security BearerAuth
 // @Produce json
-// @Param ticket_id query int false "Filter by Ticket ID"
+// @Param ticket_id query int false "Filter by Ticket ID, required for non-admins"
 // @Success 200 {object} helpers.Response{data=[]models.TicketLog}
+// @Failure 400 {object} helpers.Response "Invalid ticket_id"
+// @Failure 403 {object} helpers.Response "No ticket_id and not an admin, or no access to the ticket"
+// @Failure 404 {object} helpers.Response
 // @Failure 500 {object} helpers.Response
 // @Router /ticket-logs [get]
 func (r *appRoute) getTicketLogs(c *gin.Context) {
@@ -15,6 +15,7 @@ func (r *appRoute) TicketPriorityRoutes(rg *gin.RouterGroup) {
 	api.GET("", r.getTicketPriorities)
 	api.GET("/:id", r.getTicketPriorityByID)
 	api.PUT("/:id", r.Middleware.Auth(), r.Middleware.RequireRole(models.RoleAdmin), r.updateTicketPriority)
+	api.PATCH("/:id", r.Middleware.Auth(), r.Middleware.RequireRole(models.RoleAdmin), r.patchTicketPriority)
 	api.DELETE("/:id", r.Middleware.Auth(), r.Middleware.RequireRole(models.RoleAdmin), r.deleteTicketPriority)
 }
 
@@ -129,6 +130,51 @@ func (r *appRoute) updateTicketPriority(c *gin.Context) {
 	c.JSON(http.StatusOK, response)
 }
 
+// PatchTicketPriority godoc
+// @Summary Partially update a ticket priority
+// @Description Apply a JSON merge patch (RFC 7386) to a ticket priority, the patchable field is nama_priority
+// @Tags ticket-priorities
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param id path int true "Priority ID"
+// @Param patch body object true "Fields to change"
+// @Success 200 {object} helpers.Response{data=models.TicketPriority}
+// @Failure 400 {object} helpers.Response
+// @Failure 404 {object} helpers.Response
+// @Router /ticket-priorities/{id} [patch]
+func (r *appRoute) patchTicketPriority(c *gin.Context) {
+	id, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		response := helpers.NewResponse(http.StatusBadRequest, "Invalid priority ID", nil, nil)
+		c.JSON(http.StatusBadRequest, response)
+		return
+	}
+
+	patch, err := c.GetRawData()
+	if err != nil {
+		response := helpers.NewResponse(http.StatusBadRequest, "Invalid request body", nil, nil)
+		c.JSON(http.StatusBadRequest, response)
+		return
+	}
+
+	priority, err := r.Service.PatchTicketPriority(id, patch)
+	if err != nil {
+		status := serviceErrorStatus(err, http.StatusInternalServerError)
+		if status != http.StatusInternalServerError {
+			response := helpers.NewResponse(status, err.Error(), validationFields(err), nil)
+			c.JSON(status, response)
+			return
+		}
+		response := helpers.NewResponse(http.StatusInternalServerError, "Failed to update ticket priority", nil, nil)
+		c.JSON(http.StatusInternalServerError, response)
+		return
+	}
+
+	response := helpers.NewResponse(http.StatusOK, "Ticket priority updated successfully", nil, priority)
+	c.JSON(http.StatusOK, response)
+}
+
 // DeleteTicketPriority godoc
 // @Summary Delete a ticket priority
 // @Description Delete a ticket priority by its ID
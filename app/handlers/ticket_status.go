@@ -15,6 +15,7 @@ func (r *appRoute) TicketStatusRoutes(rg *gin.RouterGroup) {
 	api.GET("", r.Middleware.Auth(), r.Middleware.RequireRole(models.RoleAdmin), r.getTicketStatuses)
 	api.GET("/:id", r.getTicketStatusByID)
 	api.PUT("/:id", r.Middleware.Auth(), r.Middleware.RequireRole(models.RoleAdmin), r.updateTicketStatus)
+	api.PATCH("/:id", r.Middleware.Auth(), r.Middleware.RequireRole(models.RoleAdmin), r.patchTicketStatus)
 	api.DELETE("/:id", r.Middleware.Auth(), r.Middleware.RequireRole(models.RoleAdmin), r.deleteTicketStatus)
 }
 
@@ -129,6 +130,51 @@ func (r *appRoute) updateTicketStatus(c *gin.Context) {
 	c.JSON(http.StatusOK, response)
 }
 
+// PatchTicketStatus godoc
+// @Summary Partially update a ticket status
+// @Description Apply a JSON merge patch (RFC 7386) to a ticket status, patchable fields are nama_status, is_initial, is_terminal and pauses_sla
+// @Tags ticket-statuses
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param id path int true "Status ID"
+// @Param patch body object true "Fields to change"
+// @Success 200 {object} helpers.Response{data=models.TicketStatus}
+// @Failure 400 {object} helpers.Response
+// @Failure 404 {object} helpers.Response
+// @Router /ticket-statuses/{id} [patch]
+func (r *appRoute) patchTicketStatus(c *gin.Context) {
+	id, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		response := helpers.NewResponse(http.StatusBadRequest, "Invalid status ID", nil, nil)
+		c.JSON(http.StatusBadRequest, response)
+		return
+	}
+
+	patch, err := c.GetRawData()
+	if err != nil {
+		response := helpers.NewResponse(http.StatusBadRequest, "Invalid request body", nil, nil)
+		c.JSON(http.StatusBadRequest, response)
+		return
+	}
+
+	status, err := r.Service.PatchTicketStatus(id, patch)
+	if err != nil {
+		status := serviceErrorStatus(err, http.StatusInternalServerError)
+		if status != http.StatusInternalServerError {
+			response := helpers.NewResponse(status, err.Error(), validationFields(err), nil)
+			c.JSON(status, response)
+			return
+		}
+		response := helpers.NewResponse(http.StatusInternalServerError, "Failed to update ticket status", nil, nil)
+		c.JSON(http.StatusInternalServerError, response)
+		return
+	}
+
+	response := helpers.NewResponse(http.StatusOK, "Ticket status updated successfully", nil, status)
+	c.JSON(http.StatusOK, response)
+}
+
 // DeleteTicketStatus godoc
 // @Summary Delete a ticket status
 // @Description Delete a ticket status by its ID
@@ -0,0 +1,98 @@
+package handlers
+
+import (
+	"app/domain/models"
+	"app/helpers"
+	"net/http"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+)
+
+func (r *appRoute) TicketStatusTransitionRoutes(rg *gin.RouterGroup) {
+	api := rg.Group("/ticket-status-transitions")
+	api.Use(r.Middleware.Auth(), r.Middleware.RequireRole(models.RoleAdmin))
+	api.POST("", r.createTicketStatusTransition)
+	api.GET("", r.getTicketStatusTransitions)
+	api.DELETE("/:id", r.deleteTicketStatusTransition)
+}
+
+// CreateTicketStatusTransition godoc
+// @Summary Create a new ticket status transition
+// @Description Allow tickets to move from one status to another on a workflow event (manual, assign, comment). An empty allowed_roles allows every role.
+// @Tags ticket-status-transitions
+// @Accept json
+// @Security BearerAuth
+// @Produce json
+// @Param transition body models.TicketStatusTransition true "Transition Data"
+// @Success 201 {object} helpers.Response{data=models.TicketStatusTransition}
+// @Failure 400 {object} helpers.Response
+// @Failure 500 {object} helpers.Response
+// @Router /ticket-status-transitions [post]
+func (r *appRoute) createTicketStatusTransition(c *gin.Context) {
+	var transition models.TicketStatusTransition
+	if err := c.ShouldBindJSON(&transition); err != nil {
+		response := helpers.NewResponse(http.StatusBadRequest, "Invalid request body", nil, nil)
+		c.JSON(http.StatusBadRequest, response)
+		return
+	}
+
+	if err := r.Service.CreateTicketStatusTransition(&transition); err != nil {
+		response := helpers.NewResponse(http.StatusInternalServerError, "Failed to create ticket status transition: "+err.Error(), nil, nil)
+		c.JSON(http.StatusInternalServerError, response)
+		return
+	}
+
+	response := helpers.NewResponse(http.StatusCreated, "Ticket status transition created successfully", nil, transition)
+	c.JSON(http.StatusCreated, response)
+}
+
+// GetTicketStatusTransitions godoc
+// @Summary Get ticket status transitions
+// @Description Get the configured ticket workflow, optionally only the transitions leaving a status
+// @Tags ticket-status-transitions
+// @Security BearerAuth
+// @Produce json
+// @Param from_status_id query int false "Filter by source status ID"
+// @Success 200 {object} helpers.Response{data=[]models.TicketStatusTransition}
+// @Router /ticket-status-transitions [get]
+func (r *appRoute) getTicketStatusTransitions(c *gin.Context) {
+	fromStatusID, _ := strconv.Atoi(c.Query("from_status_id"))
+
+	transitions, err := r.Service.GetTicketStatusTransitions(fromStatusID)
+	if err != nil {
+		response := helpers.NewResponse(http.StatusInternalServerError, "Failed to get ticket status transitions", nil, nil)
+		c.JSON(http.StatusInternalServerError, response)
+		return
+	}
+
+	response := helpers.NewResponse(http.StatusOK, "Ticket status transitions retrieved successfully", nil, transitions)
+	c.JSON(http.StatusOK, response)
+}
+
+// DeleteTicketStatusTransition godoc
+// @Summary Delete a ticket status transition
+// @Description Delete a ticket status transition by its ID
+// @Tags ticket-status-transitions
+// @Security BearerAuth
+// @Produce json
+// @Param id path int true "Transition ID"
+// @Success 200 {object} helpers.Response
+// @Router /ticket-status-transitions/{id} [delete]
+func (r *appRoute) deleteTicketStatusTransition(c *gin.Context) {
+	id, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		response := helpers.NewResponse(http.StatusBadRequest, "Invalid transition ID", nil, nil)
+		c.JSON(http.StatusBadRequest, response)
+		return
+	}
+
+	if err := r.Service.DeleteTicketStatusTransition(id); err != nil {
+		response := helpers.NewResponse(http.StatusInternalServerError, "Failed to delete ticket status transition", nil, nil)
+		c.JSON(http.StatusInternalServerError, response)
+		return
+	}
+
+	response := helpers.NewResponse(http.StatusOK, "Ticket status transition deleted successfully", nil, nil)
+	c.JSON(http.StatusOK, response)
+}
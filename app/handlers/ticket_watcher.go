@@ -0,0 +1,113 @@
+package handlers
+
+import (
+	"app/app/middleware"
+	"app/domain"
+	"app/domain/models"
+	"app/helpers"
+	"net/http"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+)
+
+func (r *appRoute) TicketWatcherRoutes(rg *gin.RouterGroup) {
+	api := rg.Group("/tickets/:id/watchers")
+	api.Use(r.Middleware.Auth(), r.Middleware.RequireRole(models.RoleAdmin, models.RoleSupport, models.RoleSeller))
+
+	// Support users may follow any ticket, sellers the tickets they filed or are assigned to
+	watchAccess := r.Middleware.RequireTicketPolicy(middleware.TicketFromParam("id"), domain.CanWatchTicket)
+	api.GET("", r.Middleware.RequireTicketAccess(middleware.TicketFromParam("id")), r.getTicketWatchers)
+	api.POST("", watchAccess, r.watchTicket)
+	api.DELETE("", watchAccess, r.unwatchTicket)
+}
+
+// WatchTicket godoc
+// @Summary Follow a ticket
+// @Description Follow a ticket to be notified by email and websocket of comments, status changes and assignments. Admins and support users may follow any ticket, sellers the tickets they filed or are assigned to.
+// @Tags ticket-watchers
+// @Security BearerAuth
+// @Produce json
+// @Param id path int true "Ticket ID"
+// @Success 200 {object} helpers.Response
+// @Failure 404 {object} helpers.Response
+// @Router /tickets/{id}/watchers [post]
+func (r *appRoute) watchTicket(c *gin.Context) {
+	id, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		response := helpers.NewResponse(http.StatusBadRequest, "Invalid ticket ID", nil, nil)
+		c.JSON(http.StatusBadRequest, response)
+		return
+	}
+
+	user, _ := c.Get("userData")
+	claim, _ := user.(models.User)
+
+	if err := r.Service.WatchTicket(id, claim); err != nil {
+		status := serviceErrorStatus(err, http.StatusInternalServerError)
+		response := helpers.NewResponse(status, "Failed to follow ticket: "+err.Error(), nil, nil)
+		c.JSON(status, response)
+		return
+	}
+
+	response := helpers.NewResponse(http.StatusOK, "Ticket followed successfully", nil, nil)
+	c.JSON(http.StatusOK, response)
+}
+
+// UnwatchTicket godoc
+// @Summary Unfollow a ticket
+// @Description Stop following a ticket
+// @Tags ticket-watchers
+// @Security BearerAuth
+// @Produce json
+// @Param id path int true "Ticket ID"
+// @Success 200 {object} helpers.Response
+// @Router /tickets/{id}/watchers [delete]
+func (r *appRoute) unwatchTicket(c *gin.Context) {
+	id, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		response := helpers.NewResponse(http.StatusBadRequest, "Invalid ticket ID", nil, nil)
+		c.JSON(http.StatusBadRequest, response)
+		return
+	}
+
+	user, _ := c.Get("userData")
+	claim, _ := user.(models.User)
+
+	if err := r.Service.UnwatchTicket(id, claim); err != nil {
+		response := helpers.NewResponse(http.StatusInternalServerError, "Failed to unfollow ticket", nil, nil)
+		c.JSON(http.StatusInternalServerError, response)
+		return
+	}
+
+	response := helpers.NewResponse(http.StatusOK, "Ticket unfollowed successfully", nil, nil)
+	c.JSON(http.StatusOK, response)
+}
+
+// GetTicketWatchers godoc
+// @Summary Get ticket followers
+// @Description Get the users following a ticket
+// @Tags ticket-watchers
+// @Security BearerAuth
+// @Produce json
+// @Param id path int true "Ticket ID"
+// @Success 200 {object} helpers.Response{data=[]models.TicketWatcher}
+// @Router /tickets/{id}/watchers [get]
+func (r *appRoute) getTicketWatchers(c *gin.Context) {
+	id, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		response := helpers.NewResponse(http.StatusBadRequest, "Invalid ticket ID", nil, nil)
+		c.JSON(http.StatusBadRequest, response)
+		return
+	}
+
+	watchers, err := r.Service.GetTicketWatchers(id)
+	if err != nil {
+		response := helpers.NewResponse(http.StatusInternalServerError, "Failed to get ticket followers", nil, nil)
+		c.JSON(http.StatusInternalServerError, response)
+		return
+	}
+
+	response := helpers.NewResponse(http.StatusOK, "Ticket followers retrieved successfully", nil, watchers)
+	c.JSON(http.StatusOK, response)
+}
@@ -9,4 +9,7 @@ type AppMiddleware interface {
     Auth() gin.HandlerFunc
     RequireRole(allowedRoles ...models.UserRole) gin.HandlerFunc
     RequireAdminOrSupport() gin.HandlerFunc
-}
\ No newline at end of file
+    RequireTicketAccess(resolve TicketResolver) gin.HandlerFunc
+    RequireTicketPolicy(resolve TicketResolver, policy TicketPolicy) gin.HandlerFunc
+    AuthorizeTicket(c *gin.Context, ticketID int) bool
+}
@@ -0,0 +1,182 @@
+package middleware
+
+import (
+	"app/domain"
+	"app/domain/models"
+	"app/helpers"
+	"errors"
+	"net/http"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+)
+
+var errInvalidTicketReference = errors.New("invalid ticket reference")
+
+// TicketResolver finds the ticket a request is about. It returns 0 when the request names
+// no ticket and an error when the referenced record is invalid or does not exist.
+type TicketResolver func(c *gin.Context, repo domain.AppRepository) (int, error)
+
+// TicketPolicy decides whether the user may act on the ticket, assignment is nil for an
+// unassigned ticket
+type TicketPolicy func(user models.User, ticket *models.Ticket, assignment *models.TicketAssignment) bool
+
+// RequireTicketAccess lets the request through when the user may access the ticket found by
+// resolve, see domain.CanAccessTicket. A request that names no ticket is limited to admins.
+// It must run after Auth.
+func (m *appMiddleware) RequireTicketAccess(resolve TicketResolver) gin.HandlerFunc {
+	return m.RequireTicketPolicy(resolve, domain.CanAccessTicket)
+}
+
+// RequireTicketPolicy works like RequireTicketAccess with another policy, for actions that
+// are open to more users than the ticket itself
+func (m *appMiddleware) RequireTicketPolicy(resolve TicketResolver, policy TicketPolicy) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		ticketID, err := resolve(c, m.repository)
+		if err != nil {
+			status := http.StatusNotFound
+			if errors.Is(err, errInvalidTicketReference) {
+				status = http.StatusBadRequest
+			}
+			c.AbortWithStatusJSON(status, helpers.NewResponse(status, err.Error(), nil, nil))
+			return
+		}
+
+		if !m.authorizeTicket(c, ticketID, policy) {
+			return
+		}
+		c.Next()
+	}
+}
+
+// AuthorizeTicket applies the ticket policy from within a handler, for requests naming the
+// ticket in their body. A merged ticket is authorized as the ticket it was merged into. It
+// aborts with 403 or 404 and returns false when access is denied.
+func (m *appMiddleware) AuthorizeTicket(c *gin.Context, ticketID int) bool {
+	return m.authorizeTicket(c, ticketID, domain.CanAccessTicket)
+}
+
+func (m *appMiddleware) authorizeTicket(c *gin.Context, ticketID int, policy TicketPolicy) bool {
+	userData, _ := c.Get("userData")
+	user, ok := userData.(models.User)
+	if !ok {
+		c.AbortWithStatusJSON(http.StatusUnauthorized, helpers.NewResponse(http.StatusUnauthorized, "Unauthorized", nil, nil))
+		return false
+	}
+
+	if ticketID == 0 {
+		if user.Role != models.RoleAdmin {
+			c.AbortWithStatusJSON(http.StatusForbidden, helpers.NewResponse(http.StatusForbidden, "Access denied. Insufficient permissions", nil, nil))
+			return false
+		}
+		return true
+	}
+
+	ticket, err := m.repository.GetTicketByID(ticketID)
+	// A merged ticket is served as the ticket it was merged into, which is the one to authorize
+	for depth := 0; err == nil && ticket.MergedIntoID != nil && depth < models.MaxMergeDepth; depth++ {
+		ticket, err = m.repository.GetTicketByID(*ticket.MergedIntoID)
+	}
+	if err != nil {
+		c.AbortWithStatusJSON(http.StatusNotFound, helpers.NewResponse(http.StatusNotFound, "Ticket not found", nil, nil))
+		return false
+	}
+
+	// An unassigned ticket has no assignment row
+	assignment, err := m.repository.GetTicketAssignmentByTicketID(ticket.ID)
+	if err != nil {
+		assignment = nil
+	}
+
+	if !policy(user, ticket, assignment) {
+		c.AbortWithStatusJSON(http.StatusForbidden, helpers.NewResponse(http.StatusForbidden, "Access denied. You are not allowed to access this ticket", nil, nil))
+		return false
+	}
+	return true
+}
+
+// TicketFromParam resolves the ticket ID in a path parameter
+func TicketFromParam(name string) TicketResolver {
+	return func(c *gin.Context, _ domain.AppRepository) (int, error) {
+		id, err := strconv.Atoi(c.Param(name))
+		if err != nil || id <= 0 {
+			return 0, errInvalidTicketReference
+		}
+		return id, nil
+	}
+}
+
+// TicketFromQuery resolves an optional ticket ID filter in the query string
+func TicketFromQuery(name string) TicketResolver {
+	return func(c *gin.Context, _ domain.AppRepository) (int, error) {
+		value := c.Query(name)
+		if value == "" {
+			return 0, nil
+		}
+		id, err := strconv.Atoi(value)
+		if err != nil || id <= 0 {
+			return 0, errInvalidTicketReference
+		}
+		return id, nil
+	}
+}
+
+// AttachmentTicket resolves the ticket of the attachment in a path parameter
+func AttachmentTicket(name string) TicketResolver {
+	return func(c *gin.Context, repo domain.AppRepository) (int, error) {
+		id, err := strconv.Atoi(c.Param(name))
+		if err != nil {
+			return 0, errInvalidTicketReference
+		}
+		attachment, err := repo.GetTicketAttachmentByID(id)
+		if err != nil {
+			return 0, errors.New("attachment not found")
+		}
+		return attachment.TicketID, nil
+	}
+}
+
+// CommentTicket resolves the ticket of the comment in a path parameter
+func CommentTicket(name string) TicketResolver {
+	return func(c *gin.Context, repo domain.AppRepository) (int, error) {
+		id, err := strconv.Atoi(c.Param(name))
+		if err != nil {
+			return 0, errInvalidTicketReference
+		}
+		comment, err := repo.GetTicketCommentByID(id)
+		if err != nil {
+			return 0, errors.New("comment not found")
+		}
+		return comment.TicketID, nil
+	}
+}
+
+// LogTicket resolves the ticket of the log entry in a path parameter
+func LogTicket(name string) TicketResolver {
+	return func(c *gin.Context, repo domain.AppRepository) (int, error) {
+		id, err := strconv.Atoi(c.Param(name))
+		if err != nil {
+			return 0, errInvalidTicketReference
+		}
+		entry, err := repo.GetTicketLogByID(id)
+		if err != nil {
+			return 0, errors.New("log not found")
+		}
+		return entry.TicketID, nil
+	}
+}
+
+// AssignmentTicket resolves the ticket of the assignment in a path parameter
+func AssignmentTicket(name string) TicketResolver {
+	return func(c *gin.Context, repo domain.AppRepository) (int, error) {
+		id, err := strconv.Atoi(c.Param(name))
+		if err != nil {
+			return 0, errInvalidTicketReference
+		}
+		assignment, err := repo.GetTicketAssignmentByID(id)
+		if err != nil {
+			return 0, errors.New("assignment not found")
+		}
+		return assignment.TicketID, nil
+	}
+}
@@ -0,0 +1,122 @@
+package middleware
+
+import (
+	"app/domain"
+	"app/domain/models"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+)
+
+// policyRepository serves the tickets and assignments the policy reads, every other
+// repository method is left unimplemented
+type policyRepository struct {
+	domain.AppRepository
+	tickets     map[int]*models.Ticket
+	assignments map[int]*models.TicketAssignment
+}
+
+func (r *policyRepository) GetTicketByID(id int) (*models.Ticket, error) {
+	ticket, ok := r.tickets[id]
+	if !ok {
+		return nil, errors.New("record not found")
+	}
+	return ticket, nil
+}
+
+func (r *policyRepository) GetTicketAssignmentByTicketID(ticketID int) (*models.TicketAssignment, error) {
+	assignment, ok := r.assignments[ticketID]
+	if !ok {
+		return nil, errors.New("record not found")
+	}
+	return assignment, nil
+}
+
+func TestRequireTicketAccess(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	mergedInto := 1
+	m := &appMiddleware{repository: &policyRepository{
+		tickets: map[int]*models.Ticket{
+			1: {ID: 1, UserID: 10},
+			2: {ID: 2, UserID: 30, MergedIntoID: &mergedInto},
+		},
+		assignments: map[int]*models.TicketAssignment{
+			1: {TicketID: 1, AdminID: 20},
+		},
+	}}
+
+	tests := []struct {
+		name   string
+		path   string
+		user   *models.User
+		status int
+	}{
+		{"invalid ticket ID", "/tickets/abc", &models.User{ID: 10, Role: models.RoleCustomer}, http.StatusBadRequest},
+		{"unknown ticket", "/tickets/99", &models.User{ID: 10, Role: models.RoleCustomer}, http.StatusNotFound},
+		{"unrelated user", "/tickets/1", &models.User{ID: 30, Role: models.RoleCustomer}, http.StatusForbidden},
+		{"owner of merged ticket", "/tickets/2", &models.User{ID: 30, Role: models.RoleCustomer}, http.StatusForbidden},
+		{"without user", "/tickets/1", nil, http.StatusUnauthorized},
+		{"owner", "/tickets/1", &models.User{ID: 10, Role: models.RoleCustomer}, http.StatusOK},
+		{"assignee", "/tickets/1", &models.User{ID: 20, Role: models.RoleSupport}, http.StatusOK},
+		{"admin", "/tickets/1", &models.User{ID: 1, Role: models.RoleAdmin}, http.StatusOK},
+		{"admin on unknown ticket", "/tickets/99", &models.User{ID: 1, Role: models.RoleAdmin}, http.StatusNotFound},
+		{"merged ticket as the primary owner", "/tickets/2", &models.User{ID: 10, Role: models.RoleCustomer}, http.StatusOK},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			router := gin.New()
+			router.GET("/tickets/:id", func(c *gin.Context) {
+				if tt.user != nil {
+					c.Set("userData", *tt.user)
+				}
+				c.Next()
+			}, m.RequireTicketAccess(TicketFromParam("id")), func(c *gin.Context) {
+				c.Status(http.StatusOK)
+			})
+
+			w := httptest.NewRecorder()
+			router.ServeHTTP(w, httptest.NewRequest(http.MethodGet, tt.path, nil))
+			if w.Code != tt.status {
+				t.Errorf("status = %d, want %d", w.Code, tt.status)
+			}
+		})
+	}
+}
+
+func TestRequireTicketAccessWithoutTicket(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	m := &appMiddleware{repository: &policyRepository{}}
+
+	tests := []struct {
+		name   string
+		role   models.UserRole
+		status int
+	}{
+		{"admin", models.RoleAdmin, http.StatusOK},
+		{"support", models.RoleSupport, http.StatusForbidden},
+		{"customer", models.RoleCustomer, http.StatusForbidden},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			router := gin.New()
+			router.GET("/logs", func(c *gin.Context) {
+				c.Set("userData", models.User{ID: 5, Role: tt.role})
+				c.Next()
+			}, m.RequireTicketAccess(TicketFromQuery("ticket_id")), func(c *gin.Context) {
+				c.Status(http.StatusOK)
+			})
+
+			w := httptest.NewRecorder()
+			router.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/logs", nil))
+			if w.Code != tt.status {
+				t.Errorf("status = %d, want %d", w.Code, tt.status)
+			}
+		})
+	}
+}
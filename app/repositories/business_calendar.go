@@ -0,0 +1,97 @@
+package repositories
+
+import (
+	"app/domain/models"
+
+	"gorm.io/gorm"
+)
+
+func (r *appRepository) CreateBusinessCalendar(calendar *models.BusinessCalendar) error {
+	return r.Conn.Transaction(func(tx *gorm.DB) error {
+		if calendar.IsDefault {
+			if err := tx.Model(&models.BusinessCalendar{}).Where("is_default = ?", true).Update("is_default", false).Error; err != nil {
+				return err
+			}
+		}
+		return tx.Create(calendar).Error
+	})
+}
+
+func (r *appRepository) GetBusinessCalendars() ([]models.BusinessCalendar, error) {
+	var calendars []models.BusinessCalendar
+	err := r.Conn.Preload("Hours").Preload("Holidays").Order("id_calendar asc").Find(&calendars).Error
+	return calendars, err
+}
+
+func (r *appRepository) GetBusinessCalendarByID(id int) (*models.BusinessCalendar, error) {
+	var calendar models.BusinessCalendar
+	err := r.Conn.Preload("Hours").Preload("Holidays").First(&calendar, id).Error
+	if err != nil {
+		return nil, err
+	}
+	return &calendar, nil
+}
+
+func (r *appRepository) GetDefaultBusinessCalendar() (*models.BusinessCalendar, error) {
+	var calendar models.BusinessCalendar
+	err := r.Conn.Preload("Hours").Preload("Holidays").Where("is_default = ?", true).First(&calendar).Error
+	if err != nil {
+		return nil, err
+	}
+	return &calendar, nil
+}
+
+// UpdateBusinessCalendar saves the calendar and replaces its working hours, holidays are
+// managed through their own endpoints
+func (r *appRepository) UpdateBusinessCalendar(calendar *models.BusinessCalendar) error {
+	return r.Conn.Transaction(func(tx *gorm.DB) error {
+		if calendar.IsDefault {
+			if err := tx.Model(&models.BusinessCalendar{}).Where("is_default = ? AND id_calendar <> ?", true, calendar.ID).Update("is_default", false).Error; err != nil {
+				return err
+			}
+		}
+		if err := tx.Model(&models.BusinessCalendar{}).Where("id_calendar = ?", calendar.ID).Updates(map[string]interface{}{
+			"nama_calendar": calendar.NamaCalendar,
+			"timezone":      calendar.Timezone,
+			"is_default":    calendar.IsDefault,
+		}).Error; err != nil {
+			return err
+		}
+		if err := tx.Where("id_calendar = ?", calendar.ID).Delete(&models.BusinessHour{}).Error; err != nil {
+			return err
+		}
+		for i := range calendar.Hours {
+			calendar.Hours[i].ID = 0
+			calendar.Hours[i].CalendarID = calendar.ID
+		}
+		if len(calendar.Hours) > 0 {
+			if err := tx.Create(&calendar.Hours).Error; err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+}
+
+func (r *appRepository) DeleteBusinessCalendar(id int) error {
+	return r.Conn.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Model(&models.SLAPolicy{}).Where("id_calendar = ?", id).Update("id_calendar", nil).Error; err != nil {
+			return err
+		}
+		if err := tx.Where("id_calendar = ?", id).Delete(&models.BusinessHour{}).Error; err != nil {
+			return err
+		}
+		if err := tx.Where("id_calendar = ?", id).Delete(&models.Holiday{}).Error; err != nil {
+			return err
+		}
+		return tx.Delete(&models.BusinessCalendar{}, id).Error
+	})
+}
+
+func (r *appRepository) CreateHoliday(holiday *models.Holiday) error {
+	return r.Conn.Create(holiday).Error
+}
+
+func (r *appRepository) DeleteHoliday(calendarID, holidayID int) error {
+	return r.Conn.Where("id_calendar = ? AND id_holiday = ?", calendarID, holidayID).Delete(&models.Holiday{}).Error
+}
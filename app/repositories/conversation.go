@@ -1,6 +1,7 @@
 package repositories
 
 import (
+	"app/domain"
 	"app/domain/models"
 	"context"
 	"time"
@@ -11,9 +12,10 @@ func (r *appRepository) CreateConversation(ctx context.Context, conversation *mo
 	return err
 }
 
-func (r *appRepository) GetAdminConversations(adminID uint64) ([]models.Conversation, error) {
+func (r *appRepository) GetAdminConversations(adminID uint64, tags domain.TagFilter) ([]models.Conversation, error) {
 	var conversations []models.Conversation
-	err := r.Conn.Where("admin_id = ?", adminID).Order("last_message_at DESC").Find(&conversations).Error
+	db := applyTagFilter(r.Conn.Preload("Tags"), tags, "conversation_tags", "conversation_id", "conversations.id")
+	err := db.Where("admin_id = ?", adminID).Order("last_message_at DESC").Find(&conversations).Error
 	return conversations, err
 }
 
@@ -29,9 +31,10 @@ func (r *appRepository) ReopenConversation(ctx context.Context, conversationID u
 		Update("status", models.StatusOpen).Error
 }
 
-func (r *appRepository) GetCustomerConversations(customerID uint64) ([]models.Conversation, error) {
+func (r *appRepository) GetCustomerConversations(customerID uint64, tags domain.TagFilter) ([]models.Conversation, error) {
 	var conversations []models.Conversation
-	err := r.Conn.Where("customer_id = ?", customerID).
+	db := applyTagFilter(r.Conn.Preload("Tags"), tags, "conversation_tags", "conversation_id", "conversations.id")
+	err := db.Where("customer_id = ?", customerID).
 		Order("last_message_at DESC").
 		Find(&conversations).Error
 	return conversations, err
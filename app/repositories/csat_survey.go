@@ -0,0 +1,96 @@
+package repositories
+
+import (
+	"app/domain"
+	"app/domain/models"
+	"time"
+)
+
+func (r *appRepository) CreateCSATSurvey(survey *models.CSATSurvey) error {
+	return r.Conn.Create(survey).Error
+}
+
+func (r *appRepository) GetCSATSurveyByID(id int) (*models.CSATSurvey, error) {
+	var survey models.CSATSurvey
+	err := r.Conn.Preload("Ticket").Preload("Assignee").First(&survey, id).Error
+	if err != nil {
+		return nil, err
+	}
+	return &survey, nil
+}
+
+// AnswerCSATSurvey stores the rating unless the survey was answered already, the returned
+// bool tells whether this call stored it
+func (r *appRepository) AnswerCSATSurvey(id int, rating int, comment string, at time.Time) (bool, error) {
+	result := r.Conn.Model(&models.CSATSurvey{}).
+		Where("id_survey = ? AND responded_at IS NULL", id).
+		Updates(map[string]interface{}{
+			"rating":       rating,
+			"comment":      comment,
+			"responded_at": at,
+		})
+	return result.RowsAffected > 0, result.Error
+}
+
+// GetCSATAggregates aggregates the answered surveys per assignee, category or period
+func (r *appRepository) GetCSATAggregates(filter domain.CSATFilter) ([]domain.CSATAggregate, error) {
+	db := r.Conn.Table("csat_surveys").Where("csat_surveys.responded_at IS NOT NULL")
+	if !filter.From.IsZero() {
+		db = db.Where("csat_surveys.responded_at >= ?", filter.From)
+	}
+	if !filter.To.IsZero() {
+		db = db.Where("csat_surveys.responded_at < ?", filter.To)
+	}
+	if filter.AssigneeID > 0 {
+		db = db.Where("csat_surveys.id_assignee = ?", filter.AssigneeID)
+	}
+	if filter.CategoryID > 0 {
+		db = db.Where("csat_surveys.id_category = ?", filter.CategoryID)
+	}
+
+	const measures = `COUNT(*) AS responses,
+		AVG(csat_surveys.rating) AS average_rating,
+		COUNT(*) FILTER (WHERE csat_surveys.rating >= ?) AS satisfied`
+
+	switch filter.GroupBy {
+	case domain.CSATGroupByAgent:
+		db = db.Joins("LEFT JOIN users ON users.id = csat_surveys.id_assignee").
+			Select("COALESCE(CAST(csat_surveys.id_assignee AS TEXT), '') AS group_key, COALESCE(users.username, '') AS group_label, "+measures, models.CSATSatisfiedRating)
+	case domain.CSATGroupByCategory:
+		db = db.Joins("LEFT JOIN ticket_categories ON ticket_categories.id_category = csat_surveys.id_category").
+			Select("CAST(csat_surveys.id_category AS TEXT) AS group_key, COALESCE(ticket_categories.nama_category, '') AS group_label, "+measures, models.CSATSatisfiedRating)
+	default:
+		period := filter.Period
+		if period == "" {
+			period = domain.CSATPeriodMonth
+		}
+		db = db.Select("TO_CHAR(DATE_TRUNC(?, csat_surveys.responded_at), 'YYYY-MM-DD') AS group_key, TO_CHAR(DATE_TRUNC(?, csat_surveys.responded_at), 'YYYY-MM-DD') AS group_label, "+measures, period, period, models.CSATSatisfiedRating)
+	}
+
+	var rows []struct {
+		GroupKey      string
+		GroupLabel    string
+		Responses     int
+		AverageRating float64
+		Satisfied     int
+	}
+	if err := db.Group("group_key, group_label").Order("group_key asc").Scan(&rows).Error; err != nil {
+		return nil, err
+	}
+
+	aggregates := make([]domain.CSATAggregate, 0, len(rows))
+	for _, row := range rows {
+		aggregate := domain.CSATAggregate{
+			Key:           row.GroupKey,
+			Label:         row.GroupLabel,
+			Responses:     row.Responses,
+			AverageRating: row.AverageRating,
+			Satisfied:     row.Satisfied,
+		}
+		if row.Responses > 0 {
+			aggregate.Score = float64(row.Satisfied) * 100 / float64(row.Responses)
+		}
+		aggregates = append(aggregates, aggregate)
+	}
+	return aggregates, nil
+}
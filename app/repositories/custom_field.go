@@ -0,0 +1,30 @@
+package repositories
+
+import "app/domain/models"
+
+func (r *appRepository) CreateCustomFieldDefinition(definition *models.CustomFieldDefinition) error {
+	return r.Conn.Create(definition).Error
+}
+
+func (r *appRepository) GetCustomFieldDefinitions(categoryID int) ([]models.CustomFieldDefinition, error) {
+	var definitions []models.CustomFieldDefinition
+	err := r.Conn.Where("id_category = ?", categoryID).Order("urutan asc, id_custom_field asc").Find(&definitions).Error
+	return definitions, err
+}
+
+func (r *appRepository) GetCustomFieldDefinitionByID(id int) (*models.CustomFieldDefinition, error) {
+	var definition models.CustomFieldDefinition
+	err := r.Conn.First(&definition, id).Error
+	if err != nil {
+		return nil, err
+	}
+	return &definition, nil
+}
+
+func (r *appRepository) UpdateCustomFieldDefinition(definition *models.CustomFieldDefinition) error {
+	return r.Conn.Save(definition).Error
+}
+
+func (r *appRepository) DeleteCustomFieldDefinition(id int) error {
+	return r.Conn.Delete(&models.CustomFieldDefinition{}, id).Error
+}
@@ -0,0 +1,86 @@
+package repositories
+
+import (
+	"app/domain/models"
+	"time"
+
+	"gorm.io/gorm/clause"
+)
+
+func (r *appRepository) CreateEscalationRule(rule *models.EscalationRule) error {
+	return r.Conn.Create(rule).Error
+}
+
+func (r *appRepository) GetEscalationRules() ([]models.EscalationRule, error) {
+	var rules []models.EscalationRule
+	err := r.Conn.Preload("Status").Preload("Priority").Preload("Category").Preload("RaisePriority").Preload("ReassignTo").
+		Order("id_escalation_rule asc").Find(&rules).Error
+	return rules, err
+}
+
+func (r *appRepository) GetActiveEscalationRules() ([]models.EscalationRule, error) {
+	var rules []models.EscalationRule
+	err := r.Conn.Preload("RaisePriority").Preload("ReassignTo").
+		Where("is_active = ?", true).Order("id_escalation_rule asc").Find(&rules).Error
+	return rules, err
+}
+
+func (r *appRepository) GetEscalationRuleByID(id int) (*models.EscalationRule, error) {
+	var rule models.EscalationRule
+	err := r.Conn.Preload("Status").Preload("Priority").Preload("Category").Preload("RaisePriority").Preload("ReassignTo").
+		First(&rule, id).Error
+	return &rule, err
+}
+
+func (r *appRepository) UpdateEscalationRule(rule *models.EscalationRule) error {
+	return r.Conn.Omit(clause.Associations).Save(rule).Error
+}
+
+func (r *appRepository) DeleteEscalationRule(id int) error {
+	return r.Conn.Delete(&models.EscalationRule{}, id).Error
+}
+
+// GetTicketsToEscalate returns the tickets matching the rule that had no comment since
+// idleSince and were not escalated by the rule yet. Only admin and support comments count as
+// an answer, tickets without one count from their creation. Snoozed and merged tickets are
+// left alone, and without a status on the rule so are tickets in a terminal, resolved or
+// SLA pausing status, which wait for the customer.
+func (r *appRepository) GetTicketsToEscalate(rule *models.EscalationRule, idleSince time.Time) ([]models.Ticket, error) {
+	var tickets []models.Ticket
+	db := r.Conn.Preload("User").Preload("Status").Preload("Priority").
+		Where("tickets.merged_into_id IS NULL AND tickets.pending_until IS NULL").
+		Where(`COALESCE((SELECT MAX(ticket_comments.tanggal_dibuat) FROM ticket_comments
+			JOIN users ON users.id = ticket_comments.user_id
+			WHERE ticket_comments.id_ticket = tickets.id_ticket AND users.role IN ?), tickets.tanggal_dibuat) < ?`,
+			[]models.UserRole{models.RoleAdmin, models.RoleSupport}, idleSince).
+		Where(`NOT EXISTS (SELECT 1 FROM ticket_escalations
+			WHERE ticket_escalations.id_ticket = tickets.id_ticket AND ticket_escalations.id_escalation_rule = ?)`, rule.ID)
+
+	if rule.StatusID != nil {
+		db = db.Where("tickets.status_id = ?", *rule.StatusID)
+	} else {
+		waiting := r.Conn.Model(&models.TicketStatus{}).Select("id_status").
+			Where("is_terminal = ? OR is_resolved = ? OR pauses_sla = ?", true, true, true)
+		db = db.Where("tickets.status_id NOT IN (?)", waiting)
+	}
+	if rule.PriorityID != nil {
+		db = db.Where("tickets.priority_id = ?", *rule.PriorityID)
+	}
+	if rule.CategoryID != nil {
+		db = db.Where("tickets.category_id = ?", *rule.CategoryID)
+	}
+
+	err := db.Order("tickets.id_ticket asc").Find(&tickets).Error
+	return tickets, err
+}
+
+// RecordTicketEscalation records that the rule escalated the ticket unless it did already,
+// the returned bool tells whether this call recorded it
+func (r *appRepository) RecordTicketEscalation(ticketID, ruleID int, at time.Time) (bool, error) {
+	result := r.Conn.Clauses(clause.OnConflict{DoNothing: true}).Create(&models.TicketEscalation{
+		TicketID:    ticketID,
+		RuleID:      ruleID,
+		EscalatedAt: at,
+	})
+	return result.RowsAffected > 0, result.Error
+}
@@ -15,3 +15,11 @@ func NewAppRepository(conn *gorm.DB) domain.AppRepository {
 		Conn: conn,
 	}
 }
+
+// WithTransaction runs fn with a repository bound to a database transaction, the
+// transaction is committed when fn returns nil and rolled back otherwise
+func (r *appRepository) WithTransaction(fn func(repo domain.AppRepository) error) error {
+	return r.Conn.Transaction(func(tx *gorm.DB) error {
+		return fn(&appRepository{Conn: tx})
+	})
+}
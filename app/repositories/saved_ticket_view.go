@@ -0,0 +1,32 @@
+package repositories
+
+import (
+	"app/domain/models"
+)
+
+func (r *appRepository) CreateSavedTicketView(view *models.SavedTicketView) error {
+	return r.Conn.Create(view).Error
+}
+
+// GetSavedTicketViews returns the views of the user and the views others shared, by name
+func (r *appRepository) GetSavedTicketViews(userID uint64) ([]models.SavedTicketView, error) {
+	var views []models.SavedTicketView
+	err := r.Conn.Preload("User").
+		Where("id_user = ? OR is_shared = ?", userID, true).
+		Order("nama_view asc, id_view asc").
+		Find(&views).Error
+	return views, err
+}
+
+func (r *appRepository) GetSavedTicketViewByID(id int) (*models.SavedTicketView, error) {
+	var view models.SavedTicketView
+	err := r.Conn.Preload("User").First(&view, id).Error
+	if err != nil {
+		return nil, err
+	}
+	return &view, nil
+}
+
+func (r *appRepository) DeleteSavedTicketView(id int) error {
+	return r.Conn.Delete(&models.SavedTicketView{}, id).Error
+}
@@ -0,0 +1,62 @@
+package repositories
+
+import (
+	"app/domain/models"
+	"time"
+
+	"gorm.io/gorm/clause"
+)
+
+func (r *appRepository) CreateSLAPolicy(policy *models.SLAPolicy) error {
+	return r.Conn.Create(policy).Error
+}
+
+func (r *appRepository) GetSLAPolicies() ([]models.SLAPolicy, error) {
+	var policies []models.SLAPolicy
+	err := r.Conn.Preload("Priority").Preload("Category").Order("id_sla_policy asc").Find(&policies).Error
+	return policies, err
+}
+
+func (r *appRepository) GetActiveSLAPolicies() ([]models.SLAPolicy, error) {
+	var policies []models.SLAPolicy
+	err := r.Conn.Where("is_active = ?", true).Order("id_sla_policy asc").Find(&policies).Error
+	return policies, err
+}
+
+func (r *appRepository) GetSLAPolicyByID(id int) (*models.SLAPolicy, error) {
+	var policy models.SLAPolicy
+	err := r.Conn.Preload("Priority").Preload("Category").First(&policy, id).Error
+	return &policy, err
+}
+
+func (r *appRepository) UpdateSLAPolicy(policy *models.SLAPolicy) error {
+	return r.Conn.Omit(clause.Associations).Save(policy).Error
+}
+
+func (r *appRepository) DeleteSLAPolicy(id int) error {
+	return r.Conn.Delete(&models.SLAPolicy{}, id).Error
+}
+
+// GetTicketsWithUnrecordedSLABreach returns tickets with a running SLA clock whose due
+// timestamp passed before now and whose breach has not been recorded yet
+func (r *appRepository) GetTicketsWithUnrecordedSLABreach(now time.Time) ([]models.Ticket, error) {
+	var tickets []models.Ticket
+	err := r.Conn.Where("sla_paused_at IS NULL").
+		Where(r.Conn.
+			Where("first_responded_at IS NULL AND first_response_breached_at IS NULL AND first_response_due_at < ?", now).
+			Or("next_response_breached_at IS NULL AND next_response_due_at < ?", now).
+			Or("resolution_breached_at IS NULL AND resolution_due_at < ?", now)).
+		Find(&tickets).Error
+	return tickets, err
+}
+
+// MarkTicketSLABreached records the breach timestamp of one SLA target unless it
+// was recorded already, the returned bool tells whether this call recorded it
+func (r *appRepository) MarkTicketSLABreached(ticketID int, target models.SLATarget, at time.Time) (bool, error) {
+	column := string(target) + "_breached_at"
+	result := r.Conn.Model(&models.Ticket{}).
+		Where("id_ticket = ?", ticketID).
+		Where(column+" IS NULL").
+		Update(column, at)
+	return result.RowsAffected > 0, result.Error
+}
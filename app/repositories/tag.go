@@ -0,0 +1,92 @@
+package repositories
+
+import (
+	"app/domain"
+	"app/domain/models"
+
+	"gorm.io/gorm"
+)
+
+func (r *appRepository) CreateTag(tag *models.Tag) error {
+	return r.Conn.Create(tag).Error
+}
+
+func (r *appRepository) GetTags() ([]models.Tag, error) {
+	var tags []models.Tag
+	err := r.Conn.Order("nama_tag asc").Find(&tags).Error
+	return tags, err
+}
+
+func (r *appRepository) GetTagByID(id int) (*models.Tag, error) {
+	var tag models.Tag
+	err := r.Conn.First(&tag, id).Error
+	if err != nil {
+		return nil, err
+	}
+	return &tag, nil
+}
+
+func (r *appRepository) UpdateTag(tag *models.Tag) error {
+	return r.Conn.Model(&models.Tag{}).Where("id_tag = ?", tag.ID).Updates(map[string]interface{}{
+		"nama_tag": tag.NamaTag,
+		"warna":    tag.Warna,
+	}).Error
+}
+
+// DeleteTag removes the tag from every ticket and conversation before deleting it
+func (r *appRepository) DeleteTag(id int) error {
+	return r.Conn.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Exec("DELETE FROM ticket_tags WHERE tag_id = ?", id).Error; err != nil {
+			return err
+		}
+		if err := tx.Exec("DELETE FROM conversation_tags WHERE tag_id = ?", id).Error; err != nil {
+			return err
+		}
+		return tx.Delete(&models.Tag{}, id).Error
+	})
+}
+
+func (r *appRepository) AddTicketTag(ticketID int, tag *models.Tag) error {
+	return r.Conn.Model(&models.Ticket{ID: ticketID}).Omit("Tags.*").Association("Tags").Append(tag)
+}
+
+func (r *appRepository) RemoveTicketTag(ticketID int, tagID int) error {
+	return r.Conn.Model(&models.Ticket{ID: ticketID}).Association("Tags").Delete(&models.Tag{ID: tagID})
+}
+
+func (r *appRepository) AddConversationTag(conversationID uint64, tag *models.Tag) error {
+	return r.Conn.Model(&models.Conversation{ID: conversationID}).Omit("Tags.*").Association("Tags").Append(tag)
+}
+
+func (r *appRepository) RemoveConversationTag(conversationID uint64, tagID int) error {
+	return r.Conn.Model(&models.Conversation{ID: conversationID}).Association("Tags").Delete(&models.Tag{ID: tagID})
+}
+
+// applyTagFilter keeps the rows of a query whose idColumn appears in joinTable with the
+// filtered tags, joinTable being ticket_tags or conversation_tags
+func applyTagFilter(db *gorm.DB, filter domain.TagFilter, joinTable, joinColumn, idColumn string) *gorm.DB {
+	if len(filter.TagIDs) == 0 {
+		return db
+	}
+
+	tagged := db.Session(&gorm.Session{NewDB: true}).
+		Table(joinTable).
+		Select(joinColumn).
+		Where("tag_id IN ?", filter.TagIDs)
+	if filter.MatchAll {
+		tagged = tagged.Group(joinColumn).Having("COUNT(DISTINCT tag_id) = ?", len(uniqueInts(filter.TagIDs)))
+	}
+	return db.Where(idColumn+" IN (?)", tagged)
+}
+
+func uniqueInts(values []int) []int {
+	seen := make(map[int]bool, len(values))
+	unique := make([]int, 0, len(values))
+	for _, v := range values {
+		if !seen[v] {
+			seen[v] = true
+			unique = append(unique, v)
+		}
+	}
+	return unique
+}
@@ -1,13 +1,68 @@
 package repositories
 
 import (
+	"errors"
+	"fmt"
 	"strconv"
+	"strings"
+	"time"
 
+	"app/domain"
 	"app/domain/models"
+
+	"github.com/jackc/pgx/v5/pgconn"
+	"gorm.io/gorm"
 )
 
+// CreateTicket inserts the ticket, a code that is taken returns ErrTicketCodeExists
 func (r *appRepository) CreateTicket(ticket *models.Ticket) error {
-	return r.Conn.Create(ticket).Error
+	return ticketCodeError(r.Conn.Create(ticket).Error)
+}
+
+// GetTicketByCode finds a ticket by its code, case-insensitive
+func (r *appRepository) GetTicketByCode(code string) (*models.Ticket, error) {
+	var ticket models.Ticket
+	err := r.Conn.Where("UPPER(kode_tiket) = UPPER(?)", code).First(&ticket).Error
+	if err != nil {
+		return nil, err
+	}
+	return &ticket, nil
+}
+
+// NextTicketCodeSequence advances the code sequence of prefix in year and returns the new
+// value. The sequence row stays locked until the transaction ends, so concurrent tickets
+// wait for each other and a rolled back ticket gives its number back.
+func (r *appRepository) NextTicketCodeSequence(prefix string, year int) (int, error) {
+	var value int
+	err := r.Conn.Raw(`INSERT INTO ticket_code_sequences (prefix, year, last_value) VALUES (?, ?, 1)
+		ON CONFLICT (prefix, year) DO UPDATE SET last_value = ticket_code_sequences.last_value + 1
+		RETURNING last_value`, prefix, year).Scan(&value).Error
+	return value, err
+}
+
+// AdvanceTicketCodeSequence moves the code sequence of prefix in year to at least sequence,
+// locking the sequence row like NextTicketCodeSequence
+func (r *appRepository) AdvanceTicketCodeSequence(prefix string, year, sequence int) error {
+	return r.Conn.Exec(`INSERT INTO ticket_code_sequences (prefix, year, last_value) VALUES (?, ?, ?)
+		ON CONFLICT (prefix, year) DO UPDATE SET last_value = GREATEST(ticket_code_sequences.last_value, EXCLUDED.last_value)`,
+		prefix, year, sequence).Error
+}
+
+// escapeLike escapes the LIKE wildcards so value is matched literally
+func escapeLike(value string) string {
+	return strings.NewReplacer(`\`, `\\`, "%", `\%`, "_", `\_`).Replace(value)
+}
+
+// uniqueViolation is the PostgreSQL error code of a unique constraint violation
+const uniqueViolation = "23505"
+
+// ticketCodeError turns a violation of the unique ticket code into ErrTicketCodeExists
+func ticketCodeError(err error) error {
+	var pgErr *pgconn.PgError
+	if errors.As(err, &pgErr) && pgErr.Code == uniqueViolation && strings.Contains(pgErr.ConstraintName, "kode_tiket") {
+		return fmt.Errorf("%w: %s", domain.ErrTicketCodeExists, pgErr.Detail)
+	}
+	return err
 }
 
 func (r *appRepository) GetTickets() ([]models.Ticket, error) {
@@ -34,28 +89,141 @@ func (r *appRepository) GetTicketsPaginated(limit, offset int) ([]models.Ticket,
 
 func (r *appRepository) GetTicketByID(id int) (*models.Ticket, error) {
 	var ticket models.Ticket
-	err := r.Conn.Preload("User").Preload("Category").Preload("Priority").Preload("Status").First(&ticket, id).Error
+	err := r.Conn.Preload("User").Preload("Category").Preload("Priority").Preload("Status").Preload("Tags").First(&ticket, id).Error
 	return &ticket, err
 }
 
+// UpdateTicket writes the ticket when it is still at the version it was read at and
+// raises the version, an outdated ticket returns ErrVersionConflict
 func (r *appRepository) UpdateTicket(ticket *models.Ticket) error {
-	return r.Conn.Model(&models.Ticket{}).Where("id_ticket = ?", ticket.ID).Updates(map[string]interface{}{
-		"kode_tiket":         ticket.KodeTiket,
-		"id_user":            ticket.UserID,
-		"judul":              ticket.Judul,
-		"deskripsi":          ticket.Deskripsi,
-		"category_id":        ticket.CategoryID,
-		"priority_id":        ticket.PriorityID,
-		"status_id":          ticket.StatusID,
-		"tipe_pengaduan":     ticket.TipePengaduan,
-		"tanggal_diperbarui": ticket.TanggalDiperbarui,
-	}).Error
+	result := r.Conn.Model(&models.Ticket{}).Where("id_ticket = ? AND version = ?", ticket.ID, ticket.Version).Updates(map[string]interface{}{
+		"kode_tiket":           ticket.KodeTiket,
+		"id_user":              ticket.UserID,
+		"judul":                ticket.Judul,
+		"deskripsi":            ticket.Deskripsi,
+		"category_id":          ticket.CategoryID,
+		"priority_id":          ticket.PriorityID,
+		"status_id":            ticket.StatusID,
+		"tipe_pengaduan":       ticket.TipePengaduan,
+		"tanggal_diperbarui":   ticket.TanggalDiperbarui,
+		"merged_into_id":       ticket.MergedIntoID,
+		"custom_fields":        ticket.CustomFields,
+		"status_changed_at":    ticket.StatusChangedAt,
+		"auto_close_warned_at": ticket.AutoCloseWarnedAt,
+		"reopen_count":         ticket.ReopenCount,
+		"pending_until":        ticket.PendingUntil,
+		"pending_reason":       ticket.PendingReason,
+		// SLA fields are recomputed by the service on every change
+		"sla_policy_id":              ticket.SLAPolicyID,
+		"first_response_due_at":      ticket.FirstResponseDueAt,
+		"first_responded_at":         ticket.FirstRespondedAt,
+		"next_response_due_at":       ticket.NextResponseDueAt,
+		"resolution_due_at":          ticket.ResolutionDueAt,
+		"sla_paused_at":              ticket.SLAPausedAt,
+		"first_response_breached_at": ticket.FirstResponseBreachedAt,
+		"next_response_breached_at":  ticket.NextResponseBreachedAt,
+		"resolution_breached_at":     ticket.ResolutionBreachedAt,
+		"version":                    gorm.Expr("version + 1"),
+	})
+	if result.Error != nil {
+		return ticketCodeError(result.Error)
+	}
+	if result.RowsAffected == 0 {
+		return domain.ErrVersionConflict
+	}
+
+	ticket.Version++
+	return nil
 }
 
+// MoveTicketRecords re-parents the comments, attachments, assignments and logs of a ticket
+func (r *appRepository) MoveTicketRecords(fromTicketID, toTicketID int) error {
+	for _, record := range []interface{}{
+		&models.TicketComment{},
+		&models.TicketAttachment{},
+		&models.TicketAssignment{},
+		&models.TicketLog{},
+	} {
+		if err := r.Conn.Model(record).Where("id_ticket = ?", fromTicketID).Update("id_ticket", toTicketID).Error; err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// DeleteTicket soft deletes a ticket, it stays restorable until it is purged
 func (r *appRepository) DeleteTicket(id int) error {
 	return r.Conn.Delete(&models.Ticket{}, id).Error
 }
 
+// GetDeletedTickets lists the soft deleted tickets, most recently deleted first
+func (r *appRepository) GetDeletedTickets() ([]models.Ticket, error) {
+	var tickets []models.Ticket
+	err := r.Conn.Unscoped().Preload("User").Preload("Category").Preload("Priority").Preload("Status").
+		Where("deleted_at IS NOT NULL").Order("deleted_at desc").Find(&tickets).Error
+	return tickets, err
+}
+
+// RestoreTicket clears the deletion of a soft deleted ticket
+func (r *appRepository) RestoreTicket(id int) error {
+	result := r.Conn.Unscoped().Model(&models.Ticket{}).
+		Where("id_ticket = ? AND deleted_at IS NOT NULL", id).
+		Update("deleted_at", nil)
+	if result.Error != nil {
+		return result.Error
+	}
+	if result.RowsAffected == 0 {
+		return gorm.ErrRecordNotFound
+	}
+	return nil
+}
+
+// GetTicketsDeletedBefore lists the soft deleted tickets due for purging
+func (r *appRepository) GetTicketsDeletedBefore(cutoff time.Time) ([]models.Ticket, error) {
+	var tickets []models.Ticket
+	err := r.Conn.Unscoped().Where("deleted_at IS NOT NULL AND deleted_at < ?", cutoff).Find(&tickets).Error
+	return tickets, err
+}
+
+// PurgeTicket permanently deletes a soft deleted ticket with everything attached to it
+// and returns the storage paths of its attachments, which the caller removes
+func (r *appRepository) PurgeTicket(id int) ([]string, error) {
+	var filePaths []string
+	err := r.Conn.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Model(&models.TicketAttachment{}).Where("id_ticket = ?", id).Pluck("file_path", &filePaths).Error; err != nil {
+			return err
+		}
+
+		for _, record := range []interface{}{
+			&models.TicketComment{},
+			&models.TicketAttachment{},
+			&models.TicketAssignment{},
+			&models.TicketLog{},
+			&models.TicketWatcher{},
+			&models.CSATSurvey{},
+			&models.TicketEscalation{},
+		} {
+			if err := tx.Where("id_ticket = ?", id).Delete(record).Error; err != nil {
+				return err
+			}
+		}
+		if err := tx.Where("id_ticket_source = ? OR id_ticket_target = ?", id, id).Delete(&models.TicketLink{}).Error; err != nil {
+			return err
+		}
+		if err := tx.Exec("DELETE FROM ticket_tags WHERE ticket_id = ?", id).Error; err != nil {
+			return err
+		}
+
+		// Tickets merged into the purged ticket no longer point anywhere
+		if err := tx.Unscoped().Model(&models.Ticket{}).Where("merged_into_id = ?", id).Update("merged_into_id", nil).Error; err != nil {
+			return err
+		}
+
+		return tx.Unscoped().Where("deleted_at IS NOT NULL").Delete(&models.Ticket{}, id).Error
+	})
+	return filePaths, err
+}
+
 func (r *appRepository) GetTicketsByUserID(userID int) ([]models.Ticket, error) {
 	var tickets []models.Ticket
 	err := r.Conn.Where("id_user = ?", userID).Preload("User").Preload("Category").Preload("Priority").Preload("Status").Find(&tickets).Error
@@ -63,60 +231,173 @@ func (r *appRepository) GetTicketsByUserID(userID int) ([]models.Ticket, error)
 }
 
 // Add this function for cursor-based pagination
-func (r *appRepository) GetTicketsCursor(limit int, cursor string, tipePengaduan string, statusID, priorityID, categoryID int) ([]models.Ticket, string, error) {
+func (r *appRepository) GetTicketsCursor(limit int, cursor string, filter domain.TicketFilter) ([]models.Ticket, string, error) {
 	var tickets []models.Ticket
 
-	db := r.Conn.Preload("User").Preload("Category").Preload("Priority").Preload("Status")
+	db := r.Conn.Preload("User").Preload("Category").Preload("Priority").Preload("Status").Preload("Tags")
 
 	// Apply filters at database level
-	if tipePengaduan != "" {
-		db = db.Where("tipe_pengaduan = ?", tipePengaduan)
-	}
-	if statusID > 0 {
-		db = db.Where("status_id = ?", statusID)
-	}
-	if priorityID > 0 {
-		db = db.Where("priority_id = ?", priorityID)
-	}
-	if categoryID > 0 {
-		db = db.Where("category_id = ?", categoryID)
-	}
+	db = applyTicketFilter(db, filter)
 
-	if cursor != "" {
+	switch filter.Sort {
+	case models.TicketSortOldest:
+		if lastID, err := strconv.Atoi(cursor); err == nil {
+			db = db.Where("id_ticket > ?", lastID)
+		}
+		db = db.Order("id_ticket asc")
+	case models.TicketSortUpdated:
+		// cursor is "<unix nano>:<id>" of the last seen ticket
+		if nanos, id, found := strings.Cut(cursor, ":"); found {
+			lastNanos, nanosErr := strconv.ParseInt(nanos, 10, 64)
+			lastID, idErr := strconv.Atoi(id)
+			if nanosErr == nil && idErr == nil {
+				db = db.Where("(tanggal_diperbarui, id_ticket) < (?, ?)", time.Unix(0, lastNanos), lastID)
+			}
+		}
+		db = db.Order("tanggal_diperbarui desc, id_ticket desc")
+	default:
 		// cursor is last seen ticket ID (assuming descending order)
 		if lastID, err := strconv.Atoi(cursor); err == nil {
 			db = db.Where("id_ticket < ?", lastID)
 		}
+		db = db.Order("id_ticket desc")
 	}
 
-	err := db.Order("id_ticket desc").Limit(limit + 1).Find(&tickets).Error
+	err := db.Limit(limit + 1).Find(&tickets).Error
 	if err != nil {
 		return nil, "", err
 	}
 
 	var nextCursor string
 	if len(tickets) > limit {
-		nextCursor = strconv.Itoa(tickets[limit].ID)
+		// The next page starts after the last ticket of this one
 		tickets = tickets[:limit]
+		nextCursor = ticketListCursor(tickets[limit-1], filter.Sort)
 	}
 
 	return tickets, nextCursor, nil
 }
 
+// ticketListCursor is the cursor of the ticket list continuing after ticket in the sort order
+func ticketListCursor(ticket models.Ticket, sort models.TicketSort) string {
+	if sort == models.TicketSortUpdated {
+		return strconv.FormatInt(ticket.TanggalDiperbarui.UnixNano(), 10) + ":" + strconv.Itoa(ticket.ID)
+	}
+	return strconv.Itoa(ticket.ID)
+}
+
+// CountTickets counts the tickets matching the list filters
+func (r *appRepository) CountTickets(filter domain.TicketFilter) (int64, error) {
+	var count int64
+	err := applyTicketFilter(r.Conn.Model(&models.Ticket{}), filter).Count(&count).Error
+	return count, err
+}
+
+// CountTicketsPerFilter counts the tickets matching each filter in one query, the counts
+// are in the order of the filters
+func (r *appRepository) CountTicketsPerFilter(filters []domain.TicketFilter) ([]int64, error) {
+	counts := make([]int64, len(filters))
+	if len(filters) == 0 {
+		return counts, nil
+	}
+
+	queries := make([]interface{}, 0, len(filters))
+	for i, filter := range filters {
+		query := r.Conn.Model(&models.Ticket{}).Select("CAST(? AS integer) AS position, COUNT(*) AS total", i)
+		queries = append(queries, applyTicketFilter(query, filter))
+	}
+
+	var rows []struct {
+		Position int
+		Total    int64
+	}
+	sql := strings.TrimSuffix(strings.Repeat("? UNION ALL ", len(queries)), " UNION ALL ")
+	if err := r.Conn.Raw(sql, queries...).Scan(&rows).Error; err != nil {
+		return nil, err
+	}
+	for _, row := range rows {
+		counts[row.Position] = row.Total
+	}
+	return counts, nil
+}
+
+// applyTicketFilter adds the list filters to a query on the tickets table
+func applyTicketFilter(db *gorm.DB, filter domain.TicketFilter) *gorm.DB {
+	if filter.TipePengaduan != "" {
+		db = db.Where("tickets.tipe_pengaduan = ?", filter.TipePengaduan)
+	}
+	if filter.StatusID > 0 {
+		db = db.Where("tickets.status_id = ?", filter.StatusID)
+	}
+	if filter.PriorityID > 0 {
+		db = db.Where("tickets.priority_id = ?", filter.PriorityID)
+	}
+	if filter.CategoryID > 0 {
+		db = db.Where("tickets.category_id = ?", filter.CategoryID)
+	}
+	if filter.KodeTiket != "" {
+		db = db.Where("tickets.kode_tiket ILIKE ?", escapeLike(filter.KodeTiket)+"%")
+	}
+	if filter.AssigneeID > 0 {
+		db = db.Where("EXISTS (SELECT 1 FROM ticket_assignments WHERE ticket_assignments.id_ticket = tickets.id_ticket AND ticket_assignments.id_admin = ?)", filter.AssigneeID)
+	}
+	if filter.MinReopenCount > 0 {
+		db = db.Where("tickets.reopen_count >= ?", filter.MinReopenCount)
+	}
+	if !filter.IncludePending && filter.StatusID == 0 {
+		db = db.Where("tickets.pending_until IS NULL")
+	}
+
+	db = applyTagFilter(db, filter.Tags, "ticket_tags", "ticket_id", "tickets.id_ticket")
+	for key, value := range filter.CustomFields {
+		db = db.Where("tickets.custom_fields ->> ? = ?", key, value)
+	}
+
+	now := time.Now()
+	switch filter.SLAState {
+	case models.SLAStateBreached:
+		db = db.Where(slaBreachedCondition, now, now, now)
+	case models.SLAStateAtRisk:
+		riskLimit := now.Add(filter.SLAAtRiskWindow)
+		db = db.Where("NOT "+slaBreachedCondition, now, now, now).
+			Where(slaAtRiskCondition, now, riskLimit, now, riskLimit, now, riskLimit)
+	}
+
+	return db
+}
+
+// slaBreachedCondition matches tickets with a recorded breach or a running clock past its due timestamp
+const slaBreachedCondition = `(tickets.first_response_breached_at IS NOT NULL
+	OR tickets.next_response_breached_at IS NOT NULL
+	OR tickets.resolution_breached_at IS NOT NULL
+	OR (tickets.sla_paused_at IS NULL AND (
+		(tickets.first_responded_at IS NULL AND tickets.first_response_due_at < ?)
+		OR tickets.next_response_due_at < ?
+		OR tickets.resolution_due_at < ?)))`
+
+// slaAtRiskCondition matches tickets with a running clock due inside the at risk window
+const slaAtRiskCondition = `(tickets.sla_paused_at IS NULL AND (
+	(tickets.first_responded_at IS NULL AND tickets.first_response_due_at BETWEEN ? AND ?)
+	OR tickets.next_response_due_at BETWEEN ? AND ?
+	OR tickets.resolution_due_at BETWEEN ? AND ?))`
+
 func (r *appRepository) GetOpenTicketCountsByType() (customerCount int, sellerCount int, err error) {
 	var customerResult int64
 	var sellerResult int64
 
-	// Count tickets with tipe_pengaduan = customer and status_id = 1
+	// Open tickets are the ones still in the initial workflow status
+	initialStatuses := r.Conn.Model(&models.TicketStatus{}).Select("id_status").Where("is_initial = ?", true)
+
+	// Count open tickets with tipe_pengaduan = customer
 	if err := r.Conn.Model(&models.Ticket{}).
-		Where("tipe_pengaduan = ? AND status_id = ?", models.RoleCustomer, 1).
+		Where("tipe_pengaduan = ? AND status_id IN (?)", models.RoleCustomer, initialStatuses).
 		Count(&customerResult).Error; err != nil {
 		return 0, 0, err
 	}
 
-	// Count tickets with tipe_pengaduan = seller and status_id = 1
+	// Count open tickets with tipe_pengaduan = seller
 	if err := r.Conn.Model(&models.Ticket{}).
-		Where("tipe_pengaduan = ? AND status_id = ?", models.RoleSeller, 1).
+		Where("tipe_pengaduan = ? AND status_id IN (?)", models.RoleSeller, initialStatuses).
 		Count(&sellerResult).Error; err != nil {
 		return 0, 0, err
 	}
@@ -134,16 +415,20 @@ func (r *appRepository) GetTicketStatistics() (total int, inProgress int, resolv
 		return 0, 0, 0, nil, err
 	}
 
-	// Get in progress tickets (status_id = 2)
+	// In progress tickets are the ones in a working status, neither initial, resolved,
+	// terminal nor pending
+	workingStatuses := r.Conn.Model(&models.TicketStatus{}).Select("id_status").
+		Where("is_initial = ? AND is_resolved = ? AND is_terminal = ? AND is_pending = ?", false, false, false, false)
 	if err := r.Conn.Model(&models.Ticket{}).
-		Where("status_id = ?", 2).
+		Where("status_id IN (?)", workingStatuses).
 		Count(&inProgressCount).Error; err != nil {
 		return 0, 0, 0, nil, err
 	}
 
-	// Get resolved tickets (status_id = 3)
+	// Resolved tickets are the ones in a resolved workflow status
+	resolvedStatuses := r.Conn.Model(&models.TicketStatus{}).Select("id_status").Where("is_resolved = ?", true)
 	if err := r.Conn.Model(&models.Ticket{}).
-		Where("status_id = ?", 3).
+		Where("status_id IN (?)", resolvedStatuses).
 		Count(&resolvedCount).Error; err != nil {
 		return 0, 0, 0, nil, err
 	}
@@ -162,3 +447,55 @@ func (r *appRepository) GetTicketStatistics() (total int, inProgress int, resolv
 
 	return int(totalCount), int(inProgressCount), int(resolvedCount), priorityCounts, nil
 }
+
+// GetTicketReopenStatistics counts the tickets reopened at least once and all reopens together
+func (r *appRepository) GetTicketReopenStatistics() (reopenedTickets int, totalReopens int, err error) {
+	var result struct {
+		ReopenedTickets int64
+		TotalReopens    int64
+	}
+	err = r.Conn.Model(&models.Ticket{}).
+		Select("COUNT(*) FILTER (WHERE reopen_count > 0) AS reopened_tickets, COALESCE(SUM(reopen_count), 0) AS total_reopens").
+		Scan(&result).Error
+	if err != nil {
+		return 0, 0, err
+	}
+	return int(result.ReopenedTickets), int(result.TotalReopens), nil
+}
+
+// GetTicketsAwaitingAutoClose returns the tickets in a status with an auto-close transition
+// that entered it before resolvedBefore. Tickets predating the status timestamp fall back
+// to their last update.
+func (r *appRepository) GetTicketsAwaitingAutoClose(resolvedBefore time.Time) ([]models.Ticket, error) {
+	var tickets []models.Ticket
+	autoCloseStatuses := r.Conn.Model(&models.TicketStatusTransition{}).
+		Select("from_status_id").
+		Where("event = ?", models.WorkflowEventAutoClose)
+	err := r.Conn.Preload("User").Preload("Status").
+		Where("status_id IN (?)", autoCloseStatuses).
+		Where("merged_into_id IS NULL").
+		Where("COALESCE(status_changed_at, tanggal_diperbarui) < ?", resolvedBefore).
+		Find(&tickets).Error
+	return tickets, err
+}
+
+// MarkTicketAutoCloseWarned records when the customer was warned about the auto-close unless
+// a warning was recorded already, the returned bool tells whether this call recorded it
+func (r *appRepository) MarkTicketAutoCloseWarned(ticketID int, at time.Time) (bool, error) {
+	result := r.Conn.Model(&models.Ticket{}).
+		Where("id_ticket = ?", ticketID).
+		Where("auto_close_warned_at IS NULL").
+		Update("auto_close_warned_at", at)
+	return result.RowsAffected > 0, result.Error
+}
+
+// GetTicketsToWake returns the snoozed tickets whose wake-up time passed
+func (r *appRepository) GetTicketsToWake(now time.Time) ([]models.Ticket, error) {
+	var tickets []models.Ticket
+	err := r.Conn.Preload("User").Preload("Status").
+		Where("pending_until IS NOT NULL AND pending_until <= ?", now).
+		Where("merged_into_id IS NULL").
+		Order("pending_until asc").
+		Find(&tickets).Error
+	return tickets, err
+}
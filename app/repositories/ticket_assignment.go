@@ -3,7 +3,10 @@ package repositories
 import (
 	"strconv"
 
+	"app/domain"
 	"app/domain/models"
+
+	"gorm.io/gorm"
 )
 
 func (r *appRepository) CreateTicketAssignment(assignment *models.TicketAssignment) error {
@@ -22,8 +25,27 @@ func (r *appRepository) GetTicketAssignmentByID(id int) (*models.TicketAssignmen
 	return &assignment, err
 }
 
+// UpdateTicketAssignment writes the assignment when it is still at the version it was
+// read at and raises the version, an outdated assignment returns ErrVersionConflict
 func (r *appRepository) UpdateTicketAssignment(assignment *models.TicketAssignment) error {
-	return r.Conn.Save(assignment).Error
+	result := r.Conn.Model(&models.TicketAssignment{}).
+		Where("id_assignment = ? AND version = ?", assignment.ID, assignment.Version).
+		Updates(map[string]interface{}{
+			"id_ticket":          assignment.TicketID,
+			"id_admin":           assignment.AdminID,
+			"priority_id":        assignment.PriorityID,
+			"tanggal_ditugaskan": assignment.TanggalDitugaskan,
+			"version":            gorm.Expr("version + 1"),
+		})
+	if result.Error != nil {
+		return result.Error
+	}
+	if result.RowsAffected == 0 {
+		return domain.ErrVersionConflict
+	}
+
+	assignment.Version++
+	return nil
 }
 
 func (r *appRepository) DeleteTicketAssignment(id int) error {
@@ -43,23 +65,28 @@ func (r *appRepository) GetTicketAssignmentByTicketID(ticketID int) (*models.Tic
 func (r *appRepository) GetTicketAssignmentsByAdminIDCursor(adminID int, limit int, cursor string, statusName string) ([]models.TicketAssignment, string, error) {
 	var assignments []models.TicketAssignment
 
-	db := r.Conn.Preload("Ticket").Preload("Admin").Preload("Priority").Where("id_admin = ?", adminID)
+	// Assignments of deleted tickets are hidden with their ticket
+	db := r.Conn.Preload("Ticket").Preload("Admin").Preload("Priority").
+		Joins("JOIN tickets ON ticket_assignments.id_ticket = tickets.id_ticket AND tickets.deleted_at IS NULL").
+		Where("ticket_assignments.id_admin = ?", adminID)
 
-	// Join with tickets and ticket_statuses tables to filter by status name if provided
+	// Join with ticket_statuses table to filter by status name if provided, snoozed tickets
+	// only show up when asked for by status
 	if statusName != "" {
-		db = db.Joins("JOIN tickets ON ticket_assignments.id_ticket = tickets.id_ticket"). // Fixed column name in JOIN
-			Joins("JOIN ticket_statuses ON tickets.status_id = ticket_statuses.id_status").
+		db = db.Joins("JOIN ticket_statuses ON tickets.status_id = ticket_statuses.id_status").
 			Where("ticket_statuses.nama_status = ?", statusName)
+	} else {
+		db = db.Where("tickets.pending_until IS NULL")
 	}
 
 	if cursor != "" {
 		// Cursor is the last seen assignment ID (assuming descending order by ID)
 		if lastID, err := strconv.Atoi(cursor); err == nil {
-			db = db.Where("id_assignment < ?", lastID) // Fixed column name
+			db = db.Where("ticket_assignments.id_assignment < ?", lastID) // Fixed column name
 		}
 	}
 
-	err := db.Order("id_assignment desc").Limit(limit + 1).Find(&assignments).Error // Fixed column name
+	err := db.Order("ticket_assignments.id_assignment desc").Limit(limit + 1).Find(&assignments).Error // Fixed column name
 	if err != nil {
 		return nil, "", err
 	}
@@ -76,7 +103,10 @@ func (r *appRepository) GetTicketAssignmentsByAdminIDCursor(adminID int, limit i
 // New method to count total assigned tickets by admin ID
 func (r *appRepository) GetAssignedTicketCountByAdminID(adminID int) (int, error) {
 	var count int64
-	err := r.Conn.Model(&models.TicketAssignment{}).Where("id_admin = ?", adminID).Count(&count).Error
+	err := r.Conn.Model(&models.TicketAssignment{}).
+		Joins("JOIN tickets ON ticket_assignments.id_ticket = tickets.id_ticket AND tickets.deleted_at IS NULL").
+		Where("ticket_assignments.id_admin = ?", adminID).
+		Count(&count).Error
 	return int(count), err
 }
 
@@ -84,7 +114,7 @@ func (r *appRepository) GetAssignedTicketCountByAdminID(adminID int) (int, error
 func (r *appRepository) GetAssignedTicketCountByAdminIDAndStatus(adminID int, statusID int) (int, error) {
 	var count int64
 	err := r.Conn.Model(&models.TicketAssignment{}).
-		Joins("JOIN tickets ON ticket_assignments.id_ticket = tickets.id_ticket").
+		Joins("JOIN tickets ON ticket_assignments.id_ticket = tickets.id_ticket AND tickets.deleted_at IS NULL").
 		Where("ticket_assignments.id_admin = ? AND tickets.status_id = ?", adminID, statusID).
 		Count(&count).Error
 	return int(count), err
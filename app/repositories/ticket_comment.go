@@ -1,6 +1,11 @@
 package repositories
 
-import "app/domain/models"
+import (
+	"app/domain"
+	"app/domain/models"
+
+	"gorm.io/gorm"
+)
 
 func (r *appRepository) CreateTicketComment(comment *models.TicketComment) error {
 	return r.Conn.Create(comment).Error
@@ -24,8 +29,26 @@ func (r *appRepository) GetTicketCommentsByTicketID(ticketID int) ([]models.Tick
 	return comments, err
 }
 
+// UpdateTicketComment writes the comment when it is still at the version it was read at
+// and raises the version, an outdated comment returns ErrVersionConflict
 func (r *appRepository) UpdateTicketComment(comment *models.TicketComment) error {
-	return r.Conn.Save(comment).Error
+	result := r.Conn.Model(&models.TicketComment{}).
+		Where("id_comment = ? AND version = ?", comment.ID, comment.Version).
+		Updates(map[string]interface{}{
+			"id_ticket": comment.TicketID,
+			"id_user":   comment.UserID,
+			"isi_pesan": comment.IsiPesan,
+			"version":   gorm.Expr("version + 1"),
+		})
+	if result.Error != nil {
+		return result.Error
+	}
+	if result.RowsAffected == 0 {
+		return domain.ErrVersionConflict
+	}
+
+	comment.Version++
+	return nil
 }
 
 func (r *appRepository) DeleteTicketComment(id int) error {
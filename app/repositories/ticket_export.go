@@ -0,0 +1,44 @@
+package repositories
+
+import (
+	"app/domain"
+	"app/domain/models"
+)
+
+// StreamTicketExport passes every ticket matching the list filters to fn, newest first,
+// reading one row at a time. The assignee is the one GetTicketAssignmentByTicketID returns.
+func (r *appRepository) StreamTicketExport(filter domain.TicketFilter, fn func(row domain.TicketExportRow) error) error {
+	db := r.Conn.Model(&models.Ticket{}).
+		Select(`tickets.id_ticket AS id, tickets.kode_tiket, tickets.judul, tickets.tipe_pengaduan,
+			COALESCE(owner.username, '') AS username,
+			COALESCE(ticket_categories.nama_category, '') AS category,
+			COALESCE(ticket_priorities.nama_priority, '') AS priority,
+			COALESCE(ticket_statuses.nama_status, '') AS status,
+			COALESCE(assignee.username, '') AS assignee,
+			tickets.reopen_count, tickets.tanggal_dibuat, tickets.tanggal_diperbarui`).
+		Joins("LEFT JOIN users owner ON owner.id = tickets.id_user").
+		Joins("LEFT JOIN ticket_categories ON ticket_categories.id_category = tickets.category_id").
+		Joins("LEFT JOIN ticket_priorities ON ticket_priorities.id_priority = tickets.priority_id").
+		Joins("LEFT JOIN ticket_statuses ON ticket_statuses.id_status = tickets.status_id").
+		Joins(`LEFT JOIN LATERAL (SELECT ticket_assignments.id_admin FROM ticket_assignments
+			WHERE ticket_assignments.id_ticket = tickets.id_ticket
+			ORDER BY ticket_assignments.id_assignment LIMIT 1) assignment ON true`).
+		Joins("LEFT JOIN users assignee ON assignee.id = assignment.id_admin")
+
+	rows, err := applyTicketFilter(db, filter).Order("tickets.id_ticket desc").Rows()
+	if err != nil {
+		return err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var row domain.TicketExportRow
+		if err := r.Conn.ScanRows(rows, &row); err != nil {
+			return err
+		}
+		if err := fn(row); err != nil {
+			return err
+		}
+	}
+	return rows.Err()
+}
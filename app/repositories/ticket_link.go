@@ -0,0 +1,72 @@
+package repositories
+
+import (
+	"app/domain/models"
+	"errors"
+
+	"gorm.io/gorm"
+)
+
+func (r *appRepository) CreateTicketLink(link *models.TicketLink) error {
+	return r.Conn.Create(link).Error
+}
+
+func (r *appRepository) GetTicketLinkByID(id int) (*models.TicketLink, error) {
+	var link models.TicketLink
+	err := r.Conn.First(&link, id).Error
+	if err != nil {
+		return nil, err
+	}
+	return &link, nil
+}
+
+// GetTicketLinksByTicketID returns the links on either side of a ticket with both tickets loaded
+func (r *appRepository) GetTicketLinksByTicketID(ticketID int) ([]models.TicketLink, error) {
+	var links []models.TicketLink
+	err := r.Conn.Preload("SourceTicket").Preload("TargetTicket").
+		Where("id_ticket_source = ? OR id_ticket_target = ?", ticketID, ticketID).
+		Order("id_link asc").
+		Find(&links).Error
+	return links, err
+}
+
+// GetTicketLinkBetween returns the link between two tickets in either direction, nil when there is none
+func (r *appRepository) GetTicketLinkBetween(ticketID, otherTicketID int) (*models.TicketLink, error) {
+	var link models.TicketLink
+	err := r.Conn.Where("(id_ticket_source = ? AND id_ticket_target = ?) OR (id_ticket_source = ? AND id_ticket_target = ?)",
+		ticketID, otherTicketID, otherTicketID, ticketID).
+		First(&link).Error
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	return &link, nil
+}
+
+// GetParentTicketID returns the parent of a ticket, nil when it has none
+func (r *appRepository) GetParentTicketID(ticketID int) (*int, error) {
+	var link models.TicketLink
+	err := r.Conn.Where("id_ticket_target = ? AND link_type = ?", ticketID, models.LinkTypeParent).First(&link).Error
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	return &link.SourceTicketID, nil
+}
+
+func (r *appRepository) GetChildTickets(parentID int) ([]models.Ticket, error) {
+	var tickets []models.Ticket
+	err := r.Conn.Preload("Status").
+		Joins("JOIN ticket_links ON ticket_links.id_ticket_target = tickets.id_ticket").
+		Where("ticket_links.id_ticket_source = ? AND ticket_links.link_type = ?", parentID, models.LinkTypeParent).
+		Find(&tickets).Error
+	return tickets, err
+}
+
+func (r *appRepository) DeleteTicketLink(id int) error {
+	return r.Conn.Delete(&models.TicketLink{}, id).Error
+}
@@ -8,7 +8,7 @@ func (r *appRepository) CreateTicketLog(log *models.TicketLog) error {
 
 func (r *appRepository) GetTicketLogs() ([]models.TicketLog, error) {
 	var logs []models.TicketLog
-	err := r.Conn.Find(&logs).Error
+	err := r.Conn.Order("waktu asc, id_log asc").Find(&logs).Error
 	return logs, err
 }
 
@@ -20,6 +20,6 @@ func (r *appRepository) GetTicketLogByID(id int) (*models.TicketLog, error) {
 
 func (r *appRepository) GetTicketLogsByTicketID(ticketID int) ([]models.TicketLog, error) {
 	var logs []models.TicketLog
-	err := r.Conn.Where("id_ticket = ?", ticketID).Find(&logs).Error
+	err := r.Conn.Where("id_ticket = ?", ticketID).Order("waktu asc, id_log asc").Find(&logs).Error
 	return logs, err
 }
\ No newline at end of file
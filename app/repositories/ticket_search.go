@@ -0,0 +1,123 @@
+package repositories
+
+import (
+	"strconv"
+	"strings"
+
+	"app/domain"
+	"app/domain/models"
+)
+
+// Search documents, these expressions have to match the GIN indexes exactly so
+// PostgreSQL can use them. The simple configuration keeps Indonesian words and
+// ticket codes intact instead of stemming them as English.
+const (
+	ticketSearchDocument = `(setweight(to_tsvector('simple', coalesce(tickets.kode_tiket, '')), 'A')
+	|| setweight(to_tsvector('simple', coalesce(tickets.judul, '')), 'A')
+	|| setweight(to_tsvector('simple', coalesce(tickets.deskripsi, '')), 'B'))`
+	commentSearchDocument = `to_tsvector('simple', coalesce(ticket_comments.isi_pesan, ''))`
+
+	searchQuery           = `websearch_to_tsquery('simple', ?)`
+	searchHeadlineOptions = `'StartSel=<mark>, StopSel=</mark>, MaxWords=30, MinWords=10, MaxFragments=2'`
+)
+
+// escapeHTMLSQL wraps a text expression so it is HTML-escaped. ts_headline only adds the
+// <mark> tags, the snippet source has to be escaped before so the snippet is safe HTML.
+func escapeHTMLSQL(expr string) string {
+	return `replace(replace(replace(replace(replace(` + expr + `, '&', '&amp;'), '<', '&lt;'), '>', '&gt;'), '"', '&quot;'), '''', '&#39;')`
+}
+
+// CreateTicketSearchIndexes creates the full-text indexes used by SearchTickets
+func (r *appRepository) CreateTicketSearchIndexes() error {
+	if err := r.Conn.Exec(`CREATE INDEX IF NOT EXISTS idx_tickets_search ON tickets USING GIN (` + ticketSearchDocument + `)`).Error; err != nil {
+		return err
+	}
+	return r.Conn.Exec(`CREATE INDEX IF NOT EXISTS idx_ticket_comments_search ON ticket_comments USING GIN (` + commentSearchDocument + `)`).Error
+}
+
+type ticketSearchHit struct {
+	TicketID int     `gorm:"column:id_ticket"`
+	Rank     float64 `gorm:"column:search_rank"`
+	Snippet  string  `gorm:"column:search_snippet"`
+}
+
+// SearchTickets ranks tickets matching the query on code, title, description or
+// any comment. The cursor is the rank and ID of the last result ("rank:id").
+func (r *appRepository) SearchTickets(query string, limit int, cursor string, filter domain.TicketFilter) ([]domain.TicketSearchResult, string, error) {
+	// The matching tickets are found through the two GIN indexes first, only they are
+	// ranked and get a snippet
+	candidates := r.Conn.Raw(`SELECT tickets.id_ticket FROM tickets WHERE `+ticketSearchDocument+` @@ `+searchQuery+`
+		UNION
+		SELECT ticket_comments.id_ticket FROM ticket_comments WHERE `+commentSearchDocument+` @@ `+searchQuery, query, query)
+
+	// The best matching comment of every ticket, it adds to the rank and is the
+	// snippet source when only a comment matches
+	bestComment := `LEFT JOIN LATERAL (
+		SELECT ticket_comments.isi_pesan, ts_rank(` + commentSearchDocument + `, ` + searchQuery + `) AS rank
+		FROM ticket_comments
+		WHERE ticket_comments.id_ticket = tickets.id_ticket AND ` + commentSearchDocument + ` @@ ` + searchQuery + `
+		ORDER BY rank DESC
+		LIMIT 1
+	) AS best_comment ON true`
+
+	ranked := r.Conn.Model(&models.Ticket{}).
+		Select(`tickets.id_ticket,
+			(ts_rank(`+ticketSearchDocument+`, `+searchQuery+`) + coalesce(best_comment.rank, 0))::float8 AS search_rank,
+			CASE WHEN `+ticketSearchDocument+` @@ `+searchQuery+`
+				THEN ts_headline('simple', `+escapeHTMLSQL(`tickets.judul || ' ' || coalesce(tickets.deskripsi, '')`)+`, `+searchQuery+`, `+searchHeadlineOptions+`)
+				ELSE ts_headline('simple', `+escapeHTMLSQL(`best_comment.isi_pesan`)+`, `+searchQuery+`, `+searchHeadlineOptions+`)
+			END AS search_snippet`, query, query, query, query).
+		Joins(bestComment, query, query).
+		Where("tickets.id_ticket IN (?)", candidates)
+	ranked = applyTicketFilter(ranked, filter)
+
+	db := r.Conn.Table("(?) AS ranked", ranked)
+	if cursor != "" {
+		rankPart, idPart, found := strings.Cut(cursor, ":")
+		lastRank, rankErr := strconv.ParseFloat(rankPart, 64)
+		lastID, idErr := strconv.Atoi(idPart)
+		if found && rankErr == nil && idErr == nil {
+			db = db.Where("search_rank < ? OR (search_rank = ? AND id_ticket < ?)", lastRank, lastRank, lastID)
+		}
+	}
+
+	var hits []ticketSearchHit
+	if err := db.Order("search_rank desc, id_ticket desc").Limit(limit + 1).Scan(&hits).Error; err != nil {
+		return nil, "", err
+	}
+
+	var nextCursor string
+	if len(hits) > limit {
+		last := hits[limit-1]
+		nextCursor = strconv.FormatFloat(last.Rank, 'g', -1, 64) + ":" + strconv.Itoa(last.TicketID)
+		hits = hits[:limit]
+	}
+	if len(hits) == 0 {
+		return []domain.TicketSearchResult{}, nextCursor, nil
+	}
+
+	ids := make([]int, len(hits))
+	for i, hit := range hits {
+		ids[i] = hit.TicketID
+	}
+	var tickets []models.Ticket
+	if err := r.Conn.Preload("User").Preload("Category").Preload("Priority").Preload("Status").Preload("Tags").
+		Where("id_ticket IN ?", ids).Find(&tickets).Error; err != nil {
+		return nil, "", err
+	}
+	byID := make(map[int]models.Ticket, len(tickets))
+	for _, ticket := range tickets {
+		byID[ticket.ID] = ticket
+	}
+
+	// Keep the rank order of the search
+	results := make([]domain.TicketSearchResult, 0, len(hits))
+	for _, hit := range hits {
+		ticket, ok := byID[hit.TicketID]
+		if !ok {
+			continue
+		}
+		results = append(results, domain.TicketSearchResult{Ticket: ticket, Rank: hit.Rank, Snippet: hit.Snippet})
+	}
+	return results, nextCursor, nil
+}
@@ -0,0 +1,178 @@
+package repositories
+
+import (
+	"app/domain/models"
+	"strings"
+)
+
+func (r *appRepository) CreateTicketStatusTransition(transition *models.TicketStatusTransition) error {
+	return r.Conn.Create(transition).Error
+}
+
+func (r *appRepository) GetTicketStatusTransitions(fromStatusID int) ([]models.TicketStatusTransition, error) {
+	var transitions []models.TicketStatusTransition
+	db := r.Conn.Preload("FromStatus").Preload("ToStatus")
+	if fromStatusID > 0 {
+		db = db.Where("from_status_id = ?", fromStatusID)
+	}
+	err := db.Order("id_transition asc").Find(&transitions).Error
+	return transitions, err
+}
+
+func (r *appRepository) GetTicketStatusTransition(fromStatusID, toStatusID int, event models.WorkflowEvent) (*models.TicketStatusTransition, error) {
+	var transition models.TicketStatusTransition
+	err := r.Conn.Preload("FromStatus").Preload("ToStatus").
+		Where("from_status_id = ? AND to_status_id = ? AND event = ?", fromStatusID, toStatusID, event).
+		First(&transition).Error
+	if err != nil {
+		return nil, err
+	}
+	return &transition, nil
+}
+
+// GetTicketStatusTransitionByEvent returns the first transition leaving fromStatusID for the given event
+func (r *appRepository) GetTicketStatusTransitionByEvent(fromStatusID int, event models.WorkflowEvent) (*models.TicketStatusTransition, error) {
+	var transition models.TicketStatusTransition
+	err := r.Conn.Preload("FromStatus").Preload("ToStatus").
+		Where("from_status_id = ? AND event = ?", fromStatusID, event).
+		Order("id_transition asc").
+		First(&transition).Error
+	if err != nil {
+		return nil, err
+	}
+	return &transition, nil
+}
+
+func (r *appRepository) DeleteTicketStatusTransition(id int) error {
+	return r.Conn.Delete(&models.TicketStatusTransition{}, id).Error
+}
+
+func (r *appRepository) GetInitialTicketStatus() (*models.TicketStatus, error) {
+	var status models.TicketStatus
+	err := r.Conn.Where("is_initial = ?", true).Order("id_status asc").First(&status).Error
+	if err != nil {
+		return nil, err
+	}
+	return &status, nil
+}
+
+type defaultTransition struct {
+	From  string
+	To    string
+	Event models.WorkflowEvent
+	Roles []models.UserRole
+}
+
+// defaultTicketStatuses and defaultTicketTransitions reproduce the lifecycle that used to be
+// hardcoded (1=Open, 2=In Progress, 3=Resolved, 4=Closed)
+var defaultTicketStatuses = []models.TicketStatus{
+	{NamaStatus: "Open", IsInitial: true},
+	{NamaStatus: "In Progress"},
+	{NamaStatus: "Resolved", PausesSLA: true, IsResolved: true},
+	{NamaStatus: "Closed", IsTerminal: true, PausesSLA: true},
+	{NamaStatus: "Pending", PausesSLA: true, IsPending: true},
+}
+
+var defaultTicketTransitions = []defaultTransition{
+	{From: "Open", To: "In Progress", Event: models.WorkflowEventAssign},
+	{From: "In Progress", To: "In Progress", Event: models.WorkflowEventAssign},
+	{From: "Resolved", To: "In Progress", Event: models.WorkflowEventAssign},
+
+	{From: "Open", To: "Resolved", Event: models.WorkflowEventComment},
+	{From: "In Progress", To: "Resolved", Event: models.WorkflowEventComment},
+	{From: "Resolved", To: "Resolved", Event: models.WorkflowEventComment},
+
+	{From: "Open", To: "Closed", Event: models.WorkflowEventMerge},
+	{From: "In Progress", To: "Closed", Event: models.WorkflowEventMerge},
+	{From: "Resolved", To: "Closed", Event: models.WorkflowEventMerge},
+	{From: "Pending", To: "Closed", Event: models.WorkflowEventMerge},
+
+	{From: "Resolved", To: "Open", Event: models.WorkflowEventCustomerReply},
+	{From: "Resolved", To: "Closed", Event: models.WorkflowEventAutoClose},
+
+	{From: "Resolved", To: "Open", Event: models.WorkflowEventReopen},
+	{From: "Closed", To: "Open", Event: models.WorkflowEventReopen},
+
+	{From: "Open", To: "Pending", Event: models.WorkflowEventSnooze, Roles: []models.UserRole{models.RoleAdmin, models.RoleSupport}},
+	{From: "In Progress", To: "Pending", Event: models.WorkflowEventSnooze, Roles: []models.UserRole{models.RoleAdmin, models.RoleSupport}},
+	{From: "Pending", To: "Open", Event: models.WorkflowEventWake},
+
+	{From: "Open", To: "In Progress", Event: models.WorkflowEventManual, Roles: []models.UserRole{models.RoleAdmin, models.RoleSupport}},
+	{From: "Open", To: "Closed", Event: models.WorkflowEventManual, Roles: []models.UserRole{models.RoleAdmin}},
+	{From: "In Progress", To: "Open", Event: models.WorkflowEventManual, Roles: []models.UserRole{models.RoleAdmin, models.RoleSupport}},
+	{From: "In Progress", To: "Resolved", Event: models.WorkflowEventManual, Roles: []models.UserRole{models.RoleAdmin, models.RoleSupport}},
+	{From: "Resolved", To: "In Progress", Event: models.WorkflowEventManual, Roles: []models.UserRole{models.RoleAdmin, models.RoleSupport}},
+	{From: "Resolved", To: "Closed", Event: models.WorkflowEventManual, Roles: []models.UserRole{models.RoleAdmin, models.RoleSupport}},
+}
+
+// SeedTicketWorkflow makes sure the default statuses exist and seeds the default transitions
+// for every workflow event that has no transition configured yet
+func (r *appRepository) SeedTicketWorkflow() error {
+	// Existing installations predate the workflow flags, the defaults are flagged
+	// only while nobody has configured a flag yet
+	configured := make(map[string]bool)
+	for _, column := range []string{"is_initial", "is_terminal", "pauses_sla", "is_resolved", "is_pending"} {
+		var count int64
+		if err := r.Conn.Model(&models.TicketStatus{}).Where(column+" = ?", true).Count(&count).Error; err != nil {
+			return err
+		}
+		configured[column] = count > 0
+	}
+
+	statusIDs := make(map[string]int)
+	for _, def := range defaultTicketStatuses {
+		var status models.TicketStatus
+		err := r.Conn.Where("LOWER(nama_status) = ?", strings.ToLower(def.NamaStatus)).First(&status).Error
+		if err != nil {
+			status = def
+			if err := r.Conn.Create(&status).Error; err != nil {
+				return err
+			}
+		}
+		statusIDs[def.NamaStatus] = status.ID
+
+		flags := map[string]bool{
+			"is_initial":  def.IsInitial && !status.IsInitial,
+			"is_terminal": def.IsTerminal && !status.IsTerminal,
+			"pauses_sla":  def.PausesSLA && !status.PausesSLA,
+			"is_resolved": def.IsResolved && !status.IsResolved,
+			"is_pending":  def.IsPending && !status.IsPending,
+		}
+		for column, missing := range flags {
+			if !missing || configured[column] {
+				continue
+			}
+			if err := r.Conn.Model(&models.TicketStatus{}).Where("id_status = ?", status.ID).Update(column, true).Error; err != nil {
+				return err
+			}
+		}
+	}
+
+	seededEvents := make(map[models.WorkflowEvent]bool)
+	for _, def := range defaultTicketTransitions {
+		seed, checked := seededEvents[def.Event]
+		if !checked {
+			var count int64
+			if err := r.Conn.Model(&models.TicketStatusTransition{}).Where("event = ?", def.Event).Count(&count).Error; err != nil {
+				return err
+			}
+			seed = count == 0
+			seededEvents[def.Event] = seed
+		}
+		if !seed {
+			continue
+		}
+
+		transition := models.TicketStatusTransition{
+			FromStatusID: statusIDs[def.From],
+			ToStatusID:   statusIDs[def.To],
+			Event:        def.Event,
+			AllowedRoles: def.Roles,
+		}
+		if err := r.Conn.Create(&transition).Error; err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
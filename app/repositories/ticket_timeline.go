@@ -0,0 +1,151 @@
+package repositories
+
+import (
+	"database/sql"
+	"strconv"
+	"strings"
+	"time"
+
+	"app/domain"
+	"app/domain/models"
+)
+
+// ticketTimelineEvents merges the records of a ticket into one event stream. Logs written
+// for a comment, attachment or assignment being added are left out, the record itself is
+// the event. Attachments and assignments take their actor from that log entry.
+const ticketTimelineEvents = `
+	SELECT 'comment' AS event_type, c.id_comment AS event_id, c.tanggal_dibuat AS occurred_at,
+		CAST(c.id_user AS BIGINT) AS actor_id, c.isi_pesan AS content, '' AS action,
+		'' AS field, '' AS old_value, '' AS new_value, '' AS file_path, CAST(NULL AS BIGINT) AS assignee_id
+	FROM ticket_comments c
+	WHERE c.id_ticket = @ticket
+	UNION ALL
+	SELECT CASE WHEN l.action = @statusChanged THEN 'status_change' ELSE 'log' END, l.id_log, l.waktu,
+		CAST(l.id_user AS BIGINT), l.aktivitas, l.action,
+		COALESCE(l.field, ''), COALESCE(l.old_value, ''), COALESCE(l.new_value, ''), '', NULL
+	FROM ticket_logs l
+	WHERE l.id_ticket = @ticket AND l.action NOT IN @recordActions
+	UNION ALL
+	SELECT 'attachment', a.id_attachment, a.uploaded_at,
+		(SELECT CAST(l.id_user AS BIGINT) FROM ticket_logs l
+			WHERE l.action = @attachmentCreated AND l.new_value = a.file_path
+			ORDER BY l.id_log DESC LIMIT 1),
+		'', '', '', '', '', a.file_path, NULL
+	FROM ticket_attachments a
+	WHERE a.id_ticket = @ticket
+	UNION ALL
+	SELECT 'assignment', s.id_assignment, s.tanggal_ditugaskan,
+		(SELECT CAST(l.id_user AS BIGINT) FROM ticket_logs l
+			WHERE l.action = @assignmentCreated AND l.id_ticket = s.id_ticket AND l.new_value = CAST(s.id_admin AS TEXT)
+			ORDER BY l.id_log DESC LIMIT 1),
+		'', '', '', '', '', '', CAST(s.id_admin AS BIGINT)
+	FROM ticket_assignments s
+	WHERE s.id_ticket = @ticket`
+
+type ticketTimelineRow struct {
+	EventType  string
+	EventID    int
+	OccurredAt time.Time
+	ActorID    *uint64
+	Content    string
+	Action     string
+	Field      string
+	OldValue   string
+	NewValue   string
+	FilePath   string
+	AssigneeID *uint64
+}
+
+// GetTicketTimeline returns the events of a ticket of the given types, oldest first. The
+// cursor is the time, type and ID of the last event ("unixnano:type:id").
+func (r *appRepository) GetTicketTimeline(ticketID int, types []domain.TimelineEventType, limit int, cursor string) ([]domain.TimelineEvent, string, error) {
+	events := r.Conn.Raw(ticketTimelineEvents,
+		sql.Named("ticket", ticketID),
+		sql.Named("statusChanged", string(models.TicketLogStatusChanged)),
+		sql.Named("attachmentCreated", string(models.TicketLogAttachmentCreated)),
+		sql.Named("assignmentCreated", string(models.TicketLogAssignmentCreated)),
+		sql.Named("recordActions", []models.TicketLogAction{
+			models.TicketLogCommentCreated,
+			models.TicketLogAttachmentCreated,
+			models.TicketLogAssignmentCreated,
+		}),
+	)
+
+	db := r.Conn.Table("(?) AS timeline", events).Where("event_type IN ?", types)
+	if cursor != "" {
+		parts := strings.SplitN(cursor, ":", 3)
+		if len(parts) == 3 {
+			nanos, timeErr := strconv.ParseInt(parts[0], 10, 64)
+			lastID, idErr := strconv.Atoi(parts[2])
+			if timeErr == nil && idErr == nil {
+				db = db.Where("(occurred_at, event_type, event_id) > (?, ?, ?)", time.Unix(0, nanos), parts[1], lastID)
+			}
+		}
+	}
+
+	var rows []ticketTimelineRow
+	if err := db.Order("occurred_at asc, event_type asc, event_id asc").Limit(limit + 1).Scan(&rows).Error; err != nil {
+		return nil, "", err
+	}
+
+	var nextCursor string
+	if len(rows) > limit {
+		last := rows[limit-1]
+		nextCursor = strconv.FormatInt(last.OccurredAt.UnixNano(), 10) + ":" + last.EventType + ":" + strconv.Itoa(last.EventID)
+		rows = rows[:limit]
+	}
+
+	actors, err := r.timelineActors(rows)
+	if err != nil {
+		return nil, "", err
+	}
+
+	timeline := make([]domain.TimelineEvent, 0, len(rows))
+	for _, row := range rows {
+		event := domain.TimelineEvent{
+			Type:       domain.TimelineEventType(row.EventType),
+			ID:         row.EventID,
+			OccurredAt: row.OccurredAt,
+			Content:    row.Content,
+			Action:     models.TicketLogAction(row.Action),
+			Field:      row.Field,
+			OldValue:   row.OldValue,
+			NewValue:   row.NewValue,
+			FilePath:   row.FilePath,
+		}
+		if row.ActorID != nil {
+			event.Actor = actors[*row.ActorID]
+		}
+		if row.AssigneeID != nil {
+			event.Assignee = actors[*row.AssigneeID]
+		}
+		timeline = append(timeline, event)
+	}
+	return timeline, nextCursor, nil
+}
+
+// timelineActors loads the users referenced by the rows in one query
+func (r *appRepository) timelineActors(rows []ticketTimelineRow) (map[uint64]*domain.TimelineActor, error) {
+	var ids []uint64
+	for _, row := range rows {
+		if row.ActorID != nil {
+			ids = append(ids, *row.ActorID)
+		}
+		if row.AssigneeID != nil {
+			ids = append(ids, *row.AssigneeID)
+		}
+	}
+	actors := make(map[uint64]*domain.TimelineActor, len(ids))
+	if len(ids) == 0 {
+		return actors, nil
+	}
+
+	var users []models.User
+	if err := r.Conn.Select("id", "username", "role").Where("id IN ?", ids).Find(&users).Error; err != nil {
+		return nil, err
+	}
+	for _, user := range users {
+		actors[user.ID] = &domain.TimelineActor{ID: user.ID, Username: user.Username, Role: user.Role}
+	}
+	return actors, nil
+}
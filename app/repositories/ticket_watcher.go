@@ -0,0 +1,22 @@
+package repositories
+
+import (
+	"app/domain/models"
+
+	"gorm.io/gorm/clause"
+)
+
+// AddTicketWatcher adds a watcher, watching a ticket twice is a no-op
+func (r *appRepository) AddTicketWatcher(watcher *models.TicketWatcher) error {
+	return r.Conn.Clauses(clause.OnConflict{DoNothing: true}).Create(watcher).Error
+}
+
+func (r *appRepository) RemoveTicketWatcher(ticketID int, userID uint64) error {
+	return r.Conn.Where("id_ticket = ? AND id_user = ?", ticketID, userID).Delete(&models.TicketWatcher{}).Error
+}
+
+func (r *appRepository) GetTicketWatchers(ticketID int) ([]models.TicketWatcher, error) {
+	var watchers []models.TicketWatcher
+	err := r.Conn.Preload("User").Where("id_ticket = ?", ticketID).Order("id_watcher asc").Find(&watchers).Error
+	return watchers, err
+}
@@ -18,4 +18,14 @@ func (r *appRepository) GetUsersByRole(role models.UserRole) ([]models.User, err
     var users []models.User
     err := r.Conn.Where("role = ?", role).Find(&users).Error
     return users, err
-}
\ No newline at end of file
+}
+
+// GetUserByUsernameOrEmail finds a user by username or email, case-insensitive
+func (r *appRepository) GetUserByUsernameOrEmail(login string) (*models.User, error) {
+	var user models.User
+	err := r.Conn.Where("LOWER(username) = LOWER(?) OR LOWER(email) = LOWER(?)", login, login).First(&user).Error
+	if err != nil {
+		return nil, err
+	}
+	return &user, nil
+}
@@ -21,6 +21,7 @@ func (s *appService) GetAdminListConversationStates(claim models.User) helpers.R
 
 	// Get additional ticket statistics
 	total, inProgress, resolved, priorityCounts, statsErr := s.repo.GetTicketStatistics()
+	reopenedTickets, totalReopens, reopenErr := s.repo.GetTicketReopenStatistics()
 
 	// Prepare response data
 	responseData := map[string]interface{}{
@@ -32,6 +33,8 @@ func (s *appService) GetAdminListConversationStates(claim models.User) helpers.R
 			"total_tickets":         total,
 			"in_progress_tickets":   inProgress,
 			"resolved_tickets":      resolved,
+			"reopened_tickets":      reopenedTickets,
+			"total_reopens":         totalReopens,
 			"priority_counts": map[string]int{
 				"low":      priorityCounts[1],
 				"medium":   priorityCounts[2],
@@ -51,5 +54,10 @@ func (s *appService) GetAdminListConversationStates(claim models.User) helpers.R
 		responseData["ticket_notifications"].(map[string]interface{})["statistics_error"] = statsErr.Error()
 	}
 
+	// Add error info if reopen statistics failed
+	if reopenErr != nil {
+		responseData["ticket_notifications"].(map[string]interface{})["reopen_statistics_error"] = reopenErr.Error()
+	}
+
 	return helpers.NewResponse(http.StatusOK, "Successfully retrieved conversation states and ticket notifications", nil, responseData)
 }
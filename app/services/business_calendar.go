@@ -0,0 +1,344 @@
+package services
+
+import (
+	"app/domain"
+	"app/domain/models"
+	"errors"
+	"fmt"
+	"log"
+	"sort"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+func (s *appService) CreateBusinessCalendar(calendar *models.BusinessCalendar) error {
+	if err := validateBusinessCalendar(calendar); err != nil {
+		return err
+	}
+	return s.repo.CreateBusinessCalendar(calendar)
+}
+
+func (s *appService) GetBusinessCalendars() ([]models.BusinessCalendar, error) {
+	return s.repo.GetBusinessCalendars()
+}
+
+func (s *appService) GetBusinessCalendarByID(id int) (*models.BusinessCalendar, error) {
+	return s.repo.GetBusinessCalendarByID(id)
+}
+
+func (s *appService) UpdateBusinessCalendar(calendar *models.BusinessCalendar) error {
+	if err := validateBusinessCalendar(calendar); err != nil {
+		return err
+	}
+	if err := s.repo.UpdateBusinessCalendar(calendar); err != nil {
+		return err
+	}
+
+	updated, err := s.repo.GetBusinessCalendarByID(calendar.ID)
+	if err != nil {
+		return err
+	}
+	*calendar = *updated
+	return nil
+}
+
+func (s *appService) DeleteBusinessCalendar(id int) error {
+	return s.repo.DeleteBusinessCalendar(id)
+}
+
+func (s *appService) CreateHoliday(holiday *models.Holiday) error {
+	if _, err := time.Parse("2006-01-02", holiday.Tanggal); err != nil {
+		return &domain.ValidationError{Fields: map[string]string{"tanggal": "must use the YYYY-MM-DD format"}}
+	}
+	if _, err := s.repo.GetBusinessCalendarByID(holiday.CalendarID); err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return fmt.Errorf("%w: business calendar %d", domain.ErrRecordNotFound, holiday.CalendarID)
+		}
+		return err
+	}
+	return s.repo.CreateHoliday(holiday)
+}
+
+func (s *appService) DeleteHoliday(calendarID, holidayID int) error {
+	return s.repo.DeleteHoliday(calendarID, holidayID)
+}
+
+func validateBusinessCalendar(calendar *models.BusinessCalendar) error {
+	var validation domain.ValidationError
+	if calendar.NamaCalendar == "" {
+		validation.Add("nama_calendar", "is required")
+	}
+	if calendar.Timezone == "" {
+		calendar.Timezone = "Asia/Jakarta"
+	}
+	if _, err := time.LoadLocation(calendar.Timezone); err != nil {
+		validation.Add("timezone", fmt.Sprintf("unknown timezone %q", calendar.Timezone))
+	}
+
+	// Windows of the same weekday may touch but not overlap, the clock would count the
+	// shared time twice
+	type window struct{ index, start, end int }
+	windows := make(map[int][]window)
+	for i, hour := range calendar.Hours {
+		field := fmt.Sprintf("hours.%d", i)
+		if hour.Weekday < 0 || hour.Weekday > 6 {
+			validation.Add(field+".weekday", "must be between 0 (Sunday) and 6 (Saturday)")
+			continue
+		}
+		start, err := parseClockMinutes(hour.StartTime)
+		if err != nil {
+			validation.Add(field+".start_time", "must use the HH:MM format")
+			continue
+		}
+		end, err := parseClockMinutes(hour.EndTime)
+		if err != nil {
+			validation.Add(field+".end_time", "must use the HH:MM format")
+			continue
+		}
+		if end <= start {
+			validation.Add(field+".end_time", "must be after start_time")
+			continue
+		}
+		windows[hour.Weekday] = append(windows[hour.Weekday], window{i, start, end})
+	}
+	for _, day := range windows {
+		sort.Slice(day, func(i, j int) bool { return day[i].start < day[j].start })
+		latest := day[0] // the window ending last so far
+		for _, current := range day[1:] {
+			if current.start < latest.end {
+				validation.Add(fmt.Sprintf("hours.%d", current.index), fmt.Sprintf("overlaps hours.%d on the same weekday", latest.index))
+			}
+			if current.end > latest.end {
+				latest = current
+			}
+		}
+	}
+
+	for i, holiday := range calendar.Holidays {
+		if _, err := time.Parse("2006-01-02", holiday.Tanggal); err != nil {
+			validation.Add(fmt.Sprintf("holidays.%d.tanggal", i), "must use the YYYY-MM-DD format")
+		}
+	}
+	return validation.OrNil()
+}
+
+// parseClockMinutes converts HH:MM into minutes since midnight. 24:00 is the end of the day,
+// a shift past midnight is a window ending at 24:00 and one starting at 00:00 the next day.
+func parseClockMinutes(value string) (int, error) {
+	if value == "24:00" {
+		return 24 * 60, nil
+	}
+	t, err := time.Parse("15:04", value)
+	if err != nil {
+		return 0, fmt.Errorf("invalid time %q, use HH:MM", value)
+	}
+	return t.Hour()*60 + t.Minute(), nil
+}
+
+// businessClock adds and measures durations in the working time of a calendar.
+// A nil clock measures wall-clock time.
+type businessClock struct {
+	location *time.Location
+	windows  map[time.Weekday][][2]int // working windows in minutes since midnight
+	holidays map[string]bool
+}
+
+// maxCalendarDays bounds the day by day walks of a clock
+const maxCalendarDays = 3660
+
+func newBusinessClock(calendar *models.BusinessCalendar) (*businessClock, error) {
+	location, err := time.LoadLocation(calendar.Timezone)
+	if err != nil {
+		return nil, err
+	}
+
+	clock := &businessClock{
+		location: location,
+		windows:  make(map[time.Weekday][][2]int),
+		holidays: make(map[string]bool),
+	}
+	for _, hour := range calendar.Hours {
+		start, err := parseClockMinutes(hour.StartTime)
+		if err != nil {
+			return nil, err
+		}
+		end, err := parseClockMinutes(hour.EndTime)
+		if err != nil {
+			return nil, err
+		}
+		day := time.Weekday(hour.Weekday)
+		clock.windows[day] = append(clock.windows[day], [2]int{start, end})
+	}
+	if len(clock.windows) == 0 {
+		// A calendar without working hours cannot move time forward
+		return nil, fmt.Errorf("calendar %q has no working hours", calendar.NamaCalendar)
+	}
+	for day := range clock.windows {
+		sort.Slice(clock.windows[day], func(i, j int) bool { return clock.windows[day][i][0] < clock.windows[day][j][0] })
+	}
+	for _, holiday := range calendar.Holidays {
+		clock.holidays[holiday.Tanggal] = true
+	}
+
+	return clock, nil
+}
+
+// workingWindows returns the working windows of the day starting at midnight
+func (c *businessClock) workingWindows(midnight time.Time) [][2]time.Time {
+	if c.holidays[midnight.Format("2006-01-02")] {
+		return nil
+	}
+	var windows [][2]time.Time
+	for _, window := range c.windows[midnight.Weekday()] {
+		windows = append(windows, [2]time.Time{
+			time.Date(midnight.Year(), midnight.Month(), midnight.Day(), 0, window[0], 0, 0, c.location),
+			time.Date(midnight.Year(), midnight.Month(), midnight.Day(), 0, window[1], 0, 0, c.location),
+		})
+	}
+	return windows
+}
+
+// Add returns the moment d of working time after start, a negative d goes back from start
+func (c *businessClock) Add(start time.Time, d time.Duration) time.Time {
+	if c == nil {
+		return start.Add(d)
+	}
+	if d < 0 {
+		return c.subtract(start, -d)
+	}
+
+	cursor := start.In(c.location)
+	remaining := d
+	midnight := time.Date(cursor.Year(), cursor.Month(), cursor.Day(), 0, 0, 0, 0, c.location)
+	for i := 0; i < maxCalendarDays; i++ {
+		for _, window := range c.workingWindows(midnight) {
+			from := window[0]
+			if cursor.After(from) {
+				from = cursor
+			}
+			if !from.Before(window[1]) {
+				continue
+			}
+			available := window[1].Sub(from)
+			if remaining <= available {
+				return from.Add(remaining)
+			}
+			remaining -= available
+		}
+		midnight = midnight.AddDate(0, 0, 1)
+	}
+
+	return start.Add(d)
+}
+
+// subtract returns the moment d of working time before end
+func (c *businessClock) subtract(end time.Time, d time.Duration) time.Time {
+	cursor := end.In(c.location)
+	remaining := d
+	midnight := time.Date(cursor.Year(), cursor.Month(), cursor.Day(), 0, 0, 0, 0, c.location)
+	for i := 0; i < maxCalendarDays; i++ {
+		windows := c.workingWindows(midnight)
+		for j := len(windows) - 1; j >= 0; j-- {
+			to := windows[j][1]
+			if cursor.Before(to) {
+				to = cursor
+			}
+			if !to.After(windows[j][0]) {
+				continue
+			}
+			available := to.Sub(windows[j][0])
+			if remaining <= available {
+				return to.Add(-remaining)
+			}
+			remaining -= available
+		}
+		midnight = midnight.AddDate(0, 0, -1)
+	}
+
+	return end.Add(-d)
+}
+
+// Between returns the working time elapsed from from to to
+func (c *businessClock) Between(from, to time.Time) time.Duration {
+	if c == nil {
+		return to.Sub(from)
+	}
+	if !to.After(from) {
+		return 0
+	}
+
+	var total time.Duration
+	from = from.In(c.location)
+	midnight := time.Date(from.Year(), from.Month(), from.Day(), 0, 0, 0, 0, c.location)
+	for i := 0; i < maxCalendarDays && midnight.Before(to); i++ {
+		for _, window := range c.workingWindows(midnight) {
+			start, end := window[0], window[1]
+			if from.After(start) {
+				start = from
+			}
+			if to.Before(end) {
+				end = to
+			}
+			if end.After(start) {
+				total += end.Sub(start)
+			}
+		}
+		midnight = midnight.AddDate(0, 0, 1)
+	}
+
+	return total
+}
+
+// AddDays returns the same clock time n working days after start, negative days go back
+// from start
+func (c *businessClock) AddDays(start time.Time, days int) time.Time {
+	if c == nil {
+		return start.AddDate(0, 0, days)
+	}
+
+	step := 1
+	if days < 0 {
+		step, days = -1, -days
+	}
+	cursor := start.In(c.location)
+	for i := 0; days > 0 && i < maxCalendarDays; i++ {
+		cursor = cursor.AddDate(0, 0, step)
+		midnight := time.Date(cursor.Year(), cursor.Month(), cursor.Day(), 0, 0, 0, 0, c.location)
+		if len(c.workingWindows(midnight)) > 0 {
+			days--
+		}
+	}
+	return cursor
+}
+
+// calendarClock builds the clock of a calendar, falling back to the default calendar
+// when calendarID is nil and to wall-clock time when no calendar applies
+func (s *appService) calendarClock(calendarID *int) *businessClock {
+	var calendar *models.BusinessCalendar
+	var err error
+	if calendarID != nil {
+		calendar, err = s.repo.GetBusinessCalendarByID(*calendarID)
+	} else {
+		calendar, err = s.repo.GetDefaultBusinessCalendar()
+	}
+	if err != nil {
+		if !errors.Is(err, gorm.ErrRecordNotFound) {
+			log.Printf("[business-calendar] failed to load calendar: %v", err)
+		}
+		return nil
+	}
+
+	clock, err := newBusinessClock(calendar)
+	if err != nil {
+		log.Printf("[business-calendar] falling back to wall-clock time: %v", err)
+		return nil
+	}
+	return clock
+}
+
+// defaultBusinessClock is the clock of the default calendar, used by timers that are not
+// bound to an SLA policy
+func (s *appService) defaultBusinessClock() *businessClock {
+	return s.calendarClock(nil)
+}
@@ -0,0 +1,224 @@
+package services
+
+import (
+	"app/domain"
+	"app/domain/models"
+	"errors"
+	"testing"
+	"time"
+	_ "time/tzdata" // the tests need Asia/Jakarta without a system timezone database
+)
+
+// jakarta has no daylight saving time, so every working day has the same length
+var jakarta = mustLoadLocation("Asia/Jakarta")
+
+func mustLoadLocation(name string) *time.Location {
+	location, err := time.LoadLocation(name)
+	if err != nil {
+		panic(err)
+	}
+	return location
+}
+
+// at parses "YYYY-MM-DD HH:MM" in Asia/Jakarta. 2026-08-14 is a Friday and 2026-08-17, a
+// Monday, is a holiday of the office calendar.
+func at(value string) time.Time {
+	t, err := time.ParseInLocation("2006-01-02 15:04", value, jakarta)
+	if err != nil {
+		panic(err)
+	}
+	return t
+}
+
+func newTestClock(t *testing.T, hours []models.BusinessHour, holidays ...string) *businessClock {
+	t.Helper()
+	calendar := &models.BusinessCalendar{NamaCalendar: "test", Timezone: "Asia/Jakarta", Hours: hours}
+	for _, tanggal := range holidays {
+		calendar.Holidays = append(calendar.Holidays, models.Holiday{Tanggal: tanggal})
+	}
+	clock, err := newBusinessClock(calendar)
+	if err != nil {
+		t.Fatalf("newBusinessClock() error = %v", err)
+	}
+	return clock
+}
+
+// officeClock works Monday to Friday 09:00-12:00 and 13:00-17:00
+func officeClock(t *testing.T) *businessClock {
+	var hours []models.BusinessHour
+	for weekday := 1; weekday <= 5; weekday++ {
+		hours = append(hours,
+			models.BusinessHour{Weekday: weekday, StartTime: "09:00", EndTime: "12:00"},
+			models.BusinessHour{Weekday: weekday, StartTime: "13:00", EndTime: "17:00"})
+	}
+	return newTestClock(t, hours, "2026-08-17")
+}
+
+// nightClock works a single shift from Monday 22:00 to Tuesday 06:00
+func nightClock(t *testing.T) *businessClock {
+	return newTestClock(t, []models.BusinessHour{
+		{Weekday: 1, StartTime: "22:00", EndTime: "24:00"},
+		{Weekday: 2, StartTime: "00:00", EndTime: "06:00"},
+	})
+}
+
+func TestBusinessClockAdd(t *testing.T) {
+	office, night := officeClock(t), nightClock(t)
+
+	tests := []struct {
+		name  string
+		clock *businessClock
+		start time.Time
+		d     time.Duration
+		want  time.Time
+	}{
+		{"within a window", office, at("2026-08-14 10:00"), time.Hour, at("2026-08-14 11:00")},
+		{"zero duration", office, at("2026-08-14 10:00"), 0, at("2026-08-14 10:00")},
+		{"over lunch", office, at("2026-08-14 11:00"), 2 * time.Hour, at("2026-08-14 14:00")},
+		{"before opening", office, at("2026-08-14 07:00"), 30 * time.Minute, at("2026-08-14 09:30")},
+		{"over the weekend and a holiday", office, at("2026-08-14 16:00"), 2 * time.Hour, at("2026-08-18 10:00")},
+		{"start in another timezone", office, at("2026-08-14 10:00").UTC(), time.Hour, at("2026-08-14 11:00")},
+		{"back over lunch", office, at("2026-08-14 14:00"), -2 * time.Hour, at("2026-08-14 11:00")},
+		{"back over a holiday and the weekend", office, at("2026-08-18 10:00"), -2 * time.Hour, at("2026-08-14 16:00")},
+		{"past midnight", night, at("2026-08-17 23:00"), 2 * time.Hour, at("2026-08-18 01:00")},
+		{"back past midnight", night, at("2026-08-18 01:00"), -2 * time.Hour, at("2026-08-17 23:00")},
+		{"to the next shift", night, at("2026-08-18 05:00"), 2 * time.Hour, at("2026-08-24 23:00")},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tt.clock.Add(tt.start, tt.d); !got.Equal(tt.want) {
+				t.Errorf("Add() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestBusinessClockBetween(t *testing.T) {
+	office, night := officeClock(t), nightClock(t)
+
+	tests := []struct {
+		name     string
+		clock    *businessClock
+		from, to time.Time
+		want     time.Duration
+	}{
+		{"within a window", office, at("2026-08-14 10:00"), at("2026-08-14 11:00"), time.Hour},
+		{"over lunch", office, at("2026-08-14 11:00"), at("2026-08-14 14:00"), 2 * time.Hour},
+		{"outside working hours", office, at("2026-08-14 18:00"), at("2026-08-14 23:00"), 0},
+		{"over the weekend and a holiday", office, at("2026-08-14 16:00"), at("2026-08-18 10:00"), 2 * time.Hour},
+		{"from another timezone", office, at("2026-08-14 10:00").UTC(), at("2026-08-14 11:00"), time.Hour},
+		{"reversed", office, at("2026-08-14 11:00"), at("2026-08-14 10:00"), 0},
+		{"past midnight", night, at("2026-08-17 23:00"), at("2026-08-18 01:00"), 2 * time.Hour},
+		{"whole shift", night, at("2026-08-17 00:00"), at("2026-08-19 00:00"), 8 * time.Hour},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tt.clock.Between(tt.from, tt.to); got != tt.want {
+				t.Errorf("Between() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestBusinessClockAddDays(t *testing.T) {
+	office := officeClock(t)
+
+	tests := []struct {
+		name  string
+		start time.Time
+		days  int
+		want  time.Time
+	}{
+		{"next day", at("2026-08-13 10:00"), 1, at("2026-08-14 10:00")},
+		{"zero days", at("2026-08-14 10:00"), 0, at("2026-08-14 10:00")},
+		{"over the weekend and a holiday", at("2026-08-14 10:00"), 1, at("2026-08-18 10:00")},
+		{"a working week", at("2026-08-14 10:00"), 5, at("2026-08-24 10:00")},
+		{"back over a holiday and the weekend", at("2026-08-18 10:00"), -1, at("2026-08-14 10:00")},
+		{"from a weekend", at("2026-08-15 10:00"), 1, at("2026-08-18 10:00")},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := office.AddDays(tt.start, tt.days); !got.Equal(tt.want) {
+				t.Errorf("AddDays() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestNilBusinessClock(t *testing.T) {
+	var clock *businessClock
+	start := at("2026-08-15 10:00")
+
+	if got, want := clock.Add(start, 3*time.Hour), at("2026-08-15 13:00"); !got.Equal(want) {
+		t.Errorf("Add() = %v, want %v", got, want)
+	}
+	if got, want := clock.Between(start, at("2026-08-16 10:00")), 24*time.Hour; got != want {
+		t.Errorf("Between() = %v, want %v", got, want)
+	}
+	if got, want := clock.AddDays(start, 2), at("2026-08-17 10:00"); !got.Equal(want) {
+		t.Errorf("AddDays() = %v, want %v", got, want)
+	}
+}
+
+func TestValidateBusinessCalendar(t *testing.T) {
+	tests := []struct {
+		name  string
+		hours []models.BusinessHour
+		field string // the reported field, empty when the calendar is valid
+	}{
+		{"separate windows", []models.BusinessHour{
+			{Weekday: 1, StartTime: "09:00", EndTime: "12:00"},
+			{Weekday: 1, StartTime: "13:00", EndTime: "17:00"},
+		}, ""},
+		{"touching windows", []models.BusinessHour{
+			{Weekday: 1, StartTime: "09:00", EndTime: "12:00"},
+			{Weekday: 1, StartTime: "12:00", EndTime: "17:00"},
+		}, ""},
+		{"shift past midnight", []models.BusinessHour{
+			{Weekday: 1, StartTime: "22:00", EndTime: "24:00"},
+			{Weekday: 2, StartTime: "00:00", EndTime: "06:00"},
+		}, ""},
+		{"same window on other weekdays", []models.BusinessHour{
+			{Weekday: 1, StartTime: "09:00", EndTime: "17:00"},
+			{Weekday: 2, StartTime: "09:00", EndTime: "17:00"},
+		}, ""},
+		{"overlapping windows", []models.BusinessHour{
+			{Weekday: 1, StartTime: "13:00", EndTime: "17:00"},
+			{Weekday: 1, StartTime: "09:00", EndTime: "14:00"},
+		}, "hours.0"},
+		{"nested windows", []models.BusinessHour{
+			{Weekday: 3, StartTime: "08:00", EndTime: "18:00"},
+			{Weekday: 3, StartTime: "10:00", EndTime: "11:00"},
+		}, "hours.1"},
+		{"window inside an earlier one", []models.BusinessHour{
+			{Weekday: 3, StartTime: "08:00", EndTime: "18:00"},
+			{Weekday: 3, StartTime: "09:00", EndTime: "10:00"},
+			{Weekday: 3, StartTime: "12:00", EndTime: "13:00"},
+		}, "hours.2"},
+		{"end before start", []models.BusinessHour{{Weekday: 1, StartTime: "17:00", EndTime: "09:00"}}, "hours.0.end_time"},
+		{"invalid time", []models.BusinessHour{{Weekday: 1, StartTime: "9am", EndTime: "17:00"}}, "hours.0.start_time"},
+		{"invalid weekday", []models.BusinessHour{{Weekday: 7, StartTime: "09:00", EndTime: "17:00"}}, "hours.0.weekday"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := validateBusinessCalendar(&models.BusinessCalendar{NamaCalendar: "test", Hours: tt.hours})
+			if tt.field == "" {
+				if err != nil {
+					t.Fatalf("validateBusinessCalendar() error = %v", err)
+				}
+				return
+			}
+			var validation *domain.ValidationError
+			if !errors.As(err, &validation) {
+				t.Fatalf("validateBusinessCalendar() error = %v, want a validation error", err)
+			}
+			if _, ok := validation.Fields[tt.field]; !ok {
+				t.Errorf("validation fields = %v, want %s", validation.Fields, tt.field)
+			}
+		})
+	}
+}
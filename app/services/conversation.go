@@ -1,6 +1,7 @@
 package services
 
 import (
+	"app/domain"
 	"app/domain/models"
 	"app/helpers"
 	"context"
@@ -9,11 +10,11 @@ import (
 	"time"
 )
 
-func (s *appService) GetConversations(claim models.User) helpers.Response {
+func (s *appService) GetConversations(claim models.User, tags domain.TagFilter) helpers.Response {
 	var conversations []models.Conversation
 	var err error
 	if claim.Role == "admin" {
-		conversations, err = s.repo.GetAdminConversations(claim.ID)
+		conversations, err = s.repo.GetAdminConversations(claim.ID, tags)
 
 		if err != nil {
 			return helpers.NewResponse(http.StatusInternalServerError, "Failed to get conversations", nil, nil)
@@ -51,7 +52,7 @@ func (s *appService) GetConversations(claim models.User) helpers.Response {
 		}
 		return helpers.NewResponse(http.StatusOK, "Successfully get conversation", nil, list)
 	} else {
-		conversations, err := s.repo.GetCustomerConversations(claim.ID)
+		conversations, err := s.repo.GetCustomerConversations(claim.ID, tags)
 		if err != nil {
 			return helpers.NewResponse(http.StatusInternalServerError, "failed to get conversations", nil, nil)
 		}
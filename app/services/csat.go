@@ -0,0 +1,290 @@
+package services
+
+import (
+	"app/domain"
+	"app/domain/models"
+	"errors"
+	"fmt"
+	"log"
+	"net/url"
+	"os"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/golang-jwt/jwt/v5"
+)
+
+// csatTokenAudience keeps survey tokens apart from any other token signed with the same key
+const csatTokenAudience = "csat"
+
+// CSATRatingLink is one rating button of a survey email
+type CSATRatingLink struct {
+	Rating int
+	URL    string
+}
+
+type CSATSurveyEmailData struct {
+	UserName    string
+	TicketId    string
+	TicketTitle string
+	SurveyLinks []CSATRatingLink
+	ExpiresAt   string
+	CurrentYear int
+}
+
+// GetCSATSurvey returns the survey a signed survey link points to
+func (s *appService) GetCSATSurvey(token string) (*models.CSATSurvey, error) {
+	surveyID, err := parseCSATSurveyToken(token)
+	if err != nil {
+		return nil, err
+	}
+	survey, err := s.repo.GetCSATSurveyByID(surveyID)
+	if err != nil {
+		return nil, fmt.Errorf("%w: survey %d", domain.ErrRecordNotFound, surveyID)
+	}
+	return survey, nil
+}
+
+// AnswerCSATSurvey stores the rating of a survey, a survey can only be answered once and
+// only until its link expires
+func (s *appService) AnswerCSATSurvey(token string, rating int, comment string) (*models.CSATSurvey, error) {
+	var validation domain.ValidationError
+	if rating < models.CSATMinRating || rating > models.CSATMaxRating {
+		validation.Add("rating", fmt.Sprintf("must be between %d and %d", models.CSATMinRating, models.CSATMaxRating))
+	}
+	if err := validation.OrNil(); err != nil {
+		return nil, err
+	}
+
+	survey, err := s.GetCSATSurvey(token)
+	if err != nil {
+		return nil, err
+	}
+	now := time.Now()
+	if survey.RespondedAt != nil {
+		return nil, domain.ErrSurveyAnswered
+	}
+	if now.After(survey.ExpiresAt) {
+		return nil, domain.ErrSurveyExpired
+	}
+
+	comment = strings.TrimSpace(comment)
+	answered, err := s.repo.AnswerCSATSurvey(survey.ID, rating, comment, now)
+	if err != nil {
+		return nil, err
+	}
+	if !answered {
+		return nil, domain.ErrSurveyAnswered
+	}
+
+	survey.Rating = &rating
+	survey.Comment = comment
+	survey.RespondedAt = &now
+	if err := s.recordTicketLog(survey.TicketID, models.User{ID: survey.UserID}, models.TicketLogCSATRated, fmt.Sprintf("Customer rated the support %d of %d", rating, models.CSATMaxRating)); err != nil {
+		return nil, err
+	}
+	return survey, nil
+}
+
+// GetCSATReport aggregates the answered surveys per agent, category or period
+func (s *appService) GetCSATReport(filter domain.CSATFilter) ([]domain.CSATAggregate, error) {
+	return s.repo.GetCSATAggregates(filter)
+}
+
+// requestCSATSurvey emails a survey to the owner of a ticket that was just resolved. It runs
+// once the change is committed and in the background, like the watcher notifications.
+func (s *appService) requestCSATSurvey(ticket *models.Ticket) {
+	snapshot := *ticket
+	base := s.base()
+	s.onCommit(func() { go base.sendCSATSurveyEmail(&snapshot) })
+}
+
+func (s *appService) sendCSATSurveyEmail(ticket *models.Ticket) {
+	surveyURL, expiresAt, err := s.createCSATSurvey(ticket)
+	if err != nil {
+		log.Printf("[csat] failed to create survey for ticket #%s: %v", ticket.KodeTiket, err)
+		return
+	}
+
+	data := CSATSurveyEmailData{
+		UserName:    ticket.User.Username,
+		TicketId:    ticket.KodeTiket,
+		TicketTitle: ticket.Judul,
+		SurveyLinks: csatRatingLinks(surveyURL),
+		ExpiresAt:   expiresAt.Format("02 January 2006, 15:04"),
+		CurrentYear: time.Now().Year(),
+	}
+	htmlBody, err := renderEmailTemplate(csatSurveyEmailTemplate, data)
+	if err != nil {
+		log.Printf("[csat] failed to render survey email for ticket #%s: %v", ticket.KodeTiket, err)
+		return
+	}
+	if err := s.sendEmail(ticket.User.Email, fmt.Sprintf("Bagaimana pengalaman Anda? Tiket #%s", ticket.KodeTiket), htmlBody); err != nil {
+		log.Printf("[csat] failed to send survey for ticket #%s to %s: %v", ticket.KodeTiket, ticket.User.Email, err)
+	}
+}
+
+// createCSATSurvey stores a survey for the resolved ticket with its current assignee and
+// returns the signed survey link
+func (s *appService) createCSATSurvey(ticket *models.Ticket) (string, time.Time, error) {
+	secret := os.Getenv("CSAT_SURVEY_SECRET")
+	if secret == "" {
+		return "", time.Time{}, errors.New("CSAT_SURVEY_SECRET is not configured")
+	}
+
+	now := time.Now()
+	survey := &models.CSATSurvey{
+		TicketID:   ticket.ID,
+		UserID:     ticket.UserID,
+		CategoryID: ticket.CategoryID,
+		SentAt:     now,
+		ExpiresAt:  now.Add(csatSurveyExpiry()),
+	}
+	if assignment, err := s.repo.GetTicketAssignmentByTicketID(ticket.ID); err == nil {
+		assigneeID := uint64(assignment.AdminID)
+		survey.AssigneeID = &assigneeID
+	}
+	if err := s.repo.CreateCSATSurvey(survey); err != nil {
+		return "", time.Time{}, err
+	}
+
+	token, err := jwt.NewWithClaims(jwt.SigningMethodHS256, jwt.RegisteredClaims{
+		Subject:   strconv.Itoa(survey.ID),
+		Audience:  jwt.ClaimStrings{csatTokenAudience},
+		IssuedAt:  jwt.NewNumericDate(now),
+		ExpiresAt: jwt.NewNumericDate(survey.ExpiresAt),
+	}).SignedString([]byte(secret))
+	if err != nil {
+		return "", time.Time{}, err
+	}
+
+	if err := s.recordTicketLog(ticket.ID, models.SystemUser, models.TicketLogCSATSent, fmt.Sprintf("CSAT survey sent to %s, expires on %s", ticket.User.Email, survey.ExpiresAt.Format("2006-01-02T15:04:05Z07:00"))); err != nil {
+		return "", time.Time{}, err
+	}
+	return csatSurveyBaseURL() + "?token=" + url.QueryEscape(token), survey.ExpiresAt, nil
+}
+
+// parseCSATSurveyToken verifies a survey token and returns the survey it was issued for
+func parseCSATSurveyToken(token string) (int, error) {
+	secret := os.Getenv("CSAT_SURVEY_SECRET")
+	if secret == "" || token == "" {
+		return 0, domain.ErrInvalidSurveyToken
+	}
+
+	claims := &jwt.RegisteredClaims{}
+	_, err := jwt.ParseWithClaims(token, claims, func(*jwt.Token) (interface{}, error) {
+		return []byte(secret), nil
+	}, jwt.WithValidMethods([]string{jwt.SigningMethodHS256.Alg()}), jwt.WithAudience(csatTokenAudience))
+	if errors.Is(err, jwt.ErrTokenExpired) {
+		return 0, domain.ErrSurveyExpired
+	}
+	if err != nil {
+		return 0, domain.ErrInvalidSurveyToken
+	}
+
+	surveyID, err := strconv.Atoi(claims.Subject)
+	if err != nil || surveyID <= 0 {
+		return 0, domain.ErrInvalidSurveyToken
+	}
+	return surveyID, nil
+}
+
+// csatRatingLinks builds one link per rating from the survey link, none without a survey
+func csatRatingLinks(surveyURL string) []CSATRatingLink {
+	if surveyURL == "" {
+		return nil
+	}
+	links := make([]CSATRatingLink, 0, models.CSATMaxRating)
+	for rating := models.CSATMinRating; rating <= models.CSATMaxRating; rating++ {
+		links = append(links, CSATRatingLink{Rating: rating, URL: fmt.Sprintf("%s&rating=%d", surveyURL, rating)})
+	}
+	return links
+}
+
+// csatSurveyExpiry is how long a survey link stays valid, CSAT_SURVEY_EXPIRY_DAYS defaults to 7
+func csatSurveyExpiry() time.Duration {
+	days, err := strconv.Atoi(os.Getenv("CSAT_SURVEY_EXPIRY_DAYS"))
+	if err != nil || days <= 0 {
+		days = 7
+	}
+	return time.Duration(days) * 24 * time.Hour
+}
+
+// csatSurveyBaseURL is the page customers rate the support on, it receives the token and rating
+func csatSurveyBaseURL() string {
+	if surveyURL := os.Getenv("CSAT_SURVEY_URL"); surveyURL != "" {
+		return surveyURL
+	}
+	return "https://helpdesk.magangslab.store/csat"
+}
+
+const csatSurveyEmailTemplate = `<!DOCTYPE html>
+<html lang="id">
+<head>
+    <meta charset="UTF-8" />
+    <meta name="viewport" content="width=device-width, initial-scale=1.0" />
+    <title>Bagaimana Pengalaman Anda?</title>
+</head>
+
+<body style="margin:0; padding:0; background-color:#f3f4f6; font-family:Arial,Helvetica,sans-serif;">
+
+    <center style="width:100%; padding:20px 0; background-color:#f3f4f6;">
+
+        <table width="600" style="width:600px; max-width:600px; background:#ffffff; border-radius:6px; border-collapse:collapse;">
+
+            <!-- HEADER -->
+            <tr>
+                <td style="background:#f59e0b; padding:30px; text-align:center; color:#ffffff;">
+                    <h1 style="margin:0; font-size:24px; font-weight:bold;">Bagaimana Pengalaman Anda?</h1>
+                    <p style="margin:8px 0 0; font-size:14px;">Tiket Anda telah diselesaikan</p>
+                </td>
+            </tr>
+
+            <!-- BODY -->
+            <tr>
+                <td style="padding:30px; font-size:14px; color:#374151;">
+
+                    <p style="margin:0 0 15px;">
+                        Halo <strong>{{.UserName}}</strong>,
+                    </p>
+
+                    <p style="margin:0 0 20px; line-height:1.6;">
+                        Tiket <strong>#{{.TicketId}}</strong> ({{.TicketTitle}}) telah diselesaikan oleh tim support kami. Seberapa puas Anda dengan bantuan yang Anda terima?
+                    </p>
+
+                    <!-- CSAT SURVEY -->
+                    <div style="text-align:center; margin:30px 0;">
+                        {{range .SurveyLinks}}
+                        <a href="{{.URL}}"
+                            style="background:#f9fafb; border:1px solid #e5e7eb; padding:10px 16px; margin:0 4px; display:inline-block; text-decoration:none; color:#111827; font-weight:bold; border-radius:4px;">
+                            {{.Rating}}
+                        </a>
+                        {{end}}
+                        <p style="margin:8px 0 0; font-size:12px; color:#6b7280;">1 = sangat tidak puas, 5 = sangat puas</p>
+                    </div>
+
+                    <div style="margin-top:25px; background:#fef3c7; border:1px solid #fde68a; padding:15px; color:#92400e; border-radius:4px;">
+                        <strong>Catatan:</strong> Tautan ini berlaku sampai {{.ExpiresAt}}.
+                    </div>
+
+                </td>
+            </tr>
+
+            <!-- FOOTER -->
+            <tr>
+                <td style="padding:25px 30px; text-align:center; font-size:12px; color:#6b7280;">
+                    <strong>SecondCycle Help Center</strong>
+                    <br><br>
+                    <span style="color:#9ca3af;">
+                        © {{.CurrentYear}} SecondCycle. Email ini dikirim karena Anda membuat tiket support.
+                    </span>
+                </td>
+            </tr>
+
+        </table>
+
+    </center>
+
+</body>
+</html>`
@@ -0,0 +1,201 @@
+package services
+
+import (
+	"app/domain"
+	"app/domain/models"
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+)
+
+func (s *appService) CreateCustomFieldDefinition(definition *models.CustomFieldDefinition) error {
+	if err := s.validateCustomFieldDefinition(definition); err != nil {
+		return err
+	}
+	return s.repo.CreateCustomFieldDefinition(definition)
+}
+
+func (s *appService) GetCustomFieldDefinitions(categoryID int) ([]models.CustomFieldDefinition, error) {
+	return s.repo.GetCustomFieldDefinitions(categoryID)
+}
+
+func (s *appService) GetCustomFieldDefinitionByID(id int) (*models.CustomFieldDefinition, error) {
+	return s.repo.GetCustomFieldDefinitionByID(id)
+}
+
+func (s *appService) UpdateCustomFieldDefinition(definition *models.CustomFieldDefinition) error {
+	current, err := s.repo.GetCustomFieldDefinitionByID(definition.ID)
+	if err != nil || current.CategoryID != definition.CategoryID {
+		return errors.New("custom field not found in this category")
+	}
+	if err := s.validateCustomFieldDefinition(definition); err != nil {
+		return err
+	}
+	return s.repo.UpdateCustomFieldDefinition(definition)
+}
+
+func (s *appService) DeleteCustomFieldDefinition(categoryID, id int) error {
+	current, err := s.repo.GetCustomFieldDefinitionByID(id)
+	if err != nil || current.CategoryID != categoryID {
+		return errors.New("custom field not found in this category")
+	}
+	return s.repo.DeleteCustomFieldDefinition(id)
+}
+
+func (s *appService) validateCustomFieldDefinition(definition *models.CustomFieldDefinition) error {
+	if _, err := s.repo.GetTicketCategoryByID(definition.CategoryID); err != nil {
+		return fmt.Errorf("category not found: %v", err)
+	}
+	if !models.CustomFieldKeyPattern.MatchString(definition.Key) {
+		return errors.New("key must start with a lowercase letter and contain only lowercase letters, digits and underscores")
+	}
+	if definition.Label == "" {
+		definition.Label = definition.Key
+	}
+
+	switch definition.FieldType {
+	case models.CustomFieldText:
+		if definition.Regex != "" {
+			if _, err := regexp.Compile(definition.Regex); err != nil {
+				return fmt.Errorf("invalid regex: %v", err)
+			}
+		}
+	case models.CustomFieldSelect:
+		if len(definition.Options) == 0 {
+			return errors.New("a select field needs at least one option")
+		}
+	case models.CustomFieldNumber, models.CustomFieldBoolean, models.CustomFieldDate:
+	default:
+		return fmt.Errorf("invalid field_type %q, use text, number, boolean, date or select", definition.FieldType)
+	}
+	return nil
+}
+
+// customFieldValuesChanged tells whether values differ from the stored values, compared in
+// their JSON form. No values and an empty set are the same.
+func customFieldValuesChanged(stored, values models.CustomFieldValues) bool {
+	if len(stored) == 0 && len(values) == 0 {
+		return false
+	}
+	storedJSON, storedErr := json.Marshal(stored)
+	valuesJSON, valuesErr := json.Marshal(values)
+	return storedErr != nil || valuesErr != nil || !bytes.Equal(storedJSON, valuesJSON)
+}
+
+// validateTicketCustomFields checks the custom field values of a ticket against the
+// definitions of its category and returns them normalized to their field types
+func (s *appService) validateTicketCustomFields(categoryID int, values models.CustomFieldValues) (models.CustomFieldValues, error) {
+	definitions, err := s.repo.GetCustomFieldDefinitions(categoryID)
+	if err != nil {
+		return nil, err
+	}
+
+	validation := &domain.ValidationError{}
+	defined := make(map[string]bool, len(definitions))
+	normalized := make(models.CustomFieldValues)
+	for _, definition := range definitions {
+		defined[definition.Key] = true
+		field := "custom_fields." + definition.Key
+
+		value, present := values[definition.Key]
+		if str, ok := value.(string); ok && strings.TrimSpace(str) == "" {
+			present = false
+		}
+		if !present || value == nil {
+			if definition.Required {
+				validation.Add(field, fmt.Sprintf("%s is required", definition.Label))
+			}
+			continue
+		}
+
+		converted, err := convertCustomFieldValue(definition, value)
+		if err != nil {
+			validation.Add(field, fmt.Sprintf("%s %v", definition.Label, err))
+			continue
+		}
+		normalized[definition.Key] = converted
+	}
+
+	for key := range values {
+		if !defined[key] {
+			validation.Add("custom_fields."+key, "unknown field for this category")
+		}
+	}
+
+	if err := validation.OrNil(); err != nil {
+		return nil, err
+	}
+	if len(normalized) == 0 {
+		return nil, nil
+	}
+	return normalized, nil
+}
+
+func convertCustomFieldValue(definition models.CustomFieldDefinition, value interface{}) (interface{}, error) {
+	switch definition.FieldType {
+	case models.CustomFieldNumber:
+		switch v := value.(type) {
+		case float64:
+			return v, nil
+		case string:
+			number, err := strconv.ParseFloat(strings.TrimSpace(v), 64)
+			if err != nil {
+				return nil, errors.New("must be a number")
+			}
+			return number, nil
+		}
+		return nil, errors.New("must be a number")
+
+	case models.CustomFieldBoolean:
+		switch v := value.(type) {
+		case bool:
+			return v, nil
+		case string:
+			b, err := strconv.ParseBool(strings.TrimSpace(v))
+			if err != nil {
+				return nil, errors.New("must be true or false")
+			}
+			return b, nil
+		}
+		return nil, errors.New("must be true or false")
+
+	case models.CustomFieldDate:
+		str, ok := value.(string)
+		if !ok {
+			return nil, errors.New("must be a date in YYYY-MM-DD format")
+		}
+		if _, err := time.Parse("2006-01-02", strings.TrimSpace(str)); err != nil {
+			return nil, errors.New("must be a date in YYYY-MM-DD format")
+		}
+		return strings.TrimSpace(str), nil
+
+	case models.CustomFieldSelect:
+		str, ok := value.(string)
+		if ok {
+			for _, option := range definition.Options {
+				if option == str {
+					return str, nil
+				}
+			}
+		}
+		return nil, fmt.Errorf("must be one of %s", strings.Join(definition.Options, ", "))
+
+	default:
+		str, ok := value.(string)
+		if !ok {
+			return nil, errors.New("must be text")
+		}
+		if definition.Regex != "" {
+			pattern, err := regexp.Compile(definition.Regex)
+			if err == nil && !pattern.MatchString(str) {
+				return nil, errors.New("has an invalid format")
+			}
+		}
+		return str, nil
+	}
+}
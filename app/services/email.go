@@ -17,20 +17,12 @@ type TicketCommentEmailData struct {
     TicketTitle  string
     Date         string
     Resolution   string
+    SurveyLinks  []CSATRatingLink
     CurrentYear  int
 }
 
-func (s *appService) SendTicketCommentEmail(toEmail, userName, ticketId, ticketTitle, resolution string) error {
-    domain := os.Getenv("MAILGUN_DOMAIN")
-    apiKey := os.Getenv("MAILGUN_API_KEY")
-    fromEmail := os.Getenv("MAILGUN_FROM_EMAIL")
-
-    if domain == "" || apiKey == "" || fromEmail == "" {
-        return fmt.Errorf("mailgun configuration missing")
-    }
-
-    mg := mailgun.NewMailgun(domain, apiKey)
-
+// SendTicketCommentEmail sends the resolution email, a non-empty surveyURL adds the CSAT rating links
+func (s *appService) SendTicketCommentEmail(toEmail, userName, ticketId, ticketTitle, resolution, surveyURL string) error {
     // Prepare template data
     data := TicketCommentEmailData{
         UserName:    userName,
@@ -38,6 +30,7 @@ func (s *appService) SendTicketCommentEmail(toEmail, userName, ticketId, ticketT
         TicketTitle: ticketTitle,
         Date:        time.Now().Format("02 January 2006, 15:04"),
         Resolution:  resolution,
+        SurveyLinks: csatRatingLinks(surveyURL),
         CurrentYear: time.Now().Year(),
     }
 
@@ -47,10 +40,25 @@ func (s *appService) SendTicketCommentEmail(toEmail, userName, ticketId, ticketT
         return fmt.Errorf("failed to render email template: %v", err)
     }
 
+    return s.sendEmail(toEmail, "Tiket Anda Diselesaikan ✔", htmlBody)
+}
+
+// sendEmail sends an HTML email through Mailgun
+func (s *appService) sendEmail(toEmail, subject, htmlBody string) error {
+    domain := os.Getenv("MAILGUN_DOMAIN")
+    apiKey := os.Getenv("MAILGUN_API_KEY")
+    fromEmail := os.Getenv("MAILGUN_FROM_EMAIL")
+
+    if domain == "" || apiKey == "" || fromEmail == "" {
+        return fmt.Errorf("mailgun configuration missing")
+    }
+
+    mg := mailgun.NewMailgun(domain, apiKey)
+
     // Create message
     message := mg.NewMessage(
         fromEmail,
-        "Tiket Anda Diselesaikan ✔",
+        subject,
         "", // Plain text version (optional)
         toEmail,
     )
@@ -60,7 +68,7 @@ func (s *appService) SendTicketCommentEmail(toEmail, userName, ticketId, ticketT
     ctx, cancel := context.WithTimeout(context.Background(), time.Second*30)
     defer cancel()
 
-    _, _, err = mg.Send(ctx, message)
+    _, _, err := mg.Send(ctx, message)
     if err != nil {
         return fmt.Errorf("failed to send email: %v", err)
     }
@@ -144,6 +152,23 @@ func (s *appService) renderTicketCommentEmailTemplate(data TicketCommentEmailDat
 
                     <div style="background:#ecfdf5; border:1px solid #d1fae5; padding:15px; border-radius:4px; color:#065f46; line-height:1.6; white-space:pre-wrap;">{{.Resolution}}</div>
 
+                    {{if .SurveyLinks}}
+                    <!-- CSAT SURVEY -->
+                    <h3 style="margin:30px 0 12px; font-size:16px; color:#111827;">
+                        Seberapa puas Anda dengan bantuan kami?
+                    </h3>
+
+                    <div style="text-align:center;">
+                        {{range .SurveyLinks}}
+                        <a href="{{.URL}}"
+                            style="background:#f9fafb; border:1px solid #e5e7eb; padding:10px 16px; margin:0 4px; display:inline-block; text-decoration:none; color:#111827; font-weight:bold; border-radius:4px;">
+                            {{.Rating}}
+                        </a>
+                        {{end}}
+                        <p style="margin:8px 0 0; font-size:12px; color:#6b7280;">1 = sangat tidak puas, 5 = sangat puas</p>
+                    </div>
+                    {{end}}
+
                     <!-- MESSAGE BOX -->
                     <div style="margin-top:25px; background:#fef3c7; border:1px solid #fde68a; padding:15px; color:#92400e; border-radius:4px;">
                         <strong>Catatan:</strong> Jika Anda membutuhkan bantuan tambahan, jangan ragu untuk membuka tiket baru.
@@ -0,0 +1,203 @@
+package services
+
+import (
+	"app/domain"
+	"app/domain/models"
+	"errors"
+	"fmt"
+	"log"
+	"strconv"
+	"strings"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+func (s *appService) CreateEscalationRule(rule *models.EscalationRule) error {
+	if err := s.validateEscalationRule(rule); err != nil {
+		return err
+	}
+	return s.repo.CreateEscalationRule(rule)
+}
+
+func (s *appService) GetEscalationRules() ([]models.EscalationRule, error) {
+	return s.repo.GetEscalationRules()
+}
+
+func (s *appService) GetEscalationRuleByID(id int) (*models.EscalationRule, error) {
+	rule, err := s.repo.GetEscalationRuleByID(id)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, fmt.Errorf("%w: escalation rule %d", domain.ErrRecordNotFound, id)
+		}
+		return nil, err
+	}
+	return rule, nil
+}
+
+// UpdateEscalationRule replaces the rule. Tickets the rule already escalated are not
+// escalated again by it.
+func (s *appService) UpdateEscalationRule(rule *models.EscalationRule) error {
+	current, err := s.GetEscalationRuleByID(rule.ID)
+	if err != nil {
+		return err
+	}
+	if err := s.validateEscalationRule(rule); err != nil {
+		return err
+	}
+	rule.CreatedAt = current.CreatedAt
+	return s.repo.UpdateEscalationRule(rule)
+}
+
+func (s *appService) DeleteEscalationRule(id int) error {
+	if _, err := s.GetEscalationRuleByID(id); err != nil {
+		return err
+	}
+	return s.repo.DeleteEscalationRule(id)
+}
+
+func (s *appService) validateEscalationRule(rule *models.EscalationRule) error {
+	var validation domain.ValidationError
+	rule.NamaRule = strings.TrimSpace(rule.NamaRule)
+	if rule.NamaRule == "" {
+		validation.Add("nama_rule", "is required")
+	}
+	if rule.NoCommentHours <= 0 {
+		validation.Add("no_comment_hours", "must be greater than 0")
+	}
+	if rule.RaisePriorityID == nil && rule.ReassignToID == nil && !rule.Notify {
+		validation.Add("notify", "a rule has to raise the priority, reassign or notify")
+	}
+
+	if rule.StatusID != nil {
+		if _, err := s.repo.GetTicketStatusByID(*rule.StatusID); err != nil {
+			validation.Add("status_id", "status not found")
+		}
+	}
+	if rule.PriorityID != nil {
+		if _, err := s.repo.GetTicketPriorityByID(*rule.PriorityID); err != nil {
+			validation.Add("priority_id", "priority not found")
+		}
+	}
+	if rule.CategoryID != nil {
+		if _, err := s.repo.GetTicketCategoryByID(*rule.CategoryID); err != nil {
+			validation.Add("category_id", "category not found")
+		}
+	}
+	if rule.RaisePriorityID != nil {
+		if _, err := s.repo.GetTicketPriorityByID(*rule.RaisePriorityID); err != nil {
+			validation.Add("raise_priority_id", "priority not found")
+		}
+	}
+	if rule.ReassignToID != nil {
+		user, err := s.repo.GetUserByID(uint64(*rule.ReassignToID))
+		if err != nil {
+			validation.Add("reassign_to_id", "user not found")
+		} else if user.Role != models.RoleSupport {
+			validation.Add("reassign_to_id", "only users with support role can be assigned to tickets")
+		}
+	}
+
+	return validation.OrNil()
+}
+
+// EscalateTickets applies every active escalation rule to the tickets support left unanswered
+// for the hours of the rule, counted in the working hours of the default calendar. A rule escalates a ticket once, a ticket failing to escalate
+// is retried on the next run.
+func (s *appService) EscalateTickets() error {
+	rules, err := s.repo.GetActiveEscalationRules()
+	if err != nil {
+		return err
+	}
+
+	// Idle hours are working hours of the default calendar
+	clock := s.defaultBusinessClock()
+	now := time.Now()
+	for i := range rules {
+		rule := &rules[i]
+		idleSince := clock.Add(now, -time.Duration(rule.NoCommentHours)*time.Hour)
+		tickets, err := s.repo.GetTicketsToEscalate(rule, idleSince)
+		if err != nil {
+			log.Printf("[escalation] failed to load tickets for rule %q: %v", rule.NamaRule, err)
+			continue
+		}
+
+		for j := range tickets {
+			ticket := &tickets[j]
+			err := s.withTransaction(func(tx *appService) error {
+				return tx.escalateTicket(rule, ticket)
+			})
+			if err != nil {
+				log.Printf("[escalation] rule %q failed to escalate ticket #%s: %v", rule.NamaRule, ticket.KodeTiket, err)
+			}
+		}
+	}
+
+	return nil
+}
+
+// escalateTicket applies the actions of the rule to the ticket as the system. The escalation
+// is recorded first so a concurrent run leaves the ticket alone.
+func (s *appService) escalateTicket(rule *models.EscalationRule, ticket *models.Ticket) error {
+	recorded, err := s.repo.RecordTicketEscalation(ticket.ID, rule.ID, time.Now())
+	if err != nil || !recorded {
+		return err
+	}
+
+	var actions []string
+	if rule.RaisePriorityID != nil && ticket.PriorityID != *rule.RaisePriorityID {
+		raised := *ticket
+		raised.PriorityID = *rule.RaisePriorityID
+		if err := s.UpdateTicket(&raised, models.SystemUser); err != nil {
+			return err
+		}
+		actions = append(actions, fmt.Sprintf("priority raised to %s", rule.RaisePriority.NamaPriority))
+	}
+
+	if rule.ReassignToID != nil {
+		assignment, err := s.repo.GetTicketAssignmentByTicketID(ticket.ID)
+		switch {
+		case err == nil && assignment.AdminID == *rule.ReassignToID:
+			// Already with the right person
+		case err == nil:
+			assignment.AdminID = *rule.ReassignToID
+			assignment.Ticket, assignment.Admin, assignment.Priority = nil, nil, nil
+			if err := s.UpdateTicketAssignment(assignment, models.SystemUser); err != nil {
+				return err
+			}
+			actions = append(actions, fmt.Sprintf("reassigned to %s", rule.ReassignTo.Username))
+		case errors.Is(err, gorm.ErrRecordNotFound):
+			if err := s.CreateTicketAssignment(&models.TicketAssignment{
+				TicketID:          ticket.ID,
+				AdminID:           *rule.ReassignToID,
+				TanggalDitugaskan: time.Now(),
+			}, models.SystemUser); err != nil {
+				return err
+			}
+			actions = append(actions, fmt.Sprintf("assigned to %s", rule.ReassignTo.Username))
+		default:
+			return err
+		}
+	}
+
+	aktivitas := fmt.Sprintf("Escalated by rule %q after %d hours without a comment", rule.NamaRule, rule.NoCommentHours)
+	if len(actions) > 0 {
+		aktivitas += ": " + strings.Join(actions, ", ")
+	}
+	entry := newTicketLog(ticket.ID, models.SystemUser, models.TicketLogEscalated, aktivitas)
+	entry.Field = "id_escalation_rule"
+	entry.NewValue = strconv.Itoa(rule.ID)
+	if err := s.repo.CreateTicketLog(entry); err != nil {
+		return err
+	}
+
+	if rule.Notify {
+		escalated, err := s.repo.GetTicketByID(ticket.ID)
+		if err != nil {
+			return err
+		}
+		message := fmt.Sprintf("Tiket belum ditanggapi selama %d jam dan telah dieskalasi", rule.NoCommentHours)
+		s.notifyTicketWatchers(escalated, TicketNotificationEscalation, models.SystemUser, message, rule.ReassignTo)
+	}
+	return nil
+}
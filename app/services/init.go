@@ -10,6 +10,11 @@ type appService struct {
 	hub     *domain.Hub
 	timeout time.Duration
 	s3Repo  domain.S3Repository
+
+	// Set on the copies made by withTransaction: root is the service outside the
+	// transaction and afterCommit collects side effects to run once it commits
+	root        *appService
+	afterCommit *[]func()
 }
 
 type DBInjection struct {
@@ -25,3 +30,43 @@ func NewAppService(repoInjection DBInjection, hub *domain.Hub, timeout time.Dura
 		timeout: timeout,
 	}
 }
+
+// withTransaction runs fn with a copy of the service whose repository is bound to a
+// database transaction, so the service helpers can be reused inside it. Nested calls
+// run in a savepoint. Side effects queued with onCommit run after the outermost commit
+// and are dropped when their transaction or savepoint rolls back.
+func (s *appService) withTransaction(fn func(tx *appService) error) error {
+	var pending []func()
+	err := s.repo.WithTransaction(func(repo domain.AppRepository) error {
+		tx := *s
+		tx.repo = repo
+		tx.root = s.base()
+		tx.afterCommit = &pending
+		return fn(&tx)
+	})
+	if err != nil {
+		return err
+	}
+
+	for _, effect := range pending {
+		s.onCommit(effect)
+	}
+	return nil
+}
+
+// onCommit runs effect now, or after the commit when called inside a transaction
+func (s *appService) onCommit(effect func()) {
+	if s.afterCommit != nil {
+		*s.afterCommit = append(*s.afterCommit, effect)
+		return
+	}
+	effect()
+}
+
+// base returns the service outside of any transaction
+func (s *appService) base() *appService {
+	if s.root != nil {
+		return s.root
+	}
+	return s
+}
@@ -0,0 +1,160 @@
+package services
+
+import (
+	"app/domain"
+	"bytes"
+	"encoding/json"
+	"errors"
+	"reflect"
+	"sort"
+	"strings"
+)
+
+// fieldChange is a field altered by a merge patch, values are in their JSON form
+type fieldChange struct {
+	Field string
+	Old   string
+	New   string
+}
+
+// applyMergePatch applies a JSON merge patch (RFC 7386) to fields, a struct holding the
+// patchable fields of a record, and returns the fields whose value changed. Members
+// missing from the patch are kept, null resets a field and objects are merged.
+func applyMergePatch(fields interface{}, patch []byte) ([]fieldChange, error) {
+	var patchDoc map[string]interface{}
+	if err := json.Unmarshal(patch, &patchDoc); err != nil || patchDoc == nil {
+		return nil, &domain.ValidationError{Fields: map[string]string{"body": "must be a JSON object"}}
+	}
+
+	before, err := toJSONObject(fields)
+	if err != nil {
+		return nil, err
+	}
+
+	var validation domain.ValidationError
+	for key := range patchDoc {
+		if _, known := before[key]; !known {
+			validation.Add(key, "cannot be changed")
+		}
+	}
+	if err := validation.OrNil(); err != nil {
+		return nil, err
+	}
+
+	merged, err := json.Marshal(mergePatch(copyJSONObject(before), patchDoc))
+	if err != nil {
+		return nil, err
+	}
+
+	// Decode into a fresh value so reset fields do not keep their old contents
+	target := reflect.ValueOf(fields).Elem()
+	patched := reflect.New(target.Type())
+	decoder := json.NewDecoder(bytes.NewReader(merged))
+	decoder.DisallowUnknownFields()
+	if err := decoder.Decode(patched.Interface()); err != nil {
+		var typeErr *json.UnmarshalTypeError
+		if errors.As(err, &typeErr) {
+			validation.Add(typeErr.Field, "must be a "+typeErr.Type.String())
+			return nil, validation.OrNil()
+		}
+		validation.Add("body", err.Error())
+		return nil, validation.OrNil()
+	}
+	target.Set(patched.Elem())
+
+	after, err := toJSONObject(fields)
+	if err != nil {
+		return nil, err
+	}
+
+	return diffJSONObjects(before, after), nil
+}
+
+// diffFields compares two snapshots of the same fields struct and returns the fields whose
+// value differs, the way applyMergePatch reports them
+func diffFields(before, after interface{}) ([]fieldChange, error) {
+	beforeObject, err := toJSONObject(before)
+	if err != nil {
+		return nil, err
+	}
+	afterObject, err := toJSONObject(after)
+	if err != nil {
+		return nil, err
+	}
+	return diffJSONObjects(beforeObject, afterObject), nil
+}
+
+func diffJSONObjects(before, after map[string]interface{}) []fieldChange {
+	var changes []fieldChange
+	for key, oldValue := range before {
+		if newValue := after[key]; !reflect.DeepEqual(oldValue, newValue) {
+			changes = append(changes, fieldChange{Field: key, Old: jsonText(oldValue), New: jsonText(newValue)})
+		}
+	}
+	for key, newValue := range after {
+		if _, known := before[key]; !known {
+			changes = append(changes, fieldChange{Field: key, Old: jsonText(nil), New: jsonText(newValue)})
+		}
+	}
+	sort.Slice(changes, func(i, j int) bool { return changes[i].Field < changes[j].Field })
+	return changes
+}
+
+// mergePatch merges patch into target following RFC 7386
+func mergePatch(target interface{}, patch interface{}) interface{} {
+	patchObject, ok := patch.(map[string]interface{})
+	if !ok {
+		return patch
+	}
+
+	targetObject, ok := target.(map[string]interface{})
+	if !ok {
+		targetObject = map[string]interface{}{}
+	}
+	for key, value := range patchObject {
+		if value == nil {
+			delete(targetObject, key)
+			continue
+		}
+		targetObject[key] = mergePatch(targetObject[key], value)
+	}
+	return targetObject
+}
+
+func toJSONObject(value interface{}) (map[string]interface{}, error) {
+	raw, err := json.Marshal(value)
+	if err != nil {
+		return nil, err
+	}
+	var object map[string]interface{}
+	err = json.Unmarshal(raw, &object)
+	return object, err
+}
+
+func copyJSONObject(object map[string]interface{}) map[string]interface{} {
+	copied := make(map[string]interface{}, len(object))
+	for key, value := range object {
+		if nested, ok := value.(map[string]interface{}); ok {
+			value = copyJSONObject(nested)
+		}
+		copied[key] = value
+	}
+	return copied
+}
+
+func jsonText(value interface{}) string {
+	raw, err := json.Marshal(value)
+	if err != nil {
+		return ""
+	}
+	return strings.TrimSpace(string(raw))
+}
+
+// describeFieldChanges lists the changed fields for a log line, e.g. `judul: "a" -> "b"`
+func describeFieldChanges(changes []fieldChange) string {
+	parts := make([]string, 0, len(changes))
+	for _, change := range changes {
+		parts = append(parts, change.Field+": "+change.Old+" -> "+change.New)
+	}
+	return strings.Join(parts, ", ")
+}
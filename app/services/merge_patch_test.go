@@ -0,0 +1,168 @@
+package services
+
+import (
+	"app/domain"
+	"encoding/json"
+	"errors"
+	"reflect"
+	"testing"
+)
+
+// The examples of RFC 7386 appendix A
+func TestMergePatch(t *testing.T) {
+	tests := []struct {
+		target, patch, want string
+	}{
+		{`{"a":"b"}`, `{"a":"c"}`, `{"a":"c"}`},
+		{`{"a":"b"}`, `{"b":"c"}`, `{"a":"b","b":"c"}`},
+		{`{"a":"b"}`, `{"a":null}`, `{}`},
+		{`{"a":"b","b":"c"}`, `{"a":null}`, `{"b":"c"}`},
+		{`{"a":["b"]}`, `{"a":"c"}`, `{"a":"c"}`},
+		{`{"a":"c"}`, `{"a":["b"]}`, `{"a":["b"]}`},
+		{`{"a":{"b":"c"}}`, `{"a":{"b":"d","c":null}}`, `{"a":{"b":"d"}}`},
+		{`{"a":[{"b":"c"}]}`, `{"a":[1]}`, `{"a":[1]}`},
+		{`["a","b"]`, `["c","d"]`, `["c","d"]`},
+		{`{"a":"b"}`, `["c"]`, `["c"]`},
+		{`{"a":"foo"}`, `null`, `null`},
+		{`{"a":"foo"}`, `"bar"`, `"bar"`},
+		{`{"e":null}`, `{"a":1}`, `{"e":null,"a":1}`},
+		{`[1,2]`, `{"a":"b","c":null}`, `{"a":"b"}`},
+		{`{}`, `{"a":{"bb":{"ccc":null}}}`, `{"a":{"bb":{}}}`},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.target+" "+tt.patch, func(t *testing.T) {
+			decode := func(raw string) interface{} {
+				var value interface{}
+				if err := json.Unmarshal([]byte(raw), &value); err != nil {
+					t.Fatalf("invalid JSON %s: %v", raw, err)
+				}
+				return value
+			}
+			if got := mergePatch(decode(tt.target), decode(tt.patch)); !reflect.DeepEqual(got, decode(tt.want)) {
+				t.Errorf("mergePatch() = %s, want %s", jsonText(got), tt.want)
+			}
+		})
+	}
+}
+
+type patchTestFields struct {
+	Judul        string                 `json:"judul"`
+	Deskripsi    *string                `json:"deskripsi"`
+	CustomFields map[string]interface{} `json:"custom_fields"`
+}
+
+func TestApplyMergePatch(t *testing.T) {
+	deskripsi := "lama"
+	original := func() patchTestFields {
+		return patchTestFields{
+			Judul:        "judul lama",
+			Deskripsi:    &deskripsi,
+			CustomFields: map[string]interface{}{"warna": "merah", "ukuran": "L"},
+		}
+	}
+
+	tests := []struct {
+		name    string
+		patch   string
+		want    patchTestFields
+		changed []string // the changed fields in order
+		invalid string   // the field reported as invalid
+	}{
+		{
+			name:    "one member",
+			patch:   `{"judul":"judul baru"}`,
+			want:    patchTestFields{Judul: "judul baru", Deskripsi: &deskripsi, CustomFields: original().CustomFields},
+			changed: []string{"judul"},
+		},
+		{
+			name:  "unchanged value",
+			patch: `{"judul":"judul lama"}`,
+			want:  original(),
+		},
+		{
+			name:  "empty patch",
+			patch: `{}`,
+			want:  original(),
+		},
+		{
+			name:    "null resets a pointer",
+			patch:   `{"deskripsi":null}`,
+			want:    patchTestFields{Judul: "judul lama", CustomFields: original().CustomFields},
+			changed: []string{"deskripsi"},
+		},
+		{
+			name:    "null resets a value",
+			patch:   `{"judul":null}`,
+			want:    patchTestFields{Deskripsi: &deskripsi, CustomFields: original().CustomFields},
+			changed: []string{"judul"},
+		},
+		{
+			name:    "nested object is merged",
+			patch:   `{"custom_fields":{"warna":"biru","ukuran":null,"bahan":"katun"}}`,
+			want:    patchTestFields{Judul: "judul lama", Deskripsi: &deskripsi, CustomFields: map[string]interface{}{"warna": "biru", "bahan": "katun"}},
+			changed: []string{"custom_fields"},
+		},
+		{
+			name:    "null resets a nested object",
+			patch:   `{"custom_fields":null,"judul":"judul baru"}`,
+			want:    patchTestFields{Judul: "judul baru", Deskripsi: &deskripsi},
+			changed: []string{"custom_fields", "judul"},
+		},
+		{name: "unknown member", patch: `{"status":"open"}`, invalid: "status"},
+		{name: "wrong type", patch: `{"judul":1}`, invalid: "judul"},
+		{name: "array", patch: `["judul"]`, invalid: "body"},
+		{name: "null document", patch: `null`, invalid: "body"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			fields := original()
+			changes, err := applyMergePatch(&fields, []byte(tt.patch))
+
+			if tt.invalid != "" {
+				var validation *domain.ValidationError
+				if !errors.As(err, &validation) {
+					t.Fatalf("applyMergePatch() error = %v, want a validation error", err)
+				}
+				if _, ok := validation.Fields[tt.invalid]; !ok {
+					t.Errorf("validation fields = %v, want %s", validation.Fields, tt.invalid)
+				}
+				if !reflect.DeepEqual(fields, original()) {
+					t.Errorf("fields = %+v, want them unchanged", fields)
+				}
+				return
+			}
+
+			if err != nil {
+				t.Fatalf("applyMergePatch() error = %v", err)
+			}
+			if !reflect.DeepEqual(fields, tt.want) {
+				t.Errorf("fields = %+v, want %+v", fields, tt.want)
+			}
+			var changed []string
+			for _, change := range changes {
+				changed = append(changed, change.Field)
+			}
+			if !reflect.DeepEqual(changed, tt.changed) {
+				t.Errorf("changed fields = %v, want %v", changed, tt.changed)
+			}
+		})
+	}
+}
+
+func TestApplyMergePatchReportsValues(t *testing.T) {
+	fields := patchTestFields{Judul: "lama"}
+	changes, err := applyMergePatch(&fields, []byte(`{"judul":"baru","deskripsi":"isi"}`))
+	if err != nil {
+		t.Fatalf("applyMergePatch() error = %v", err)
+	}
+
+	want := []fieldChange{
+		{Field: "deskripsi", Old: "null", New: `"isi"`},
+		{Field: "judul", Old: `"lama"`, New: `"baru"`},
+	}
+	if !reflect.DeepEqual(changes, want) {
+		t.Errorf("changes = %+v, want %+v", changes, want)
+	}
+}
@@ -0,0 +1,172 @@
+package services
+
+import (
+	"app/domain"
+	"app/domain/models"
+	"errors"
+	"fmt"
+	"strings"
+
+	"gorm.io/gorm"
+)
+
+// CreateSavedTicketView saves a ticket list filter for the agent
+func (s *appService) CreateSavedTicketView(view *models.SavedTicketView, claim models.User) error {
+	view.ID = 0
+	view.UserID = claim.ID
+	view.User = nil
+	view.NamaView = strings.TrimSpace(view.NamaView)
+	if view.Sort == "" {
+		view.Sort = models.TicketSortNewest
+	}
+	if err := validateSavedTicketView(view); err != nil {
+		return err
+	}
+	return s.repo.CreateSavedTicketView(view)
+}
+
+// GetSavedTicketViews returns the views the agent saved or others shared, each with the
+// live count of the tickets it matches for the agent
+func (s *appService) GetSavedTicketViews(claim models.User) ([]domain.SavedTicketViewSummary, error) {
+	views, err := s.repo.GetSavedTicketViews(claim.ID)
+	if err != nil {
+		return nil, err
+	}
+
+	filters := make([]domain.TicketFilter, 0, len(views))
+	for i := range views {
+		filters = append(filters, savedTicketViewFilter(&views[i], claim))
+	}
+	counts, err := s.repo.CountTicketsPerFilter(filters)
+	if err != nil {
+		return nil, err
+	}
+
+	summaries := make([]domain.SavedTicketViewSummary, 0, len(views))
+	for i, view := range views {
+		summaries = append(summaries, domain.SavedTicketViewSummary{SavedTicketView: view, Count: counts[i]})
+	}
+	return summaries, nil
+}
+
+// DeleteSavedTicketView deletes a view of the agent, admins may delete any shared view
+func (s *appService) DeleteSavedTicketView(id int, claim models.User) error {
+	view, err := s.visibleSavedTicketView(id, claim)
+	if err != nil {
+		return err
+	}
+	if view.UserID != claim.ID && claim.Role != models.RoleAdmin {
+		return domain.ErrNotViewOwner
+	}
+	return s.repo.DeleteSavedTicketView(id)
+}
+
+// RunSavedTicketView lists a page of the tickets matching the view through GetTicketsCursor,
+// see savedTicketViewFilter for the tickets an agent gets
+func (s *appService) RunSavedTicketView(id int, limit int, cursor string, claim models.User) (*domain.SavedTicketViewSummary, []models.Ticket, string, error) {
+	view, err := s.visibleSavedTicketView(id, claim)
+	if err != nil {
+		return nil, nil, "", err
+	}
+
+	filter := savedTicketViewFilter(view, claim)
+	tickets, nextCursor, err := s.GetTicketsCursor(limit, cursor, filter)
+	if err != nil {
+		return nil, nil, "", err
+	}
+	count, err := s.repo.CountTickets(filter)
+	if err != nil {
+		return nil, nil, "", err
+	}
+
+	return &domain.SavedTicketViewSummary{SavedTicketView: *view, Count: count}, tickets, nextCursor, nil
+}
+
+// visibleSavedTicketView returns a view the agent saved or another agent shared, the
+// private views of others are not found
+func (s *appService) visibleSavedTicketView(id int, claim models.User) (*models.SavedTicketView, error) {
+	view, err := s.repo.GetSavedTicketViewByID(id)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, fmt.Errorf("%w: view %d", domain.ErrRecordNotFound, id)
+		}
+		return nil, err
+	}
+	if view.UserID != claim.ID && !view.IsShared {
+		return nil, fmt.Errorf("%w: view %d", domain.ErrRecordNotFound, id)
+	}
+	return view, nil
+}
+
+// savedTicketViewFilter turns the filter of a view into the ticket list filter of the
+// agent, which is limited to the tickets assigned to them unless they are an admin
+func savedTicketViewFilter(view *models.SavedTicketView, claim models.User) domain.TicketFilter {
+	filter := domain.TicketFilter{
+		KodeTiket:       view.Filter.KodeTiket,
+		TipePengaduan:   view.Filter.TipePengaduan,
+		StatusID:        view.Filter.StatusID,
+		PriorityID:      view.Filter.PriorityID,
+		CategoryID:      view.Filter.CategoryID,
+		MinReopenCount:  view.Filter.MinReopenCount,
+		IncludePending:  view.Filter.IncludePending,
+		SLAState:        view.Filter.SLA,
+		SLAAtRiskWindow: slaAtRiskWindow(),
+		Tags: domain.TagFilter{
+			TagIDs:   view.Filter.TagIDs,
+			MatchAll: view.Filter.TagsMode == "all",
+		},
+		CustomFields: view.Filter.CustomFields,
+		Sort:         view.Sort,
+	}
+	if claim.Role != models.RoleAdmin {
+		filter.AssigneeID = claim.ID
+	}
+	return filter
+}
+
+func validateSavedTicketView(view *models.SavedTicketView) error {
+	var validation domain.ValidationError
+	if view.NamaView == "" {
+		validation.Add("nama_view", "is required")
+	} else if len(view.NamaView) > 100 {
+		validation.Add("nama_view", "must be at most 100 characters")
+	}
+
+	switch view.Sort {
+	case models.TicketSortNewest, models.TicketSortOldest, models.TicketSortUpdated:
+	default:
+		validation.Add("sort", "must be newest, oldest or updated")
+	}
+
+	filter := view.Filter
+	switch models.UserRole(filter.TipePengaduan) {
+	case "", models.RoleAdmin, models.RoleSeller, models.RoleCustomer, models.RoleSupport:
+	default:
+		validation.Add("filter.role", "must be admin, seller, customer or support")
+	}
+	if filter.StatusID < 0 || filter.PriorityID < 0 || filter.CategoryID < 0 || filter.MinReopenCount < 0 {
+		validation.Add("filter", "IDs and min_reopen_count cannot be negative")
+	}
+	switch filter.SLA {
+	case "", models.SLAStateAtRisk, models.SLAStateBreached:
+	default:
+		validation.Add("filter.sla", "must be at_risk or breached")
+	}
+	for _, tagID := range filter.TagIDs {
+		if tagID <= 0 {
+			validation.Add("filter.tags", "must be a list of tag IDs")
+		}
+	}
+	switch filter.TagsMode {
+	case "", "any", "all":
+	default:
+		validation.Add("filter.tags_mode", "must be any or all")
+	}
+	for key := range filter.CustomFields {
+		if !models.CustomFieldKeyPattern.MatchString(key) {
+			validation.Add("filter.custom_fields", fmt.Sprintf("invalid custom field key %q", key))
+		}
+	}
+
+	return validation.OrNil()
+}
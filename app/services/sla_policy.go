@@ -0,0 +1,256 @@
+package services
+
+import (
+	"app/domain/models"
+	"fmt"
+	"log"
+	"os"
+	"strconv"
+	"time"
+)
+
+func (s *appService) CreateSLAPolicy(policy *models.SLAPolicy) error {
+	if err := validateSLAPolicy(policy); err != nil {
+		return err
+	}
+	return s.repo.CreateSLAPolicy(policy)
+}
+
+func (s *appService) GetSLAPolicies() ([]models.SLAPolicy, error) {
+	return s.repo.GetSLAPolicies()
+}
+
+func (s *appService) GetSLAPolicyByID(id int) (*models.SLAPolicy, error) {
+	return s.repo.GetSLAPolicyByID(id)
+}
+
+func (s *appService) UpdateSLAPolicy(policy *models.SLAPolicy) error {
+	if err := validateSLAPolicy(policy); err != nil {
+		return err
+	}
+	return s.repo.UpdateSLAPolicy(policy)
+}
+
+func (s *appService) DeleteSLAPolicy(id int) error {
+	return s.repo.DeleteSLAPolicy(id)
+}
+
+func validateSLAPolicy(policy *models.SLAPolicy) error {
+	if policy.NamaPolicy == "" {
+		return fmt.Errorf("nama_policy is required")
+	}
+	if policy.FirstResponseMinutes < 0 || policy.NextResponseMinutes < 0 || policy.ResolutionMinutes < 0 {
+		return fmt.Errorf("SLA targets cannot be negative")
+	}
+	return nil
+}
+
+// CheckSLABreaches records every SLA target that passed its due timestamp in the ticket log
+func (s *appService) CheckSLABreaches() error {
+	now := time.Now()
+	tickets, err := s.repo.GetTicketsWithUnrecordedSLABreach(now)
+	if err != nil {
+		return err
+	}
+
+	for i := range tickets {
+		ticket := &tickets[i]
+		for target, due := range breachedSLATargets(ticket, now) {
+			recorded, err := s.repo.MarkTicketSLABreached(ticket.ID, target, now)
+			if err != nil {
+				log.Printf("[sla] failed to mark %s breach for ticket #%s: %v", target, ticket.KodeTiket, err)
+				continue
+			}
+			if recorded {
+				s.recordTicketLog(ticket.ID, models.SystemUser, models.TicketLogSLABreached, fmt.Sprintf("SLA %s breached, was due %s", target, due.Format("2006-01-02T15:04:05Z07:00")))
+			}
+		}
+	}
+
+	return nil
+}
+
+// matchSLAPolicy returns the most specific active policy matching the ticket, or nil
+func (s *appService) matchSLAPolicy(ticket *models.Ticket) (*models.SLAPolicy, error) {
+	policies, err := s.repo.GetActiveSLAPolicies()
+	if err != nil {
+		return nil, err
+	}
+
+	var best *models.SLAPolicy
+	bestScore := -1
+	for i := range policies {
+		policy := &policies[i]
+		score := 0
+		if policy.PriorityID != nil {
+			if *policy.PriorityID != ticket.PriorityID {
+				continue
+			}
+			score++
+		}
+		if policy.CategoryID != nil {
+			if *policy.CategoryID != ticket.CategoryID {
+				continue
+			}
+			score++
+		}
+		if policy.TipePengaduan != nil {
+			if *policy.TipePengaduan != ticket.TipePengaduan {
+				continue
+			}
+			score++
+		}
+		if score > bestScore {
+			best = policy
+			bestScore = score
+		}
+	}
+
+	return best, nil
+}
+
+// slaClock returns the business clock SLA targets of the policy are measured in
+func (s *appService) slaClock(policy *models.SLAPolicy) *businessClock {
+	if policy == nil {
+		return s.defaultBusinessClock()
+	}
+	return s.calendarClock(policy.CalendarID)
+}
+
+func minutes(value int) time.Duration {
+	return time.Duration(value) * time.Minute
+}
+
+// refreshTicketSLA re-matches the SLA policy and recomputes the due timestamps. transition is
+// the status change that just happened, or nil when only other fields changed.
+func (s *appService) refreshTicketSLA(ticket *models.Ticket, transition *models.TicketStatusTransition, now time.Time) {
+	policy, err := s.matchSLAPolicy(ticket)
+	if err != nil {
+		log.Printf("[sla] failed to match policy for ticket #%s: %v", ticket.KodeTiket, err)
+		return
+	}
+
+	clock := s.slaClock(policy)
+	if !sameSLAPolicy(ticket.SLAPolicyID, policy) {
+		retargetTicketSLA(ticket, policy, clock)
+	}
+
+	if transition == nil {
+		return
+	}
+
+	fromPauses := transition.FromStatus != nil && transition.FromStatus.PausesSLA
+	toPauses := transition.ToStatus != nil && transition.ToStatus.PausesSLA
+
+	switch {
+	case toPauses && ticket.SLAPausedAt == nil:
+		ticket.SLAPausedAt = &now
+		ticket.NextResponseDueAt = nil
+		ticket.NextResponseBreachedAt = nil
+	case fromPauses && !toPauses && ticket.SLAPausedAt != nil:
+		// The clocks continue with the working time that was left when they stopped
+		pausedAt := *ticket.SLAPausedAt
+		resume := func(due *time.Time) *time.Time {
+			if due == nil || !due.After(pausedAt) {
+				return due
+			}
+			resumed := clock.Add(now, clock.Between(pausedAt, *due))
+			return &resumed
+		}
+		if ticket.FirstRespondedAt == nil {
+			ticket.FirstResponseDueAt = resume(ticket.FirstResponseDueAt)
+		}
+		ticket.ResolutionDueAt = resume(ticket.ResolutionDueAt)
+		ticket.SLAPausedAt = nil
+	}
+
+	// Every status change after the first response expects another response
+	if !toPauses && ticket.FirstRespondedAt != nil && policy != nil && policy.NextResponseMinutes > 0 {
+		due := clock.Add(now, minutes(policy.NextResponseMinutes))
+		ticket.NextResponseDueAt = &due
+		ticket.NextResponseBreachedAt = nil
+	}
+}
+
+// retargetTicketSLA computes the due timestamps of a newly matched policy from the ticket creation
+func retargetTicketSLA(ticket *models.Ticket, policy *models.SLAPolicy, clock *businessClock) {
+	ticket.SLAPolicyID = nil
+	ticket.FirstResponseDueAt = nil
+	ticket.NextResponseDueAt = nil
+	ticket.ResolutionDueAt = nil
+	if policy == nil {
+		return
+	}
+
+	createdAt := ticket.TanggalDibuat
+	if createdAt.IsZero() {
+		createdAt = time.Now()
+	}
+
+	ticket.SLAPolicyID = &policy.ID
+	if policy.FirstResponseMinutes > 0 {
+		due := clock.Add(createdAt, minutes(policy.FirstResponseMinutes))
+		ticket.FirstResponseDueAt = &due
+	}
+	if policy.ResolutionMinutes > 0 {
+		due := clock.Add(createdAt, minutes(policy.ResolutionMinutes))
+		ticket.ResolutionDueAt = &due
+	}
+}
+
+// markTicketResponded stops the response clocks when support replies to a ticket
+func markTicketResponded(ticket *models.Ticket, now time.Time) {
+	if ticket.FirstRespondedAt == nil {
+		ticket.FirstRespondedAt = &now
+	}
+	ticket.NextResponseDueAt = nil
+	ticket.NextResponseBreachedAt = nil
+}
+
+// evaluateTicketSLA fills the computed at risk and breached flags of a ticket
+func (s *appService) evaluateTicketSLA(ticket *models.Ticket, now time.Time) {
+	ticket.SLABreached = ticket.FirstResponseBreachedAt != nil ||
+		ticket.NextResponseBreachedAt != nil ||
+		ticket.ResolutionBreachedAt != nil ||
+		len(breachedSLATargets(ticket, now)) > 0
+	if ticket.SLABreached {
+		ticket.SLAAtRisk = false
+		return
+	}
+
+	ticket.SLAAtRisk = len(breachedSLATargets(ticket, now.Add(slaAtRiskWindow()))) > 0
+}
+
+// breachedSLATargets returns the running SLA clocks whose due timestamp is before now
+func breachedSLATargets(ticket *models.Ticket, now time.Time) map[models.SLATarget]time.Time {
+	breached := make(map[models.SLATarget]time.Time)
+	if ticket.SLAPausedAt != nil {
+		return breached
+	}
+	if ticket.FirstRespondedAt == nil && ticket.FirstResponseDueAt != nil && ticket.FirstResponseDueAt.Before(now) {
+		breached[models.SLATargetFirstResponse] = *ticket.FirstResponseDueAt
+	}
+	if ticket.NextResponseDueAt != nil && ticket.NextResponseDueAt.Before(now) {
+		breached[models.SLATargetNextResponse] = *ticket.NextResponseDueAt
+	}
+	if ticket.ResolutionDueAt != nil && ticket.ResolutionDueAt.Before(now) {
+		breached[models.SLATargetResolution] = *ticket.ResolutionDueAt
+	}
+	return breached
+}
+
+func sameSLAPolicy(current *int, policy *models.SLAPolicy) bool {
+	if current == nil || policy == nil {
+		return current == nil && policy == nil
+	}
+	return *current == policy.ID
+}
+
+// slaAtRiskWindow is how close to a due timestamp a ticket is flagged as at risk
+func slaAtRiskWindow() time.Duration {
+	minutes, err := strconv.Atoi(os.Getenv("SLA_AT_RISK_MINUTES"))
+	if err != nil || minutes <= 0 {
+		minutes = 60
+	}
+	return time.Duration(minutes) * time.Minute
+}
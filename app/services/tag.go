@@ -0,0 +1,101 @@
+package services
+
+import (
+	"app/domain"
+	"app/domain/models"
+	"errors"
+	"fmt"
+	"strings"
+
+	"gorm.io/gorm"
+)
+
+func (s *appService) CreateTag(tag *models.Tag) error {
+	if err := validateTag(tag); err != nil {
+		return err
+	}
+	return s.repo.CreateTag(tag)
+}
+
+func (s *appService) GetTags() ([]models.Tag, error) {
+	return s.repo.GetTags()
+}
+
+func (s *appService) GetTagByID(id int) (*models.Tag, error) {
+	return s.repo.GetTagByID(id)
+}
+
+func (s *appService) UpdateTag(tag *models.Tag) error {
+	if err := validateTag(tag); err != nil {
+		return err
+	}
+	return s.repo.UpdateTag(tag)
+}
+
+func validateTag(tag *models.Tag) error {
+	var validation domain.ValidationError
+	tag.NamaTag = strings.TrimSpace(tag.NamaTag)
+	if tag.NamaTag == "" {
+		validation.Add("nama_tag", "is required")
+	} else if len(tag.NamaTag) > 50 {
+		validation.Add("nama_tag", "must be at most 50 characters")
+	}
+	return validation.OrNil()
+}
+
+func (s *appService) DeleteTag(id int) error {
+	return s.repo.DeleteTag(id)
+}
+
+func (s *appService) AddTicketTag(ticketID, tagID int, claim models.User) (*models.Tag, error) {
+	if _, err := s.repo.GetTicketByID(ticketID); err != nil {
+		return nil, fmt.Errorf("%w: ticket %d", domain.ErrTicketNotFound, ticketID)
+	}
+	tag, err := s.repo.GetTagByID(tagID)
+	if err != nil {
+		return nil, fmt.Errorf("%w: tag %d", domain.ErrTagNotFound, tagID)
+	}
+
+	if err := s.repo.AddTicketTag(ticketID, tag); err != nil {
+		return nil, err
+	}
+
+	if err := s.recordTicketLog(ticketID, claim, models.TicketLogTagAdded, fmt.Sprintf("Added tag %s", tag.NamaTag)); err != nil {
+		return nil, err
+	}
+	return tag, nil
+}
+
+func (s *appService) RemoveTicketTag(ticketID, tagID int, claim models.User) error {
+	tag, err := s.repo.GetTagByID(tagID)
+	if err != nil {
+		return fmt.Errorf("%w: tag %d", domain.ErrTagNotFound, tagID)
+	}
+
+	if err := s.repo.RemoveTicketTag(ticketID, tag.ID); err != nil {
+		return err
+	}
+
+	return s.recordTicketLog(ticketID, claim, models.TicketLogTagRemoved, fmt.Sprintf("Removed tag %s", tag.NamaTag))
+}
+
+func (s *appService) AddConversationTag(conversationID uint64, tagID int) (*models.Tag, error) {
+	if _, err := s.repo.GetConversationByID(conversationID); err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, fmt.Errorf("%w: conversation %d", domain.ErrRecordNotFound, conversationID)
+		}
+		return nil, err
+	}
+	tag, err := s.repo.GetTagByID(tagID)
+	if err != nil {
+		return nil, fmt.Errorf("%w: tag %d", domain.ErrTagNotFound, tagID)
+	}
+	if err := s.repo.AddConversationTag(conversationID, tag); err != nil {
+		return nil, err
+	}
+	return tag, nil
+}
+
+func (s *appService) RemoveConversationTag(conversationID uint64, tagID int) error {
+	return s.repo.RemoveConversationTag(conversationID, tagID)
+}
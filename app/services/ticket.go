@@ -1,33 +1,54 @@
 package services
 
 import (
+	"app/domain"
 	"app/domain/models"
-	"crypto/md5"
 	"fmt"
 	"strings"
 	"time"
 )
 
 func (s *appService) CreateTicket(ticket *models.Ticket) error {
-	// Generate kode_tiket if not provided
-	if ticket.KodeTiket == "" {
-		ticket.KodeTiket = s.generateTicketCode(ticket.Judul, ticket.Deskripsi)
+	// Every ticket starts in the initial status of the workflow
+	statusID, err := s.initialTicketStatusID()
+	if err != nil {
+		return err
 	}
-	return s.repo.CreateTicket(ticket)
-}
+	ticket.StatusID = statusID
 
-func (s *appService) generateTicketCode(title, description string) string {
-	// Combine title + description + current time
-	input := fmt.Sprintf("%s%s%d", title, description, time.Now().UnixNano())
+	customFields, err := s.validateTicketCustomFields(ticket.CategoryID, ticket.CustomFields)
+	if err != nil {
+		return err
+	}
+	ticket.CustomFields = customFields
 
-	// Generate MD5 hash
-	hash := md5.Sum([]byte(input))
-	hashString := fmt.Sprintf("%x", hash)
+	// Compute the SLA due timestamps from the matching policy
+	if ticket.TanggalDibuat.IsZero() {
+		ticket.TanggalDibuat = time.Now()
+	}
+	s.refreshTicketSLA(ticket, nil, ticket.TanggalDibuat)
+	createdAt := ticket.TanggalDibuat
+	ticket.StatusChangedAt = &createdAt
 
-	// Take first 8 characters and convert to uppercase
-	shortHash := strings.ToUpper(hashString[:8])
+	// The code sequence only advances when the ticket is stored
+	err = s.withTransaction(func(tx *appService) error {
+		// Generate kode_tiket if not provided, a given one is kept out of the sequence
+		if ticket.KodeTiket == "" {
+			code, err := tx.nextTicketCode(ticket)
+			if err != nil {
+				return err
+			}
+			ticket.KodeTiket = code
+		} else if err := tx.reserveTicketCode(ticket); err != nil {
+			return err
+		}
+		return tx.repo.CreateTicket(ticket)
+	})
+	if err != nil {
+		return err
+	}
 
-	return fmt.Sprintf("TCKT-%s", shortHash)
+	return s.recordTicketLog(ticket.ID, models.User{ID: ticket.UserID}, models.TicketLogTicketCreated, fmt.Sprintf("Ticket %s created", ticket.KodeTiket))
 }
 
 func (s *appService) GetTickets() ([]models.Ticket, error) {
@@ -38,22 +59,251 @@ func (s *appService) GetTicketsPaginated(limit, offset int) ([]models.Ticket, in
     return s.repo.GetTicketsPaginated(limit, offset)
 }
 
+// GetTicketByID returns the ticket, or the surviving ticket when it was merged
 func (s *appService) GetTicketByID(id int) (*models.Ticket, error) {
-	return s.repo.GetTicketByID(id)
+	ticket, err := s.repo.GetTicketByID(id)
+	if err != nil {
+		return nil, err
+	}
+	for depth := 0; ticket.MergedIntoID != nil && depth < models.MaxMergeDepth; depth++ {
+		if ticket, err = s.repo.GetTicketByID(*ticket.MergedIntoID); err != nil {
+			return nil, err
+		}
+	}
+	s.evaluateTicketSLA(ticket, time.Now())
+	return ticket, nil
+}
+
+// UpdateTicket applies the editable fields of ticket. A non-zero ticket.Version is the
+// version the caller last read, the update fails with ErrVersionConflict when it is outdated.
+func (s *appService) UpdateTicket(ticket *models.Ticket, claim models.User) error {
+	current, err := s.repo.GetTicketByID(ticket.ID)
+	if err != nil {
+		return err
+	}
+	if ticket.Version != 0 && ticket.Version != current.Version {
+		return domain.ErrVersionConflict
+	}
+
+	before := newTicketAuditFields(current)
+	fromStatus := ticketStatusName(current)
+
+	// kode_tiket, the owner and tipe_pengaduan are fixed when the ticket is created
+	if ticket.KodeTiket != "" && ticket.KodeTiket != current.KodeTiket {
+		return &domain.ValidationError{Fields: map[string]string{"kode_tiket": "cannot be changed"}}
+	}
+
+	// Custom fields are kept when omitted. They are only checked against the category when
+	// either changes, so a status change or bulk update still works on a ticket created
+	// before a field became required.
+	checkCustomFields := ticket.CategoryID != current.CategoryID ||
+		(ticket.CustomFields != nil && customFieldValuesChanged(current.CustomFields, ticket.CustomFields))
+
+	// Apply the editable fields on the stored ticket so tracked fields are kept
+	current.Judul = ticket.Judul
+	current.Deskripsi = ticket.Deskripsi
+	current.CategoryID = ticket.CategoryID
+	current.PriorityID = ticket.PriorityID
+	if ticket.CustomFields != nil {
+		current.CustomFields = ticket.CustomFields
+	}
+	if checkCustomFields {
+		customFields, err := s.validateTicketCustomFields(current.CategoryID, current.CustomFields)
+		if err != nil {
+			return err
+		}
+		current.CustomFields = customFields
+	}
+
+	// Status changes have to follow the workflow, an empty status keeps the current one
+	statusChanged := ticket.StatusID != 0 && ticket.StatusID != current.StatusID
+	if statusChanged {
+		if err := s.changeTicketStatus(current, ticket.StatusID, claim); err != nil {
+			return err
+		}
+	} else {
+		// Priority or category may select another SLA policy
+		s.refreshTicketSLA(current, nil, time.Now())
+	}
+
+	current.TanggalDiperbarui = time.Now()
+	if err := s.repo.UpdateTicket(current); err != nil {
+		return err
+	}
+
+	if err := s.recordTicketUpdate(current, before, fromStatus, claim); err != nil {
+		return err
+	}
+	if statusChanged {
+		s.notifyTicketWatchers(current, TicketNotificationStatusChange, claim, fmt.Sprintf("Status tiket berubah menjadi %s", ticketStatusName(current)))
+		if current.Status != nil && current.Status.IsResolved {
+			s.requestCSATSurvey(current)
+		}
+	}
+
+	*ticket = *current
+	return nil
+}
+
+// ticketAuditFields are the ticket fields whose changes are written to the ticket log, the
+// status is logged by name on its own
+type ticketAuditFields struct {
+	KodeTiket     string                   `json:"kode_tiket"`
+	UserID        uint64                   `json:"id_user"`
+	Judul         string                   `json:"judul"`
+	Deskripsi     string                   `json:"deskripsi"`
+	CategoryID    int                      `json:"id_category"`
+	PriorityID    int                      `json:"id_priority"`
+	TipePengaduan models.UserRole          `json:"tipe_pengaduan"`
+	CustomFields  models.CustomFieldValues `json:"custom_fields"`
 }
 
-func (s *appService) UpdateTicket(ticket *models.Ticket) error {
-	return s.repo.UpdateTicket(ticket)
+func newTicketAuditFields(ticket *models.Ticket) ticketAuditFields {
+	return ticketAuditFields{
+		KodeTiket:     ticket.KodeTiket,
+		UserID:        ticket.UserID,
+		Judul:         ticket.Judul,
+		Deskripsi:     ticket.Deskripsi,
+		CategoryID:    ticket.CategoryID,
+		PriorityID:    ticket.PriorityID,
+		TipePengaduan: ticket.TipePengaduan,
+		CustomFields:  ticket.CustomFields,
+	}
 }
 
-func (s *appService) DeleteTicket(id int) error {
-	return s.repo.DeleteTicket(id)
+// ticketPatchFields are the ticket fields a merge patch may change, named as in the requests
+type ticketPatchFields struct {
+	Judul        string                   `json:"judul"`
+	Deskripsi    string                   `json:"deskripsi"`
+	CategoryID   int                      `json:"id_category"`
+	PriorityID   int                      `json:"id_priority"`
+	StatusID     int                      `json:"id_status"`
+	CustomFields models.CustomFieldValues `json:"custom_fields"`
+}
+
+// PatchTicket applies a JSON merge patch to a ticket. Only the fields present in the patch
+// change, they go through the same checks and logging as UpdateTicket.
+func (s *appService) PatchTicket(id int, patch []byte, version int, claim models.User) (*models.Ticket, error) {
+	current, err := s.repo.GetTicketByID(id)
+	if err != nil {
+		return nil, fmt.Errorf("%w: ticket %d", domain.ErrTicketNotFound, id)
+	}
+	if version != 0 && version != current.Version {
+		return nil, domain.ErrVersionConflict
+	}
+
+	fields := ticketPatchFields{
+		Judul:        current.Judul,
+		Deskripsi:    current.Deskripsi,
+		CategoryID:   current.CategoryID,
+		PriorityID:   current.PriorityID,
+		StatusID:     current.StatusID,
+		CustomFields: current.CustomFields,
+	}
+	changes, err := applyMergePatch(&fields, patch)
+	if err != nil {
+		return nil, err
+	}
+	if len(changes) == 0 {
+		s.evaluateTicketSLA(current, time.Now())
+		return current, nil
+	}
+
+	var validation domain.ValidationError
+	if strings.TrimSpace(fields.Judul) == "" {
+		validation.Add("judul", "is required")
+	}
+	if _, err := s.repo.GetTicketCategoryByID(fields.CategoryID); err != nil {
+		validation.Add("id_category", "unknown category")
+	}
+	if _, err := s.repo.GetTicketPriorityByID(fields.PriorityID); err != nil {
+		validation.Add("id_priority", "unknown priority")
+	}
+	if _, err := s.repo.GetTicketStatusByID(fields.StatusID); err != nil {
+		validation.Add("id_status", "unknown status")
+	}
+	if err := validation.OrNil(); err != nil {
+		return nil, err
+	}
+
+	ticket := *current
+	ticket.Judul = fields.Judul
+	ticket.Deskripsi = fields.Deskripsi
+	ticket.CategoryID = fields.CategoryID
+	ticket.PriorityID = fields.PriorityID
+	ticket.StatusID = fields.StatusID
+	ticket.CustomFields = fields.CustomFields
+	if ticket.CustomFields == nil {
+		// UpdateTicket keeps the stored values when none are given
+		ticket.CustomFields = models.CustomFieldValues{}
+	}
+	// UpdateTicket writes every change to the ticket log
+	if err := s.UpdateTicket(&ticket, claim); err != nil {
+		return nil, err
+	}
+	return &ticket, nil
+}
+
+// canAccessTicket applies the ticket access policy of the routes, see
+// domain.CanAccessTicket, to a ticket reached without going through them
+func (s *appService) canAccessTicket(ticket *models.Ticket, claim models.User) bool {
+	// An unassigned ticket has no assignment row
+	assignment, err := s.repo.GetTicketAssignmentByTicketID(ticket.ID)
+	if err != nil {
+		assignment = nil
+	}
+	return domain.CanAccessTicket(claim, ticket, assignment)
+}
+
+// GetTicketByCode returns the ticket with the given code, see GetTicketByID
+func (s *appService) GetTicketByCode(code string) (*models.Ticket, error) {
+	ticket, err := s.repo.GetTicketByCode(strings.TrimSpace(code))
+	if err != nil {
+		return nil, fmt.Errorf("%w: code %s", domain.ErrTicketNotFound, code)
+	}
+	return s.GetTicketByID(ticket.ID)
 }
 
 func (s *appService) GetTicketsByUserID(userID int) ([]models.Ticket, error) {
 	return s.repo.GetTicketsByUserID(userID)
 }
 
-func (s *appService) GetTicketsCursor(limit int, cursor string, tipePengaduan string, statusID, priorityID, categoryID int) ([]models.Ticket, string, error) {
-    return s.repo.GetTicketsCursor(limit, cursor, tipePengaduan, statusID, priorityID, categoryID)
+func (s *appService) GetTicketsCursor(limit int, cursor string, filter domain.TicketFilter) ([]models.Ticket, string, error) {
+	filter.SLAAtRiskWindow = slaAtRiskWindow()
+	tickets, nextCursor, err := s.repo.GetTicketsCursor(limit, cursor, filter)
+	if err != nil {
+		return nil, "", err
+	}
+
+	now := time.Now()
+	for i := range tickets {
+		s.evaluateTicketSLA(&tickets[i], now)
+	}
+	return tickets, nextCursor, nil
+}
+
+func (s *appService) SearchTickets(query string, limit int, cursor string, filter domain.TicketFilter, claim models.User) ([]domain.TicketSearchResult, string, error) {
+	filter.SLAAtRiskWindow = slaAtRiskWindow()
+	// Search only finds the tickets the user may open, for support the ones assigned to them
+	if claim.Role != models.RoleAdmin {
+		filter.AssigneeID = claim.ID
+	}
+	results, nextCursor, err := s.repo.SearchTickets(query, limit, cursor, filter)
+	if err != nil {
+		return nil, "", err
+	}
+
+	now := time.Now()
+	for i := range results {
+		s.evaluateTicketSLA(&results[i].Ticket, now)
+	}
+	return results, nextCursor, nil
+}
+
+// ticketStatusName returns the name of the loaded status, or its ID when not loaded
+func ticketStatusName(ticket *models.Ticket) string {
+	if ticket.Status != nil && ticket.Status.NamaStatus != "" {
+		return ticket.Status.NamaStatus
+	}
+	return fmt.Sprintf("status %d", ticket.StatusID)
 }
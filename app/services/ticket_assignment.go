@@ -1,13 +1,16 @@
 package services
 
 import (
+	"app/domain"
 	"app/domain/models"
 	"errors"
 	"fmt"
+	"log"
+	"strconv"
 	"time"
 )
 
-func (s *appService) CreateTicketAssignment(assignment *models.TicketAssignment) error {
+func (s *appService) CreateTicketAssignment(assignment *models.TicketAssignment, claim models.User) error {
     // Validate that the admin being assigned has the support role
     admin, err := s.repo.GetUserByID(uint64(assignment.AdminID))
     if err != nil {
@@ -24,15 +27,18 @@ func (s *appService) CreateTicketAssignment(assignment *models.TicketAssignment)
         return fmt.Errorf("ticket not found: %v", err)
     }
 
-    // Update ticket status to "In Progress" (ID 2) and priority if provided BEFORE creating assignment
-    ticket.StatusID = 2
+    before, fromStatus := newTicketAuditFields(ticket), ticketStatusName(ticket)
+
+    // Update priority if provided and move the ticket along the workflow BEFORE creating assignment
     if assignment.PriorityID != nil {
         ticket.PriorityID = *assignment.PriorityID
     }
-    ticket.TanggalDiperbarui = time.Now()
-    
+    if err := s.applyTicketEvent(ticket, models.WorkflowEventAssign, claim); err != nil {
+        return err
+    }
+
     if err := s.repo.UpdateTicket(ticket); err != nil {
-        return fmt.Errorf("failed to update ticket status: %v", err)
+        return fmt.Errorf("failed to update ticket status: %w", err)
     }
 
     // Create the assignment after ticket is updated
@@ -40,6 +46,17 @@ func (s *appService) CreateTicketAssignment(assignment *models.TicketAssignment)
         return fmt.Errorf("failed to create ticket assignment: %v", err)
     }
 
+    entry := newTicketLog(ticket.ID, claim, models.TicketLogAssignmentCreated, fmt.Sprintf("Assignment %d created, assigned to %s", assignment.ID, admin.Username))
+    entry.Field = "id_admin"
+    entry.NewValue = strconv.Itoa(assignment.AdminID)
+    if err := s.writeTicketLog(entry); err != nil {
+        return err
+    }
+    if err := s.recordTicketUpdate(ticket, before, fromStatus, claim); err != nil {
+        return err
+    }
+
+    s.watchAssignedTicket(ticket, admin, claim)
     return nil
 }
 
@@ -51,7 +68,18 @@ func (s *appService) GetTicketAssignmentByID(id int) (*models.TicketAssignment,
     return s.repo.GetTicketAssignmentByID(id)
 }
 
-func (s *appService) UpdateTicketAssignment(assignment *models.TicketAssignment) error {
+// UpdateTicketAssignment reassigns a ticket and logs the change on it. A non-zero assignment.Version is the version the
+// caller last read, the update fails with ErrVersionConflict when it is outdated.
+func (s *appService) UpdateTicketAssignment(assignment *models.TicketAssignment, claim models.User) error {
+    current, err := s.repo.GetTicketAssignmentByID(assignment.ID)
+    if err != nil {
+        return fmt.Errorf("assignment not found: %v", err)
+    }
+    if assignment.Version != 0 && assignment.Version != current.Version {
+        return domain.ErrVersionConflict
+    }
+    assignment.Version = current.Version
+
     // Validate that the admin being assigned has the support role
     admin, err := s.repo.GetUserByID(uint64(assignment.AdminID))
     if err != nil {
@@ -68,27 +96,120 @@ func (s *appService) UpdateTicketAssignment(assignment *models.TicketAssignment)
         return fmt.Errorf("ticket not found: %v", err)
     }
 
-    // Update ticket status to "In Progress" (ID 2) and priority if provided BEFORE updating assignment
-    ticket.StatusID = 2
+    before, fromStatus := newTicketAuditFields(ticket), ticketStatusName(ticket)
+
+    // Update priority if provided and move the ticket along the workflow BEFORE updating assignment
     if assignment.PriorityID != nil {
         ticket.PriorityID = *assignment.PriorityID
     }
-    ticket.TanggalDiperbarui = time.Now()
-    
+    if err := s.applyTicketEvent(ticket, models.WorkflowEventAssign, claim); err != nil {
+        return err
+    }
+
     if err := s.repo.UpdateTicket(ticket); err != nil {
-        return fmt.Errorf("failed to update ticket status: %v", err)
+        return fmt.Errorf("failed to update ticket status: %w", err)
     }
 
     // Update the assignment after ticket is updated
     if err := s.repo.UpdateTicketAssignment(assignment); err != nil {
-        return fmt.Errorf("failed to update ticket assignment: %v", err)
+        return fmt.Errorf("failed to update ticket assignment: %w", err)
     }
 
+    changes, err := diffFields(newTicketAssignmentAuditFields(current), newTicketAssignmentAuditFields(assignment))
+    if err != nil {
+        log.Printf("[ticket-log] failed to diff assignment %d: %v", assignment.ID, err)
+    }
+    if err := s.recordTicketChanges(ticket.ID, claim, models.TicketLogAssignmentUpdated, fmt.Sprintf("Assignment %d", assignment.ID), changes); err != nil {
+        return err
+    }
+    if err := s.recordTicketUpdate(ticket, before, fromStatus, claim); err != nil {
+        return err
+    }
+
+    s.watchAssignedTicket(ticket, admin, claim)
     return nil
 }
 
-func (s *appService) DeleteTicketAssignment(id int) error {
-    return s.repo.DeleteTicketAssignment(id)
+// ticketAssignmentAuditFields are the assignment fields whose changes are written to the ticket log
+type ticketAssignmentAuditFields struct {
+    TicketID          int       `json:"id_ticket"`
+    AdminID           int       `json:"id_admin"`
+    PriorityID        *int      `json:"priority_id"`
+    TanggalDitugaskan time.Time `json:"tanggal_ditugaskan"`
+}
+
+func newTicketAssignmentAuditFields(assignment *models.TicketAssignment) ticketAssignmentAuditFields {
+    return ticketAssignmentAuditFields{
+        TicketID:          assignment.TicketID,
+        AdminID:           assignment.AdminID,
+        PriorityID:        assignment.PriorityID,
+        TanggalDitugaskan: assignment.TanggalDitugaskan,
+    }
+}
+
+// watchAssignedTicket makes the assignee follow the ticket and notifies the watchers
+func (s *appService) watchAssignedTicket(ticket *models.Ticket, assignee *models.User, claim models.User) {
+    if err := s.addTicketWatcher(ticket.ID, assignee.ID); err != nil {
+        log.Printf("Failed to add assignee %d as watcher of ticket #%s: %v", assignee.ID, ticket.KodeTiket, err)
+    }
+    s.notifyTicketWatchers(ticket, TicketNotificationAssignment, claim, fmt.Sprintf("Tiket ditugaskan kepada %s", assignee.Username))
+}
+
+// PatchTicketAssignment applies a JSON merge patch to an assignment through
+// UpdateTicketAssignment
+func (s *appService) PatchTicketAssignment(id int, patch []byte, version int, claim models.User) (*models.TicketAssignment, error) {
+    current, err := s.repo.GetTicketAssignmentByID(id)
+    if err != nil {
+        return nil, fmt.Errorf("%w: assignment %d", domain.ErrRecordNotFound, id)
+    }
+    if version != 0 && version != current.Version {
+        return nil, domain.ErrVersionConflict
+    }
+
+    fields := struct {
+        AdminID           int       `json:"id_admin"`
+        PriorityID        *int      `json:"priority_id"`
+        TanggalDitugaskan time.Time `json:"tanggal_ditugaskan"`
+    }{
+        AdminID:           current.AdminID,
+        PriorityID:        current.PriorityID,
+        TanggalDitugaskan: current.TanggalDitugaskan,
+    }
+    changes, err := applyMergePatch(&fields, patch)
+    if err != nil {
+        return nil, err
+    }
+    if len(changes) == 0 {
+        return current, nil
+    }
+    if fields.TanggalDitugaskan.IsZero() {
+        return nil, &domain.ValidationError{Fields: map[string]string{"tanggal_ditugaskan": "is required"}}
+    }
+
+    assignment := *current
+    assignment.AdminID = fields.AdminID
+    assignment.PriorityID = fields.PriorityID
+    assignment.TanggalDitugaskan = fields.TanggalDitugaskan
+    assignment.Ticket, assignment.Admin, assignment.Priority = nil, nil, nil
+    if err := s.UpdateTicketAssignment(&assignment, claim); err != nil {
+        return nil, err
+    }
+    return &assignment, nil
+}
+
+func (s *appService) DeleteTicketAssignment(id int, claim models.User) error {
+    assignment, err := s.repo.GetTicketAssignmentByID(id)
+    if err != nil {
+        return err
+    }
+    if err := s.repo.DeleteTicketAssignment(id); err != nil {
+        return err
+    }
+
+    entry := newTicketLog(assignment.TicketID, claim, models.TicketLogAssignmentDeleted, fmt.Sprintf("Assignment %d deleted", assignment.ID))
+    entry.Field = "id_admin"
+    entry.OldValue = strconv.Itoa(assignment.AdminID)
+    return s.writeTicketLog(entry)
 }
 
 func (s *appService) GetTicketAssignmentByTicketID(ticketID int) (*models.TicketAssignment, error) {
@@ -9,7 +9,7 @@ import (
 	"time"
 )
 
-func (s *appService) CreateTicketAttachment(ticketID int, file *multipart.FileHeader) (*models.TicketAttachment, error) {
+func (s *appService) CreateTicketAttachment(ticketID int, file *multipart.FileHeader, claim models.User) (*models.TicketAttachment, error) {
 	// Check if S3 is available
 	if s.s3Repo == nil {
 		return nil, fmt.Errorf("file storage service is not configured")
@@ -38,6 +38,13 @@ func (s *appService) CreateTicketAttachment(ticketID int, file *multipart.FileHe
 	}
 
 	log.Printf("[ticket-attachment-service] attachment record created: id=%d file=%s", attachment.ID, attachment.FilePath)
+
+	entry := newTicketLog(ticketID, claim, models.TicketLogAttachmentCreated, fmt.Sprintf("Attachment %d added: %s", attachment.ID, file.Filename))
+	entry.Field = "file_path"
+	entry.NewValue = attachment.FilePath
+	if err := s.writeTicketLog(entry); err != nil {
+		return nil, err
+	}
 	return attachment, nil
 }
 
@@ -67,11 +74,12 @@ func (s *appService) GetTicketAttachmentsByTicketID(ticketID int) ([]models.Tick
 	return s.repo.GetTicketAttachmentsByTicketID(ticketID)
 }
 
-func (s *appService) UpdateTicketAttachment(id int, ticketID *int, file *multipart.FileHeader) (*models.TicketAttachment, error) {
+func (s *appService) UpdateTicketAttachment(id int, ticketID *int, file *multipart.FileHeader, claim models.User) (*models.TicketAttachment, error) {
 	attachment, err := s.repo.GetTicketAttachmentByID(id)
 	if err != nil {
 		return nil, err
 	}
+	before := newTicketAttachmentAuditFields(attachment)
 
 	// Update ticket ID if provided
 	if ticketID != nil {
@@ -109,10 +117,27 @@ func (s *appService) UpdateTicketAttachment(id int, ticketID *int, file *multipa
 		}
 	}
 
+	changes, err := diffFields(before, newTicketAttachmentAuditFields(attachment))
+	if err != nil {
+		log.Printf("[ticket-log] failed to diff attachment %d: %v", attachment.ID, err)
+	}
+	if err := s.recordTicketChanges(attachment.TicketID, claim, models.TicketLogAttachmentUpdated, fmt.Sprintf("Attachment %d", attachment.ID), changes); err != nil {
+		return nil, err
+	}
 	return attachment, nil
 }
 
-func (s *appService) DeleteTicketAttachment(id int) error {
+// ticketAttachmentAuditFields are the attachment fields whose changes are written to the ticket log
+type ticketAttachmentAuditFields struct {
+	TicketID int    `json:"id_ticket"`
+	FilePath string `json:"file_path"`
+}
+
+func newTicketAttachmentAuditFields(attachment *models.TicketAttachment) ticketAttachmentAuditFields {
+	return ticketAttachmentAuditFields{TicketID: attachment.TicketID, FilePath: attachment.FilePath}
+}
+
+func (s *appService) DeleteTicketAttachment(id int, claim models.User) error {
 	// Get the attachment first
 	attachment, err := s.repo.GetTicketAttachmentByID(id)
 	if err != nil {
@@ -125,6 +150,13 @@ func (s *appService) DeleteTicketAttachment(id int) error {
 		return err
 	}
 
+	entry := newTicketLog(attachment.TicketID, claim, models.TicketLogAttachmentDeleted, fmt.Sprintf("Attachment %d deleted", attachment.ID))
+	entry.Field = "file_path"
+	entry.OldValue = attachment.FilePath
+	if err := s.writeTicketLog(entry); err != nil {
+		return err
+	}
+
 	// Delete file from MinIO
 	if attachment != nil && attachment.FilePath != "" {
 		ctx := context.Background()
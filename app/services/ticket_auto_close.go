@@ -0,0 +1,103 @@
+package services
+
+import (
+	"app/domain/models"
+	"fmt"
+	"log"
+	"time"
+)
+
+// AutoCloseResolvedTickets closes the tickets that stayed resolved for days working days of
+// the default calendar without a customer reply. The ticket owner is emailed warningDays
+// working days before the ticket closes, a ticket is never closed before its owner was
+// warned for the full warning period.
+func (s *appService) AutoCloseResolvedTickets(days, warningDays int) error {
+	if warningDays > days {
+		warningDays = days
+	}
+
+	// Weekends and holidays of the default calendar do not count
+	clock := s.defaultBusinessClock()
+	now := time.Now()
+	tickets, err := s.repo.GetTicketsAwaitingAutoClose(clock.AddDays(now, warningDays-days))
+	if err != nil {
+		return err
+	}
+
+	for i := range tickets {
+		ticket := &tickets[i]
+		closesAt := clock.AddDays(ticketStatusChangedAt(ticket), days)
+
+		if warningDays > 0 {
+			if ticket.AutoCloseWarnedAt == nil {
+				s.warnTicketAutoClose(ticket, closesAt, now)
+				continue
+			}
+			// The owner always gets the full warning period
+			if warnedUntil := clock.AddDays(*ticket.AutoCloseWarnedAt, warningDays); warnedUntil.After(closesAt) {
+				closesAt = warnedUntil
+			}
+		}
+
+		if closesAt.After(now) {
+			continue
+		}
+		if err := s.autoCloseTicket(ticket, days); err != nil {
+			log.Printf("[auto-close] failed to close ticket #%s: %v", ticket.KodeTiket, err)
+		}
+	}
+
+	return nil
+}
+
+// warnTicketAutoClose emails the ticket owner that the ticket closes at closesAt. The warning
+// is only recorded once it was sent, a failed email is retried on the next run.
+func (s *appService) warnTicketAutoClose(ticket *models.Ticket, closesAt, now time.Time) {
+	if closesAt.Before(now) {
+		closesAt = now
+	}
+	message := fmt.Sprintf("Tiket Anda telah diselesaikan dan akan ditutup otomatis pada %s. Balas tiket ini jika masalah Anda belum terselesaikan.", closesAt.Format("02 January 2006, 15:04"))
+	if err := s.sendTicketNotificationEmail(&ticket.User, ticket.KodeTiket, ticket.Judul, TicketNotificationAutoClose, message); err != nil {
+		log.Printf("[auto-close] failed to warn %s about ticket #%s: %v", ticket.User.Email, ticket.KodeTiket, err)
+		return
+	}
+
+	recorded, err := s.repo.MarkTicketAutoCloseWarned(ticket.ID, now)
+	if err != nil {
+		log.Printf("[auto-close] failed to mark warning for ticket #%s: %v", ticket.KodeTiket, err)
+		return
+	}
+	if recorded {
+		s.recordTicketLog(ticket.ID, models.SystemUser, models.TicketLogAutoCloseWarning, fmt.Sprintf("Auto-close warning sent to %s, ticket closes on %s", ticket.User.Email, closesAt.Format("2006-01-02T15:04:05Z07:00")))
+	}
+}
+
+// autoCloseTicket moves the ticket along the auto-close transition as the system. A customer
+// reply racing the job raises the version, the stale ticket is then left alone.
+func (s *appService) autoCloseTicket(ticket *models.Ticket, days int) error {
+	fromStatus := ticketStatusName(ticket)
+	if err := s.applyTicketEvent(ticket, models.WorkflowEventAutoClose, models.SystemUser); err != nil {
+		return err
+	}
+	if err := s.repo.UpdateTicket(ticket); err != nil {
+		return err
+	}
+
+	if err := s.recordStatusChange(ticket, fromStatus, models.SystemUser); err != nil {
+		return err
+	}
+	if err := s.recordTicketLog(ticket.ID, models.SystemUser, models.TicketLogAutoClosed, fmt.Sprintf("Ticket closed automatically after %d working days without customer reply", days)); err != nil {
+		return err
+	}
+	s.notifyTicketWatchers(ticket, TicketNotificationStatusChange, models.SystemUser, fmt.Sprintf("Status tiket berubah menjadi %s", ticketStatusName(ticket)))
+	return nil
+}
+
+// ticketStatusChangedAt is when the ticket entered its current status, tickets created before
+// the timestamp was tracked fall back to their last update
+func ticketStatusChangedAt(ticket *models.Ticket) time.Time {
+	if ticket.StatusChangedAt != nil {
+		return *ticket.StatusChangedAt
+	}
+	return ticket.TanggalDiperbarui
+}
@@ -0,0 +1,123 @@
+package services
+
+import (
+	"app/domain"
+	"app/domain/models"
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+)
+
+// BulkUpdateTickets applies one action to a list of tickets in a single transaction.
+// Every ticket runs in its own savepoint through the same service paths as the single
+// ticket endpoints, so a ticket failing validation is rolled back and reported without
+// undoing the others.
+func (s *appService) BulkUpdateTickets(op domain.BulkTicketOperation, claim models.User) ([]domain.BulkTicketResult, error) {
+	results := make([]domain.BulkTicketResult, 0, len(op.TicketIDs))
+	err := s.withTransaction(func(tx *appService) error {
+		for _, ticketID := range op.TicketIDs {
+			err := tx.withTransaction(func(item *appService) error {
+				return item.applyBulkTicketAction(ticketID, op, claim)
+			})
+
+			result := domain.BulkTicketResult{TicketID: ticketID, Success: err == nil}
+			if err != nil {
+				result.Error = bulkTicketErrorMessage(err)
+				result.Err = err
+			}
+			results = append(results, result)
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return results, nil
+}
+
+func (s *appService) applyBulkTicketAction(ticketID int, op domain.BulkTicketOperation, claim models.User) error {
+	current, err := s.repo.GetTicketByID(ticketID)
+	if err != nil {
+		return fmt.Errorf("%w: ticket %d", domain.ErrTicketNotFound, ticketID)
+	}
+	if !s.canAccessTicket(current, claim) {
+		return fmt.Errorf("%w: ticket %d", domain.ErrTicketAccessDenied, ticketID)
+	}
+
+	var aktivitas string
+	switch op.Action {
+	case domain.BulkTicketActionStatus, domain.BulkTicketActionPriority, domain.BulkTicketActionCategory:
+		ticket := *current
+		switch op.Action {
+		case domain.BulkTicketActionStatus:
+			ticket.StatusID = op.StatusID
+		case domain.BulkTicketActionPriority:
+			ticket.PriorityID = op.PriorityID
+		case domain.BulkTicketActionCategory:
+			ticket.CategoryID = op.CategoryID
+		}
+		if err := s.UpdateTicket(&ticket, claim); err != nil {
+			return err
+		}
+		aktivitas = fmt.Sprintf("Bulk update: %s set to %d", op.Action, bulkTicketActionValue(op))
+
+	case domain.BulkTicketActionAssign:
+		assignment, err := s.repo.GetTicketAssignmentByTicketID(ticketID)
+		if err == nil {
+			assignment.AdminID = op.AdminID
+			assignment.Ticket, assignment.Admin, assignment.Priority = nil, nil, nil
+			err = s.UpdateTicketAssignment(assignment, claim)
+		} else {
+			err = s.CreateTicketAssignment(&models.TicketAssignment{
+				TicketID:          ticketID,
+				AdminID:           op.AdminID,
+				TanggalDitugaskan: time.Now(),
+			}, claim)
+		}
+		if err != nil {
+			return err
+		}
+		aktivitas = fmt.Sprintf("Bulk update: assigned to admin %d", op.AdminID)
+
+	case domain.BulkTicketActionDelete:
+		if err := s.DeleteTicket(ticketID, claim); err != nil {
+			return err
+		}
+		aktivitas = "Bulk update: ticket deleted"
+
+	default:
+		return fmt.Errorf("unknown bulk action %q", op.Action)
+	}
+
+	// Written in the savepoint so a failing entry rolls back the change it describes
+	return s.repo.CreateTicketLog(newTicketLog(ticketID, claim, models.TicketLogBulkUpdated, aktivitas))
+}
+
+func bulkTicketActionValue(op domain.BulkTicketOperation) int {
+	switch op.Action {
+	case domain.BulkTicketActionStatus:
+		return op.StatusID
+	case domain.BulkTicketActionPriority:
+		return op.PriorityID
+	case domain.BulkTicketActionCategory:
+		return op.CategoryID
+	}
+	return op.AdminID
+}
+
+// bulkTicketErrorMessage spells out validation errors, which only say "validation failed"
+func bulkTicketErrorMessage(err error) string {
+	var validationErr *domain.ValidationError
+	if !errors.As(err, &validationErr) {
+		return err.Error()
+	}
+
+	fields := make([]string, 0, len(validationErr.Fields))
+	for field, message := range validationErr.Fields {
+		fields = append(fields, field+": "+message)
+	}
+	sort.Strings(fields)
+	return strings.Join(fields, "; ")
+}
@@ -1,6 +1,12 @@
 package services
 
-import "app/domain/models"
+import (
+	"app/domain"
+	"app/domain/models"
+	"fmt"
+	"log"
+	"strings"
+)
 
 func (s *appService) CreateTicketCategory(category *models.TicketCategory) error {
 	return s.repo.CreateTicketCategory(category)
@@ -18,6 +24,33 @@ func (s *appService) UpdateTicketCategory(category *models.TicketCategory) error
 	return s.repo.UpdateTicketCategory(category)
 }
 
+// PatchTicketCategory applies a JSON merge patch to a category
+func (s *appService) PatchTicketCategory(id int, patch []byte) (*models.TicketCategory, error) {
+	category, err := s.repo.GetTicketCategoryByID(id)
+	if err != nil {
+		return nil, fmt.Errorf("%w: category %d", domain.ErrRecordNotFound, id)
+	}
+
+	fields := struct {
+		NamaCategory string `json:"nama_category"`
+	}{NamaCategory: category.NamaCategory}
+	changes, err := applyMergePatch(&fields, patch)
+	if err != nil || len(changes) == 0 {
+		return category, err
+	}
+	if strings.TrimSpace(fields.NamaCategory) == "" {
+		return nil, &domain.ValidationError{Fields: map[string]string{"nama_category": "is required"}}
+	}
+
+	category.NamaCategory = fields.NamaCategory
+	if err := s.repo.UpdateTicketCategory(category); err != nil {
+		return nil, err
+	}
+
+	log.Printf("[ticket-category] category %d changed: %s", category.ID, describeFieldChanges(changes))
+	return category, nil
+}
+
 func (s *appService) DeleteTicketCategory(id int) error {
 	return s.repo.DeleteTicketCategory(id)
 }
@@ -0,0 +1,122 @@
+package services
+
+import (
+	"app/domain/models"
+	"fmt"
+	"os"
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+// defaultTicketCodePrefixes are the code prefixes per tipe_pengaduan, TICKET_CODE_PREFIXES
+// overrides them as a comma separated list such as "customer=CUS,seller=SEL"
+var defaultTicketCodePrefixes = map[models.UserRole]string{
+	models.RoleCustomer: "CUS",
+	models.RoleSeller:   "SEL",
+	models.RoleAdmin:    "ADM",
+	models.RoleSupport:  "SUP",
+}
+
+// fallbackTicketCodePrefix is used for tickets without a known tipe_pengaduan
+const fallbackTicketCodePrefix = "TCK"
+
+// nextTicketCode hands out the next code for the ticket from the sequence of its prefix and
+// year. It has to run in the transaction creating the ticket to keep the sequence gap-free.
+func (s *appService) nextTicketCode(ticket *models.Ticket) (string, error) {
+	prefix := ticketCodePrefix(ticket.TipePengaduan)
+	year := ticket.TanggalDibuat.Year()
+
+	sequence, err := s.repo.NextTicketCodeSequence(prefix, year)
+	if err != nil {
+		return "", fmt.Errorf("failed to generate ticket code: %w", err)
+	}
+	return formatTicketCode(prefix, year, sequence), nil
+}
+
+// reserveTicketCode advances the sequence past a code given with the ticket, so generated
+// codes never take it. Codes in another format than TICKET_CODE_FORMAT are left alone.
+func (s *appService) reserveTicketCode(ticket *models.Ticket) error {
+	prefix, year, sequence, ok := parseTicketCode(ticket.KodeTiket, ticketCodePrefix(ticket.TipePengaduan), ticket.TanggalDibuat.Year())
+	if !ok {
+		return nil
+	}
+	if err := s.repo.AdvanceTicketCodeSequence(prefix, year, sequence); err != nil {
+		return fmt.Errorf("failed to reserve ticket code: %w", err)
+	}
+	return nil
+}
+
+func ticketCodePrefix(tipePengaduan models.UserRole) string {
+	prefixes := defaultTicketCodePrefixes
+	if configured := os.Getenv("TICKET_CODE_PREFIXES"); configured != "" {
+		prefixes = make(map[models.UserRole]string)
+		for _, pair := range strings.Split(configured, ",") {
+			role, prefix, found := strings.Cut(pair, "=")
+			if found && strings.TrimSpace(prefix) != "" {
+				prefixes[models.UserRole(strings.TrimSpace(role))] = strings.ToUpper(strings.TrimSpace(prefix))
+			}
+		}
+	}
+
+	if prefix, ok := prefixes[tipePengaduan]; ok {
+		return prefix
+	}
+	return fallbackTicketCodePrefix
+}
+
+// ticketCodeFormat returns TICKET_CODE_FORMAT, "{prefix}-{year}-{seq}" by default
+func ticketCodeFormat() string {
+	if format := os.Getenv("TICKET_CODE_FORMAT"); format != "" {
+		return format
+	}
+	return "{prefix}-{year}-{seq}"
+}
+
+// parseTicketCode reads the prefix, year and sequence of a code in TICKET_CODE_FORMAT. A
+// format without {prefix} or {year} takes them from prefix and year. ok is false when the
+// code is in another format.
+func parseTicketCode(code, prefix string, year int) (string, int, int, bool) {
+	pattern := strings.NewReplacer(
+		`\{prefix\}`, `(?P<prefix>[A-Za-z0-9]+)`,
+		`\{year\}`, `(?P<year>[0-9]{4})`,
+		`\{seq\}`, `(?P<seq>[0-9]+)`,
+	).Replace(regexp.QuoteMeta(ticketCodeFormat()))
+	re, err := regexp.Compile("^" + pattern + "$")
+	if err != nil {
+		return "", 0, 0, false
+	}
+	match := re.FindStringSubmatch(code)
+	if match == nil || re.SubexpIndex("seq") < 0 {
+		return "", 0, 0, false
+	}
+
+	sequence, err := strconv.Atoi(match[re.SubexpIndex("seq")])
+	if err != nil {
+		return "", 0, 0, false
+	}
+	if i := re.SubexpIndex("prefix"); i >= 0 {
+		prefix = strings.ToUpper(match[i])
+	}
+	if i := re.SubexpIndex("year"); i >= 0 {
+		year, _ = strconv.Atoi(match[i])
+	}
+	return prefix, year, sequence, true
+}
+
+// formatTicketCode fills TICKET_CODE_FORMAT (default "{prefix}-{year}-{seq}") with the zero
+// padded sequence, TICKET_CODE_DIGITS (default 6) wide. Sequences restart every year, so a
+// format without {year} repeats codes and the ticket is rejected as a duplicate.
+func formatTicketCode(prefix string, year, sequence int) string {
+	format := ticketCodeFormat()
+	digits, err := strconv.Atoi(os.Getenv("TICKET_CODE_DIGITS"))
+	if err != nil || digits <= 0 {
+		digits = 6
+	}
+
+	return strings.NewReplacer(
+		"{prefix}", prefix,
+		"{year}", strconv.Itoa(year),
+		"{seq}", fmt.Sprintf("%0*d", digits, sequence),
+	).Replace(format)
+}
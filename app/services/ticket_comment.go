@@ -1,9 +1,12 @@
 package services
 
 import (
+	"app/domain"
 	"app/domain/models"
+	"errors"
 	"fmt"
 	"log"
+	"strings"
 	"time"
 )
 
@@ -20,34 +23,118 @@ func (s *appService) CreateTicketComment(comment *models.TicketComment) error {
 		return fmt.Errorf("user not found: %v", err)
 	}
 
-	// Create the comment first
-	if err := s.repo.CreateTicketComment(comment); err != nil {
-		return fmt.Errorf("failed to create comment: %v", err)
+	// Get the author, the workflow guards transitions by role
+	author, err := s.repo.GetUserByID(uint64(comment.UserID))
+	if err != nil {
+		return fmt.Errorf("comment author not found: %v", err)
 	}
 
-	// Update ticket status to 3 (resolved/commented)
-	ticket.StatusID = 3
-	ticket.TanggalDiperbarui = time.Now()
-	
-	if err := s.repo.UpdateTicket(ticket); err != nil {
-		return fmt.Errorf("failed to update ticket status: %v", err)
+	// A reply from support stops the response clocks
+	fromSupport := author.Role == models.RoleAdmin || author.Role == models.RoleSupport
+	if fromSupport {
+		markTicketResponded(ticket, time.Now())
 	}
 
-	// Send email notification asynchronously
-	go func() {
-		emailErr := s.SendTicketCommentEmail(
-			user.Email,
-			user.Username,
-			ticket.KodeTiket,
-			ticket.Judul,
-			comment.IsiPesan,
-		)
-		if emailErr != nil {
-			log.Printf("Failed to send email notification for ticket #%s: %v", ticket.KodeTiket, emailErr)
+	// Validate the status change before anything is written. A customer reply only moves
+	// the ticket when the workflow has a transition for it, e.g. to reopen a resolved ticket
+	// or to wake a snoozed one. Support replies leave a snoozed or closed ticket as it is.
+	fromStatus, previousStatusID := ticketStatusName(ticket), ticket.StatusID
+	pending, pendingUntil := ticketIsPending(ticket), ticket.PendingUntil
+	terminal := ticket.Status != nil && ticket.Status.IsTerminal
+	switch {
+	case fromSupport && (pending || terminal):
+	case fromSupport:
+		if err := s.applyTicketEvent(ticket, models.WorkflowEventComment, *author); err != nil {
+			return err
+		}
+	default:
+		event := models.WorkflowEventCustomerReply
+		if pending {
+			event = models.WorkflowEventWake
+		}
+		if err := s.applyTicketEvent(ticket, event, *author); err != nil && !errors.Is(err, domain.ErrInvalidStatusTransition) {
+			return err
+		}
+	}
+	statusChanged := ticket.StatusID != previousStatusID
+	woken := statusChanged && pending
+	reopened := statusChanged && !fromSupport && !pending
+	resolved := statusChanged && fromSupport && ticket.Status != nil && ticket.Status.IsResolved
+
+	// The comment and the ticket it moves are stored together
+	err = s.withTransaction(func(tx *appService) error {
+		if err := tx.repo.CreateTicketComment(comment); err != nil {
+			return fmt.Errorf("failed to create comment: %v", err)
+		}
+
+		if err := tx.repo.UpdateTicket(ticket); err != nil {
+			return fmt.Errorf("failed to update ticket status: %w", err)
+		}
+
+		if err := tx.recordTicketLog(ticket.ID, *author, models.TicketLogCommentCreated, fmt.Sprintf("Comment %d added", comment.ID)); err != nil {
+			return err
+		}
+		var err error
+		if woken {
+			err = tx.recordTicketWoken(ticket, fromStatus, pendingUntil, *author, "Customer replied while the ticket was pending")
 		} else {
-			log.Printf("Email notification sent successfully for ticket #%s to %s", ticket.KodeTiket, user.Email)
+			err = tx.recordStatusChange(ticket, fromStatus, *author)
+		}
+		if err != nil {
+			return err
+		}
+
+		tx.notifyTicketWatchers(ticket, TicketNotificationComment, *author, comment.IsiPesan)
+		if reopened {
+			tx.notifyTicketWatchers(ticket, TicketNotificationStatusChange, *author, fmt.Sprintf("Status tiket berubah menjadi %s", ticketStatusName(ticket)))
 		}
-	}()
+		return nil
+	})
+	if err != nil {
+		return err
+	}
+
+	// The cascade and the email only follow a stored comment
+	base := s.base()
+
+	// Only support replies are cascaded, customers cannot post on tickets they do not own
+	if comment.CascadeToChildren && fromSupport {
+		s.onCommit(func() {
+			base.cascadeTicketComment(ticket, comment, *author)
+		})
+	}
+
+	// The resolution email only goes out for support replies
+	if !fromSupport {
+		return nil
+	}
+
+	// Send email notification asynchronously, a reply resolving the ticket asks for a rating
+	s.onCommit(func() {
+		go func() {
+			surveyURL := ""
+			if resolved {
+				var surveyErr error
+				if surveyURL, _, surveyErr = base.createCSATSurvey(ticket); surveyErr != nil {
+					log.Printf("[csat] failed to create survey for ticket #%s: %v", ticket.KodeTiket, surveyErr)
+				}
+			}
+
+			emailErr := base.SendTicketCommentEmail(
+				user.Email,
+				user.Username,
+				ticket.KodeTiket,
+				ticket.Judul,
+				comment.IsiPesan,
+				surveyURL,
+			)
+			if emailErr != nil {
+				log.Printf("Failed to send email notification for ticket #%s: %v", ticket.KodeTiket, emailErr)
+			} else {
+				log.Printf("Email notification sent successfully for ticket #%s to %s", ticket.KodeTiket, user.Email)
+			}
+		}()
+	})
 
 	return nil
 }
@@ -64,10 +151,85 @@ func (s *appService) GetTicketCommentsByTicketID(ticketID int) ([]models.TicketC
 	return s.repo.GetTicketCommentsByTicketID(ticketID)
 }
 
-func (s *appService) UpdateTicketComment(comment *models.TicketComment) error {
-	return s.repo.UpdateTicketComment(comment)
+// UpdateTicketComment edits a comment and logs the change on its ticket. A non-zero
+// comment.Version is the version the caller last read, the update fails with
+// ErrVersionConflict when it is outdated.
+func (s *appService) UpdateTicketComment(comment *models.TicketComment, claim models.User) error {
+	current, err := s.repo.GetTicketCommentByID(comment.ID)
+	if err != nil {
+		return err
+	}
+	if comment.Version != 0 && comment.Version != current.Version {
+		return domain.ErrVersionConflict
+	}
+
+	comment.Version = current.Version
+	comment.TanggalDibuat = current.TanggalDibuat
+	if err := s.repo.UpdateTicketComment(comment); err != nil {
+		return err
+	}
+
+	changes, err := diffFields(newTicketCommentAuditFields(current), newTicketCommentAuditFields(comment))
+	if err != nil {
+		log.Printf("[ticket-log] failed to diff comment %d: %v", comment.ID, err)
+	}
+	return s.recordTicketChanges(comment.TicketID, claim, models.TicketLogCommentUpdated, fmt.Sprintf("Comment %d", comment.ID), changes)
+}
+
+// ticketCommentAuditFields are the comment fields whose changes are written to the ticket log
+type ticketCommentAuditFields struct {
+	TicketID int    `json:"id_ticket"`
+	IsiPesan string `json:"isi_pesan"`
 }
 
-func (s *appService) DeleteTicketComment(id int) error {
-	return s.repo.DeleteTicketComment(id)
+func newTicketCommentAuditFields(comment *models.TicketComment) ticketCommentAuditFields {
+	return ticketCommentAuditFields{TicketID: comment.TicketID, IsiPesan: comment.IsiPesan}
+}
+
+// PatchTicketComment applies a JSON merge patch to a comment through UpdateTicketComment
+func (s *appService) PatchTicketComment(id int, patch []byte, version int, claim models.User) (*models.TicketComment, error) {
+	current, err := s.repo.GetTicketCommentByID(id)
+	if err != nil {
+		return nil, fmt.Errorf("%w: comment %d", domain.ErrRecordNotFound, id)
+	}
+	if version != 0 && version != current.Version {
+		return nil, domain.ErrVersionConflict
+	}
+
+	fields := struct {
+		IsiPesan string `json:"isi_pesan"`
+	}{IsiPesan: current.IsiPesan}
+	changes, err := applyMergePatch(&fields, patch)
+	if err != nil {
+		return nil, err
+	}
+	if len(changes) == 0 {
+		return current, nil
+	}
+	if strings.TrimSpace(fields.IsiPesan) == "" {
+		return nil, &domain.ValidationError{Fields: map[string]string{"isi_pesan": "is required"}}
+	}
+
+	comment := *current
+	comment.IsiPesan = fields.IsiPesan
+	comment.Ticket, comment.User = nil, nil
+	if err := s.UpdateTicketComment(&comment, claim); err != nil {
+		return nil, err
+	}
+	return &comment, nil
+}
+
+func (s *appService) DeleteTicketComment(id int, claim models.User) error {
+	comment, err := s.repo.GetTicketCommentByID(id)
+	if err != nil {
+		return err
+	}
+	if err := s.repo.DeleteTicketComment(id); err != nil {
+		return err
+	}
+
+	entry := newTicketLog(comment.TicketID, claim, models.TicketLogCommentDeleted, fmt.Sprintf("Comment %d deleted", comment.ID))
+	entry.Field = "isi_pesan"
+	entry.OldValue = comment.IsiPesan
+	return s.writeTicketLog(entry)
 }
\ No newline at end of file
@@ -0,0 +1,104 @@
+package services
+
+import (
+	"app/domain"
+	"encoding/csv"
+	"fmt"
+	"io"
+	"time"
+
+	"github.com/xuri/excelize/v2"
+)
+
+// ExportTickets writes the tickets matching the list filters to w as a spreadsheet. Rows
+// are written as they are read, an XLSX workbook buffers them in a temporary file once it
+// grows large and is written out at the end.
+func (s *appService) ExportTickets(filter domain.TicketFilter, format domain.TicketExportFormat, w io.Writer) error {
+	filter.SLAAtRiskWindow = slaAtRiskWindow()
+	switch format {
+	case domain.TicketExportCSV:
+		return s.exportTicketsCSV(filter, w)
+	case domain.TicketExportXLSX:
+		return s.exportTicketsXLSX(filter, w)
+	default:
+		var validation domain.ValidationError
+		validation.Add("format", "must be csv or xlsx")
+		return validation.OrNil()
+	}
+}
+
+func (s *appService) exportTicketsCSV(filter domain.TicketFilter, w io.Writer) error {
+	writer := csv.NewWriter(w)
+	if err := writer.Write(domain.TicketExportColumns); err != nil {
+		return err
+	}
+
+	record := make([]string, len(domain.TicketExportColumns))
+	err := s.repo.StreamTicketExport(filter, func(row domain.TicketExportRow) error {
+		for i, value := range row.Values() {
+			record[i] = exportCellText(value)
+		}
+		return writer.Write(record)
+	})
+	if err != nil {
+		return err
+	}
+
+	writer.Flush()
+	return writer.Error()
+}
+
+func (s *appService) exportTicketsXLSX(filter domain.TicketFilter, w io.Writer) error {
+	file := excelize.NewFile()
+	defer file.Close()
+
+	sheet := file.GetSheetName(0)
+	stream, err := file.NewStreamWriter(sheet)
+	if err != nil {
+		return err
+	}
+	dateStyle, err := file.NewStyle(&excelize.Style{NumFmt: 22}) // m/d/yy h:mm
+	if err != nil {
+		return err
+	}
+
+	header := make([]interface{}, len(domain.TicketExportColumns))
+	for i, column := range domain.TicketExportColumns {
+		header[i] = column
+	}
+	if err := stream.SetRow("A1", header); err != nil {
+		return err
+	}
+
+	rowNumber := 1
+	err = s.repo.StreamTicketExport(filter, func(row domain.TicketExportRow) error {
+		rowNumber++
+		values := row.Values()
+		for i, value := range values {
+			if t, isTime := value.(time.Time); isTime {
+				values[i] = excelize.Cell{StyleID: dateStyle, Value: t}
+			}
+		}
+		cell, err := excelize.CoordinatesToCellName(1, rowNumber)
+		if err != nil {
+			return err
+		}
+		return stream.SetRow(cell, values)
+	})
+	if err != nil {
+		return err
+	}
+
+	if err := stream.Flush(); err != nil {
+		return err
+	}
+	return file.Write(w)
+}
+
+// exportCellText formats an export cell for a text format
+func exportCellText(value interface{}) string {
+	if t, isTime := value.(time.Time); isTime {
+		return t.Format(time.RFC3339)
+	}
+	return fmt.Sprint(value)
+}
@@ -0,0 +1,472 @@
+package services
+
+import (
+	"app/domain"
+	"app/domain/models"
+	"encoding/csv"
+	"errors"
+	"fmt"
+	"io"
+	"strings"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+// ticketImportDateLayouts are the date formats accepted in an import, dates without a zone
+// are read in the server time zone
+var ticketImportDateLayouts = []string{
+	time.RFC3339,
+	"2006-01-02 15:04:05",
+	"2006-01-02 15:04",
+	"2006-01-02",
+	"02/01/2006 15:04:05",
+	"02/01/2006 15:04",
+	"02/01/2006",
+}
+
+// ticketImportTypes are the tipe_pengaduan values a ticket can be stored with
+var ticketImportTypes = map[models.UserRole]bool{
+	models.RoleAdmin:    true,
+	models.RoleSeller:   true,
+	models.RoleCustomer: true,
+}
+
+// ImportTickets creates tickets with their comments and assignment from a CSV export of a
+// legacy helpdesk, keeping the original dates. Rows sharing a kode_tiket belong to one
+// ticket: the first row holds the ticket, every row may add a comment. Categories,
+// priorities and statuses are resolved by name, users by username or email. The workflow,
+// SLA policies and custom field definitions do not apply to imported tickets.
+//
+// Every row is validated first, the tickets are only created when no row has an error and
+// dryRun is not set. They are created in a single transaction.
+func (s *appService) ImportTickets(file io.Reader, mapping domain.TicketImportMapping, dryRun bool, claim models.User) (*domain.TicketImportResult, error) {
+	rows, err := readTicketImportRows(file, mapping)
+	if err != nil {
+		return nil, err
+	}
+
+	importer, err := s.newTicketImporter()
+	if err != nil {
+		return nil, err
+	}
+	tickets := importer.validate(rows)
+
+	result := &domain.TicketImportResult{
+		DryRun: dryRun,
+		Rows:   len(rows),
+		Errors: importer.errors,
+	}
+	if result.Errors == nil {
+		result.Errors = []domain.TicketImportRowError{}
+	}
+	for _, ticket := range tickets {
+		result.Tickets++
+		result.Comments += len(ticket.comments)
+		if ticket.assignee != nil {
+			result.Assignments++
+		}
+	}
+	if dryRun || len(result.Errors) > 0 {
+		return result, nil
+	}
+
+	err = s.withTransaction(func(tx *appService) error {
+		// The codes of the file are reserved first, so no ticket without one is given a
+		// code that a later row brings along
+		for _, ticket := range tickets {
+			if ticket.ticket.KodeTiket == "" {
+				continue
+			}
+			if err := tx.reserveTicketCode(ticket.ticket); err != nil {
+				return fmt.Errorf("row %d: %w", ticket.row, err)
+			}
+		}
+		for _, ticket := range tickets {
+			if err := tx.importTicket(ticket, claim); err != nil {
+				return fmt.Errorf("row %d: %w", ticket.row, err)
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return result, nil
+}
+
+// importTicket stores a validated ticket with its comments and assignment
+func (s *appService) importTicket(imported *importedTicket, claim models.User) error {
+	ticket := imported.ticket
+	if ticket.KodeTiket == "" {
+		code, err := s.nextTicketCode(ticket)
+		if err != nil {
+			return err
+		}
+		ticket.KodeTiket = code
+	}
+	if err := s.repo.CreateTicket(ticket); err != nil {
+		return err
+	}
+
+	for _, comment := range imported.comments {
+		comment.TicketID = ticket.ID
+		if err := s.repo.CreateTicketComment(comment); err != nil {
+			return err
+		}
+	}
+
+	if imported.assignee != nil {
+		if err := s.repo.CreateTicketAssignment(&models.TicketAssignment{
+			TicketID:          ticket.ID,
+			AdminID:           int(imported.assignee.ID),
+			TanggalDitugaskan: ticket.TanggalDibuat,
+		}); err != nil {
+			return err
+		}
+		if err := s.addTicketWatcher(ticket.ID, imported.assignee.ID); err != nil {
+			return err
+		}
+	}
+
+	return s.repo.CreateTicketLog(newTicketLog(ticket.ID, claim, models.TicketLogTicketImported, fmt.Sprintf("Ticket %s imported from CSV row %d", ticket.KodeTiket, imported.row)))
+}
+
+// ticketImportRow is a CSV row keyed by the field its columns are mapped to
+type ticketImportRow struct {
+	line   int
+	values map[domain.TicketImportField]string
+}
+
+func (row ticketImportRow) get(field domain.TicketImportField) string {
+	return strings.TrimSpace(row.values[field])
+}
+
+// readTicketImportRows reads the CSV and maps its columns to the import fields. A mapping
+// or a file that cannot be read is a validation error.
+func readTicketImportRows(file io.Reader, mapping domain.TicketImportMapping) ([]ticketImportRow, error) {
+	var validation domain.ValidationError
+
+	known := make(map[domain.TicketImportField]bool, len(domain.TicketImportFields))
+	for _, field := range domain.TicketImportFields {
+		known[field] = true
+	}
+	for field := range mapping {
+		if !known[field] {
+			validation.Add("mapping."+string(field), "unknown ticket field")
+		}
+	}
+	if err := validation.OrNil(); err != nil {
+		return nil, err
+	}
+
+	reader := csv.NewReader(file)
+	reader.TrimLeadingSpace = true
+	header, err := reader.Read()
+	if err != nil {
+		validation.Add("file", fmt.Sprintf("cannot read the CSV header: %v", err))
+		return nil, validation.OrNil()
+	}
+
+	columns := make(map[string]int, len(header))
+	for i, name := range header {
+		if i == 0 {
+			name = strings.TrimPrefix(name, "\ufeff") // byte order mark written by spreadsheet apps
+		}
+		columns[strings.ToLower(strings.TrimSpace(name))] = i
+	}
+
+	indexes := make(map[domain.TicketImportField]int)
+	for _, field := range domain.TicketImportFields {
+		column, mapped := mapping[field]
+		if !mapped {
+			column = string(field)
+		}
+		if index, found := columns[strings.ToLower(strings.TrimSpace(column))]; found {
+			indexes[field] = index
+		} else if mapped {
+			validation.Add("mapping."+string(field), fmt.Sprintf("column %q not found in the CSV header", column))
+		}
+	}
+	for _, field := range domain.RequiredTicketImportFields {
+		if _, found := indexes[field]; !found {
+			validation.Add("mapping."+string(field), "is required, map it to a column")
+		}
+	}
+	if err := validation.OrNil(); err != nil {
+		return nil, err
+	}
+
+	var rows []ticketImportRow
+	for line := 2; ; line++ {
+		record, err := reader.Read()
+		if errors.Is(err, io.EOF) {
+			break
+		}
+		if err != nil {
+			validation.Add("file", fmt.Sprintf("cannot read row %d: %v", line, err))
+			return nil, validation.OrNil()
+		}
+
+		row := ticketImportRow{line: line, values: make(map[domain.TicketImportField]string, len(indexes))}
+		for field, index := range indexes {
+			row.values[field] = record[index]
+		}
+		rows = append(rows, row)
+	}
+	if len(rows) == 0 {
+		validation.Add("file", "has no rows")
+		return nil, validation.OrNil()
+	}
+	return rows, nil
+}
+
+// importedTicket is a validated ticket ready to be stored
+type importedTicket struct {
+	row      int
+	ticket   *models.Ticket
+	assignee *models.User
+	comments []*models.TicketComment
+}
+
+// ticketImporter validates import rows, looking every name up once
+type ticketImporter struct {
+	repo       domain.AppRepository
+	categories map[string]int
+	priorities map[string]int
+	statuses   map[string]int
+	users      map[string]*models.User
+	errors     []domain.TicketImportRowError
+}
+
+func (s *appService) newTicketImporter() (*ticketImporter, error) {
+	importer := &ticketImporter{
+		repo:       s.repo,
+		categories: make(map[string]int),
+		priorities: make(map[string]int),
+		statuses:   make(map[string]int),
+		users:      make(map[string]*models.User),
+	}
+
+	categories, err := s.repo.GetTicketCategories()
+	if err != nil {
+		return nil, err
+	}
+	for _, category := range categories {
+		importer.categories[strings.ToLower(category.NamaCategory)] = category.ID
+	}
+	priorities, err := s.repo.GetTicketPriorities()
+	if err != nil {
+		return nil, err
+	}
+	for _, priority := range priorities {
+		importer.priorities[strings.ToLower(priority.NamaPriority)] = priority.ID
+	}
+	statuses, err := s.repo.GetTicketStatuses()
+	if err != nil {
+		return nil, err
+	}
+	for _, status := range statuses {
+		importer.statuses[strings.ToLower(status.NamaStatus)] = status.ID
+	}
+	return importer, nil
+}
+
+func (im *ticketImporter) fail(row int, field domain.TicketImportField, format string, args ...interface{}) {
+	im.errors = append(im.errors, domain.TicketImportRowError{Row: row, Field: string(field), Message: fmt.Sprintf(format, args...)})
+}
+
+// validate groups the rows into tickets and reports every problem found
+func (im *ticketImporter) validate(rows []ticketImportRow) []*importedTicket {
+	var tickets []*importedTicket
+	byCode := make(map[string]*importedTicket)
+
+	for _, row := range rows {
+		code := strings.ToUpper(row.get(domain.TicketImportKodeTiket))
+		imported, seen := byCode[code]
+		if code == "" || !seen {
+			imported = im.validateTicket(row)
+			if imported == nil {
+				// Keep later rows of the ticket from being reported as a new ticket
+				if code != "" {
+					byCode[code] = &importedTicket{row: row.line}
+				}
+				continue
+			}
+			tickets = append(tickets, imported)
+			if code != "" {
+				byCode[code] = imported
+			}
+		}
+		if imported.ticket == nil {
+			continue
+		}
+
+		if comment := im.validateComment(row, imported.ticket); comment != nil {
+			imported.comments = append(imported.comments, comment)
+		}
+	}
+
+	return tickets
+}
+
+// validateTicket builds the ticket of the first row of a ticket, nil when the row is invalid
+func (im *ticketImporter) validateTicket(row ticketImportRow) *importedTicket {
+	failed := len(im.errors)
+	ticket := &models.Ticket{
+		KodeTiket: row.get(domain.TicketImportKodeTiket),
+		Judul:     row.get(domain.TicketImportJudul),
+		Deskripsi: row.get(domain.TicketImportDeskripsi),
+	}
+
+	if ticket.KodeTiket != "" {
+		if _, err := im.repo.GetTicketByCode(ticket.KodeTiket); err == nil {
+			im.fail(row.line, domain.TicketImportKodeTiket, "ticket %s already exists", ticket.KodeTiket)
+		}
+	}
+	if ticket.Judul == "" {
+		im.fail(row.line, domain.TicketImportJudul, "is required")
+	}
+
+	owner := im.user(row, domain.TicketImportUser, true)
+	if owner != nil {
+		ticket.UserID = owner.ID
+	}
+
+	ticket.CategoryID = im.lookup(row, domain.TicketImportCategory, im.categories)
+	ticket.PriorityID = im.lookup(row, domain.TicketImportPriority, im.priorities)
+	ticket.StatusID = im.lookup(row, domain.TicketImportStatus, im.statuses)
+
+	if tipe := row.get(domain.TicketImportTipePengaduan); tipe != "" {
+		ticket.TipePengaduan = models.UserRole(strings.ToLower(tipe))
+		if !ticketImportTypes[ticket.TipePengaduan] {
+			im.fail(row.line, domain.TicketImportTipePengaduan, "must be admin, seller or customer")
+		}
+	} else if owner != nil {
+		ticket.TipePengaduan = owner.Role
+		if !ticketImportTypes[ticket.TipePengaduan] {
+			im.fail(row.line, domain.TicketImportTipePengaduan, "is required for a user with role %s", owner.Role)
+		}
+	}
+
+	if createdAt, ok := im.date(row, domain.TicketImportTanggalDibuat, true); ok {
+		ticket.TanggalDibuat = createdAt
+		ticket.TanggalDiperbarui = createdAt
+	}
+	if updatedAt, ok := im.date(row, domain.TicketImportTanggalDiperbarui, false); ok && !updatedAt.IsZero() {
+		if updatedAt.Before(ticket.TanggalDibuat) {
+			im.fail(row.line, domain.TicketImportTanggalDiperbarui, "is before tanggal_dibuat")
+		}
+		ticket.TanggalDiperbarui = updatedAt
+	}
+	statusChangedAt := ticket.TanggalDiperbarui
+	ticket.StatusChangedAt = &statusChangedAt
+
+	var assignee *models.User
+	if row.get(domain.TicketImportAssignee) != "" {
+		assignee = im.user(row, domain.TicketImportAssignee, false)
+		if assignee != nil && assignee.Role != models.RoleSupport {
+			im.fail(row.line, domain.TicketImportAssignee, "only users with support role can be assigned to tickets")
+		}
+	}
+
+	if len(im.errors) > failed {
+		return nil
+	}
+	return &importedTicket{row: row.line, ticket: ticket, assignee: assignee}
+}
+
+// validateComment builds the comment of a row, nil when the row has none or it is invalid.
+// Comments are written by the ticket owner on the ticket creation date unless told otherwise.
+func (im *ticketImporter) validateComment(row ticketImportRow, ticket *models.Ticket) *models.TicketComment {
+	text := row.get(domain.TicketImportComment)
+	if text == "" {
+		if row.get(domain.TicketImportCommentUser) != "" || row.get(domain.TicketImportCommentDate) != "" {
+			im.fail(row.line, domain.TicketImportComment, "is required with comment_user or comment_date")
+		}
+		return nil
+	}
+
+	comment := &models.TicketComment{
+		UserID:        int(ticket.UserID),
+		IsiPesan:      text,
+		TanggalDibuat: ticket.TanggalDibuat,
+	}
+	if row.get(domain.TicketImportCommentUser) != "" {
+		author := im.user(row, domain.TicketImportCommentUser, false)
+		if author == nil {
+			return nil
+		}
+		comment.UserID = int(author.ID)
+	}
+	date, ok := im.date(row, domain.TicketImportCommentDate, false)
+	if !ok {
+		return nil
+	}
+	if !date.IsZero() {
+		comment.TanggalDibuat = date
+	}
+	return comment
+}
+
+// lookup resolves the name in the field, 0 when it is missing or unknown
+func (im *ticketImporter) lookup(row ticketImportRow, field domain.TicketImportField, ids map[string]int) int {
+	name := row.get(field)
+	if name == "" {
+		im.fail(row.line, field, "is required")
+		return 0
+	}
+	id, found := ids[strings.ToLower(name)]
+	if !found {
+		im.fail(row.line, field, "%q not found", name)
+	}
+	return id
+}
+
+// user resolves the username or email in the field, nil when it is missing or unknown
+func (im *ticketImporter) user(row ticketImportRow, field domain.TicketImportField, required bool) *models.User {
+	login := row.get(field)
+	if login == "" {
+		if required {
+			im.fail(row.line, field, "is required")
+		}
+		return nil
+	}
+
+	key := strings.ToLower(login)
+	user, cached := im.users[key]
+	if !cached {
+		found, err := im.repo.GetUserByUsernameOrEmail(login)
+		if err != nil && !errors.Is(err, gorm.ErrRecordNotFound) {
+			im.fail(row.line, field, "cannot look up user %q: %v", login, err)
+			return nil
+		}
+		user = found
+		im.users[key] = user
+	}
+	if user == nil {
+		im.fail(row.line, field, "user %q not found", login)
+	}
+	return user
+}
+
+// date parses the date in the field, a missing optional date is the zero time. ok is false
+// when the date is invalid or a required one is missing.
+func (im *ticketImporter) date(row ticketImportRow, field domain.TicketImportField, required bool) (time.Time, bool) {
+	value := row.get(field)
+	if value == "" {
+		if required {
+			im.fail(row.line, field, "is required")
+			return time.Time{}, false
+		}
+		return time.Time{}, true
+	}
+
+	for _, layout := range ticketImportDateLayouts {
+		if date, err := time.ParseInLocation(layout, value, time.Local); err == nil {
+			return date, true
+		}
+	}
+	im.fail(row.line, field, "invalid date %q, use YYYY-MM-DD HH:MM or DD/MM/YYYY HH:MM", value)
+	return time.Time{}, false
+}
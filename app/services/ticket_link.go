@@ -0,0 +1,176 @@
+package services
+
+import (
+	"app/domain"
+	"app/domain/models"
+	"fmt"
+	"log"
+	"time"
+)
+
+// maxTicketHierarchyDepth bounds the parent chain walked when checking for cycles
+const maxTicketHierarchyDepth = 50
+
+// CreateTicketLink links ticketID to otherTicketID. The link type is what otherTicketID is
+// to ticketID, e.g. LinkTypeChild makes otherTicketID a child of ticketID.
+func (s *appService) CreateTicketLink(ticketID, otherTicketID int, linkType models.TicketLinkType, claim models.User) (*models.TicketLink, error) {
+	if ticketID == otherTicketID {
+		return nil, fmt.Errorf("%w: a ticket cannot be linked to itself", domain.ErrInvalidTicketLink)
+	}
+
+	ticket, err := s.repo.GetTicketByID(ticketID)
+	if err != nil {
+		return nil, fmt.Errorf("%w: ticket %d", domain.ErrTicketNotFound, ticketID)
+	}
+	other, err := s.repo.GetTicketByID(otherTicketID)
+	if err != nil {
+		return nil, fmt.Errorf("%w: ticket %d", domain.ErrTicketNotFound, otherTicketID)
+	}
+
+	// Store the reversed relations in their canonical direction
+	link := &models.TicketLink{SourceTicketID: ticket.ID, TargetTicketID: other.ID, LinkType: linkType}
+	switch linkType {
+	case models.LinkTypeChild:
+		link.LinkType = models.LinkTypeParent
+	case models.LinkTypeParent:
+		link.SourceTicketID, link.TargetTicketID = other.ID, ticket.ID
+	case models.LinkTypeBlocks:
+		link.SourceTicketID, link.TargetTicketID = other.ID, ticket.ID
+	case models.LinkTypeBlockedBy:
+		link.LinkType = models.LinkTypeBlocks
+	case models.LinkTypeRelated:
+	default:
+		return nil, fmt.Errorf("%w: unknown link type %q", domain.ErrInvalidTicketLink, linkType)
+	}
+
+	existing, err := s.repo.GetTicketLinkBetween(ticket.ID, other.ID)
+	if err != nil {
+		return nil, err
+	}
+	if existing != nil {
+		return nil, fmt.Errorf("%w as %s (link %d)", domain.ErrTicketLinkExists, existing.LinkType, existing.ID)
+	}
+
+	if link.LinkType == models.LinkTypeParent {
+		if err := s.validateTicketParent(link.SourceTicketID, link.TargetTicketID); err != nil {
+			return nil, err
+		}
+	}
+
+	if claim.ID != 0 {
+		createdBy := int(claim.ID)
+		link.CreatedBy = &createdBy
+	}
+	link.CreatedAt = time.Now()
+	if err := s.repo.CreateTicketLink(link); err != nil {
+		return nil, err
+	}
+
+	if err := s.recordTicketLog(ticket.ID, claim, models.TicketLogLinkAdded, fmt.Sprintf("Linked %s as %s", other.KodeTiket, linkType)); err != nil {
+		return nil, err
+	}
+	if err := s.recordTicketLog(other.ID, claim, models.TicketLogLinkAdded, fmt.Sprintf("Linked %s as %s", ticket.KodeTiket, reverseTicketLinkType(linkType))); err != nil {
+		return nil, err
+	}
+	return link, nil
+}
+
+// validateTicketParent makes sure a child keeps a single parent and that the hierarchy has no cycles
+func (s *appService) validateTicketParent(parentID, childID int) error {
+	currentParent, err := s.repo.GetParentTicketID(childID)
+	if err != nil {
+		return err
+	}
+	if currentParent != nil {
+		return fmt.Errorf("%w: ticket %d already has parent ticket %d", domain.ErrTicketLinkExists, childID, *currentParent)
+	}
+
+	ancestorID := parentID
+	for depth := 0; depth < maxTicketHierarchyDepth; depth++ {
+		ancestor, err := s.repo.GetParentTicketID(ancestorID)
+		if err != nil {
+			return err
+		}
+		if ancestor == nil {
+			return nil
+		}
+		if *ancestor == childID {
+			return fmt.Errorf("%w: ticket %d is an ancestor of ticket %d", domain.ErrInvalidTicketLink, childID, parentID)
+		}
+		ancestorID = *ancestor
+	}
+	return fmt.Errorf("%w: ticket hierarchy is deeper than %d levels", domain.ErrInvalidTicketLink, maxTicketHierarchyDepth)
+}
+
+func (s *appService) GetTicketLinks(ticketID int) ([]models.TicketLink, error) {
+	return s.repo.GetTicketLinksByTicketID(ticketID)
+}
+
+func (s *appService) DeleteTicketLink(ticketID, linkID int, claim models.User) error {
+	link, err := s.repo.GetTicketLinkByID(linkID)
+	if err != nil || (link.SourceTicketID != ticketID && link.TargetTicketID != ticketID) {
+		return domain.ErrTicketLinkNotFound
+	}
+
+	if err := s.repo.DeleteTicketLink(link.ID); err != nil {
+		return err
+	}
+
+	if err := s.recordTicketLog(link.SourceTicketID, claim, models.TicketLogLinkRemoved, fmt.Sprintf("Removed %s link to ticket %d", reverseTicketLinkType(link.LinkType), link.TargetTicketID)); err != nil {
+		return err
+	}
+	return s.recordTicketLog(link.TargetTicketID, claim, models.TicketLogLinkRemoved, fmt.Sprintf("Removed %s link to ticket %d", link.LinkType, link.SourceTicketID))
+}
+
+// cascadeTicketComment posts the comment of a parent ticket on each open child ticket through
+// CreateTicketComment, so every child moves along its own comment transition. Children the
+// author may not access are skipped.
+func (s *appService) cascadeTicketComment(parent *models.Ticket, comment *models.TicketComment, author models.User) {
+	children, err := s.repo.GetChildTickets(parent.ID)
+	if err != nil {
+		log.Printf("Failed to load child tickets of ticket #%s: %v", parent.KodeTiket, err)
+		return
+	}
+
+	cascaded := 0
+	for _, child := range children {
+		if child.MergedIntoID != nil || (child.Status != nil && child.Status.IsTerminal) {
+			continue
+		}
+		if !s.canAccessTicket(&child, author) {
+			log.Printf("Skipped cascading comment from ticket #%s to ticket #%s, user %d may not access it", parent.KodeTiket, child.KodeTiket, author.ID)
+			continue
+		}
+		childComment := models.TicketComment{
+			TicketID:      child.ID,
+			UserID:        comment.UserID,
+			IsiPesan:      comment.IsiPesan,
+			TanggalDibuat: comment.TanggalDibuat,
+		}
+		if err := s.CreateTicketComment(&childComment); err != nil {
+			log.Printf("Failed to cascade comment from ticket #%s to ticket #%s: %v", parent.KodeTiket, child.KodeTiket, err)
+			continue
+		}
+		cascaded++
+	}
+
+	if cascaded > 0 {
+		s.recordTicketLog(parent.ID, author, models.TicketLogCommentCreated, fmt.Sprintf("Comment cascaded to %d child tickets", cascaded))
+	}
+}
+
+// reverseTicketLinkType describes a link type from the other ticket's side
+func reverseTicketLinkType(linkType models.TicketLinkType) models.TicketLinkType {
+	switch linkType {
+	case models.LinkTypeParent:
+		return models.LinkTypeChild
+	case models.LinkTypeChild:
+		return models.LinkTypeParent
+	case models.LinkTypeBlocks:
+		return models.LinkTypeBlockedBy
+	case models.LinkTypeBlockedBy:
+		return models.LinkTypeBlocks
+	default:
+		return linkType
+	}
+}
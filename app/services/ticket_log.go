@@ -1,9 +1,31 @@
 package services
 
-import "app/domain/models"
+import (
+	"app/domain"
+	"app/domain/models"
+	"fmt"
+	"log"
+	"strings"
+	"time"
+)
 
-func (s *appService) CreateTicketLog(log *models.TicketLog) error {
-	return s.repo.CreateTicketLog(log)
+// CreateTicketLog adds a note to the log of a ticket. The entries of the mutations are
+// written by the services themselves and cannot be created by hand.
+func (s *appService) CreateTicketLog(ticketID int, aktivitas string, claim models.User) (*models.TicketLog, error) {
+	aktivitas = strings.TrimSpace(aktivitas)
+	if aktivitas == "" {
+		return nil, &domain.ValidationError{Fields: map[string]string{"aktivitas": "is required"}}
+	}
+	if _, err := s.repo.GetTicketByID(ticketID); err != nil {
+		return nil, fmt.Errorf("%w: ticket %d", domain.ErrTicketNotFound, ticketID)
+	}
+
+	entry := newTicketLog(ticketID, claim, models.TicketLogNote, aktivitas)
+	entry.IsSystem = false
+	if err := s.repo.CreateTicketLog(entry); err != nil {
+		return nil, err
+	}
+	return entry, nil
 }
 
 func (s *appService) GetTicketLogs() ([]models.TicketLog, error) {
@@ -16,4 +38,84 @@ func (s *appService) GetTicketLogByID(id int) (*models.TicketLog, error) {
 
 func (s *appService) GetTicketLogsByTicketID(ticketID int) ([]models.TicketLog, error) {
 	return s.repo.GetTicketLogsByTicketID(ticketID)
-}
\ No newline at end of file
+}
+
+// newTicketLog builds a system entry for a ticket. A zero actor records the entry as
+// written by a background job.
+func newTicketLog(ticketID int, actor models.User, action models.TicketLogAction, aktivitas string) *models.TicketLog {
+	entry := &models.TicketLog{
+		TicketID:  ticketID,
+		Aktivitas: aktivitas,
+		Action:    action,
+		IsSystem:  true,
+		Waktu:     time.Now(),
+	}
+	if actor.ID != 0 {
+		userID := int(actor.ID)
+		entry.UserID = &userID
+	}
+	return entry
+}
+
+// recordTicketLog writes an activity entry for a ticket, see writeTicketLog for the errors
+// it returns
+func (s *appService) recordTicketLog(ticketID int, actor models.User, action models.TicketLogAction, aktivitas string) error {
+	return s.writeTicketLog(newTicketLog(ticketID, actor, action, aktivitas))
+}
+
+// recordTicketChanges writes one entry per changed field with its old and new value, the
+// subject names the changed record in the activity text, e.g. "Comment 12"
+func (s *appService) recordTicketChanges(ticketID int, actor models.User, action models.TicketLogAction, subject string, changes []fieldChange) error {
+	for _, change := range changes {
+		entry := newTicketLog(ticketID, actor, action, fmt.Sprintf("%s: %s changed from %s to %s", subject, change.Field, change.Old, change.New))
+		entry.Field = change.Field
+		entry.OldValue = change.Old
+		entry.NewValue = change.New
+		if err := s.writeTicketLog(entry); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// recordStatusChange writes the status change of a ticket that was persisted, nothing is
+// written when the status stayed the same
+func (s *appService) recordStatusChange(ticket *models.Ticket, fromStatus string, actor models.User) error {
+	toStatus := ticketStatusName(ticket)
+	if fromStatus == toStatus {
+		return nil
+	}
+	entry := newTicketLog(ticket.ID, actor, models.TicketLogStatusChanged, fmt.Sprintf("Status changed from %s to %s", fromStatus, toStatus))
+	entry.Field = "status"
+	entry.OldValue = fromStatus
+	entry.NewValue = toStatus
+	return s.writeTicketLog(entry)
+}
+
+// writeTicketLog stores an entry. Inside a transaction a failure is returned so the change
+// it describes is rolled back with it, outside one the change is already stored and the
+// failure is only logged so it never breaks the mutation.
+func (s *appService) writeTicketLog(entry *models.TicketLog) error {
+	err := s.repo.CreateTicketLog(entry)
+	if err == nil {
+		return nil
+	}
+	if s.afterCommit != nil {
+		return fmt.Errorf("failed to record %q for ticket %d: %w", entry.Aktivitas, entry.TicketID, err)
+	}
+	log.Printf("[ticket-log] failed to record %q for ticket %d: %v", entry.Aktivitas, entry.TicketID, err)
+	return nil
+}
+
+// recordTicketUpdate writes the ticket fields and status changed since before was taken,
+// for mutations that persist the ticket as a side effect like assignments and comments
+func (s *appService) recordTicketUpdate(ticket *models.Ticket, before ticketAuditFields, fromStatus string, actor models.User) error {
+	changes, err := diffFields(before, newTicketAuditFields(ticket))
+	if err != nil {
+		log.Printf("[ticket-log] failed to diff ticket %d: %v", ticket.ID, err)
+	}
+	if err := s.recordTicketChanges(ticket.ID, actor, models.TicketLogTicketUpdated, "Ticket", changes); err != nil {
+		return err
+	}
+	return s.recordStatusChange(ticket, fromStatus, actor)
+}
@@ -0,0 +1,73 @@
+package services
+
+import (
+	"app/domain"
+	"app/domain/models"
+	"fmt"
+	"time"
+)
+
+// MergeTickets merges a duplicate ticket into the primary ticket. Comments, attachments,
+// assignments and logs move to the primary and the duplicate is closed through the
+// merge workflow event with a reference to the primary.
+func (s *appService) MergeTickets(primaryID, secondaryID int, claim models.User) (*models.Ticket, error) {
+	if primaryID == secondaryID {
+		return nil, domain.ErrTicketMergeSelf
+	}
+
+	var primary, secondary *models.Ticket
+	var fromStatus string
+	err := s.withTransaction(func(tx *appService) error {
+		var err error
+		if primary, err = tx.repo.GetTicketByID(primaryID); err != nil {
+			return fmt.Errorf("%w: primary ticket %d", domain.ErrTicketNotFound, primaryID)
+		}
+		if secondary, err = tx.repo.GetTicketByID(secondaryID); err != nil {
+			return fmt.Errorf("%w: secondary ticket %d", domain.ErrTicketNotFound, secondaryID)
+		}
+		if primary.MergedIntoID != nil {
+			return fmt.Errorf("%w: merge into ticket %d instead", domain.ErrTicketAlreadyMerged, *primary.MergedIntoID)
+		}
+		if secondary.MergedIntoID != nil {
+			return domain.ErrTicketAlreadyMerged
+		}
+
+		if err := tx.repo.MoveTicketRecords(secondary.ID, primary.ID); err != nil {
+			return err
+		}
+
+		// A duplicate that is already closed keeps its status
+		fromStatus = ticketStatusName(secondary)
+		if secondary.Status == nil || !secondary.Status.IsTerminal {
+			if err := tx.applyTicketEvent(secondary, models.WorkflowEventMerge, claim); err != nil {
+				return err
+			}
+		}
+
+		now := time.Now()
+		secondary.MergedIntoID = &primary.ID
+		secondary.TanggalDiperbarui = now
+		if err := tx.repo.UpdateTicket(secondary); err != nil {
+			return err
+		}
+
+		primary.TanggalDiperbarui = now
+		return tx.repo.UpdateTicket(primary)
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	if err := s.recordTicketLog(secondary.ID, claim, models.TicketLogTicketMerged, fmt.Sprintf("Ticket merged into %s", primary.KodeTiket)); err != nil {
+		return nil, err
+	}
+	if err := s.recordStatusChange(secondary, fromStatus, claim); err != nil {
+		return nil, err
+	}
+	if err := s.recordTicketLog(primary.ID, claim, models.TicketLogTicketMerged, fmt.Sprintf("Ticket %s merged into this ticket", secondary.KodeTiket)); err != nil {
+		return nil, err
+	}
+	s.notifyTicketWatchers(secondary, TicketNotificationStatusChange, claim, fmt.Sprintf("Tiket digabungkan ke tiket %s", primary.KodeTiket))
+
+	return s.GetTicketByID(primary.ID)
+}
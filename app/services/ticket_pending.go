@@ -0,0 +1,132 @@
+package services
+
+import (
+	"app/domain"
+	"app/domain/models"
+	"fmt"
+	"log"
+	"strings"
+	"time"
+)
+
+// SnoozeTicket parks a ticket in the pending status through the snooze workflow event while
+// support waits on someone else, e.g. the courier or the seller. The ticket leaves the
+// default queues and wakes up at until, or earlier when the customer comments.
+func (s *appService) SnoozeTicket(id int, until time.Time, reason string, claim models.User) (*models.Ticket, error) {
+	var validation domain.ValidationError
+	reason = strings.TrimSpace(reason)
+	if reason == "" {
+		validation.Add("reason", "is required")
+	}
+	if !until.After(time.Now()) {
+		validation.Add("pending_until", "must be in the future")
+	}
+	if err := validation.OrNil(); err != nil {
+		return nil, err
+	}
+
+	ticket, err := s.repo.GetTicketByID(id)
+	if err != nil {
+		return nil, fmt.Errorf("%w: ticket %d", domain.ErrTicketNotFound, id)
+	}
+	if ticket.MergedIntoID != nil {
+		return nil, fmt.Errorf("%w: snooze ticket %d instead", domain.ErrTicketAlreadyMerged, *ticket.MergedIntoID)
+	}
+
+	fromStatus := ticketStatusName(ticket)
+	if err := s.applyTicketEvent(ticket, models.WorkflowEventSnooze, claim); err != nil {
+		return nil, err
+	}
+	if !ticketIsPending(ticket) {
+		return nil, fmt.Errorf("%w: the snooze transition has to lead to a pending status", domain.ErrInvalidStatusTransition)
+	}
+	ticket.PendingUntil = &until
+	ticket.PendingReason = reason
+
+	if err := s.repo.UpdateTicket(ticket); err != nil {
+		return nil, err
+	}
+
+	if err := s.recordStatusChange(ticket, fromStatus, claim); err != nil {
+		return nil, err
+	}
+	entry := newTicketLog(ticket.ID, claim, models.TicketLogTicketSnoozed, fmt.Sprintf("Ticket snoozed until %s. Reason: %s", until.Format(time.RFC3339), reason))
+	entry.Field = "pending_until"
+	entry.NewValue = until.Format(time.RFC3339)
+	if err := s.writeTicketLog(entry); err != nil {
+		return nil, err
+	}
+
+	return s.GetTicketByID(ticket.ID)
+}
+
+// WakeTicket ends the snooze of a pending ticket before its timer expires
+func (s *appService) WakeTicket(id int, claim models.User) (*models.Ticket, error) {
+	ticket, err := s.repo.GetTicketByID(id)
+	if err != nil {
+		return nil, fmt.Errorf("%w: ticket %d", domain.ErrTicketNotFound, id)
+	}
+	if !ticketIsPending(ticket) {
+		return nil, fmt.Errorf("%w: ticket %s is not pending", domain.ErrInvalidStatusTransition, ticket.KodeTiket)
+	}
+
+	if err := s.wakeTicket(ticket, claim, fmt.Sprintf("Ticket woken up by %s", claim.Username)); err != nil {
+		return nil, err
+	}
+	return s.GetTicketByID(ticket.ID)
+}
+
+// WakePendingTickets moves every pending ticket whose timer expired back through the wake
+// workflow event. A ticket failing to wake is retried on the next run.
+func (s *appService) WakePendingTickets() error {
+	tickets, err := s.repo.GetTicketsToWake(time.Now())
+	if err != nil {
+		return err
+	}
+
+	for i := range tickets {
+		ticket := &tickets[i]
+		if err := s.wakeTicket(ticket, models.SystemUser, "Snooze timer expired"); err != nil {
+			log.Printf("[pending] failed to wake ticket #%s: %v", ticket.KodeTiket, err)
+		}
+	}
+	return nil
+}
+
+// wakeTicket applies the wake event as actor, models.SystemUser for the scheduler, and tells
+// the assignee the ticket is back in their queue
+func (s *appService) wakeTicket(ticket *models.Ticket, actor models.User, aktivitas string) error {
+	fromStatus := ticketStatusName(ticket)
+	pendingUntil := ticket.PendingUntil
+	if err := s.applyTicketEvent(ticket, models.WorkflowEventWake, actor); err != nil {
+		return err
+	}
+	if err := s.repo.UpdateTicket(ticket); err != nil {
+		return err
+	}
+
+	return s.recordTicketWoken(ticket, fromStatus, pendingUntil, actor, aktivitas)
+}
+
+// recordTicketWoken logs a ticket that left the pending status and notifies its assignee
+func (s *appService) recordTicketWoken(ticket *models.Ticket, fromStatus string, pendingUntil *time.Time, actor models.User, aktivitas string) error {
+	if err := s.recordStatusChange(ticket, fromStatus, actor); err != nil {
+		return err
+	}
+	entry := newTicketLog(ticket.ID, actor, models.TicketLogTicketWoken, aktivitas)
+	if pendingUntil != nil {
+		entry.Field = "pending_until"
+		entry.OldValue = pendingUntil.Format(time.RFC3339)
+	}
+	if err := s.writeTicketLog(entry); err != nil {
+		return err
+	}
+
+	s.notifyTicketAssignee(ticket, TicketNotificationWake, actor, fmt.Sprintf("Tiket %s aktif kembali: %s", ticket.KodeTiket, aktivitas))
+	return nil
+}
+
+// ticketIsPending tells whether the ticket waits in a pending status
+func ticketIsPending(ticket *models.Ticket) bool {
+	return ticket.Status != nil && ticket.Status.IsPending
+}
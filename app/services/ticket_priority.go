@@ -1,6 +1,12 @@
 package services
 
-import "app/domain/models"
+import (
+	"app/domain"
+	"app/domain/models"
+	"fmt"
+	"log"
+	"strings"
+)
 
 func (s *appService) CreateTicketPriority(priority *models.TicketPriority) error {
 	return s.repo.CreateTicketPriority(priority)
@@ -18,6 +24,33 @@ func (s *appService) UpdateTicketPriority(priority *models.TicketPriority) error
 	return s.repo.UpdateTicketPriority(priority)
 }
 
+// PatchTicketPriority applies a JSON merge patch to a priority
+func (s *appService) PatchTicketPriority(id int, patch []byte) (*models.TicketPriority, error) {
+	priority, err := s.repo.GetTicketPriorityByID(id)
+	if err != nil {
+		return nil, fmt.Errorf("%w: priority %d", domain.ErrRecordNotFound, id)
+	}
+
+	fields := struct {
+		NamaPriority string `json:"nama_priority"`
+	}{NamaPriority: priority.NamaPriority}
+	changes, err := applyMergePatch(&fields, patch)
+	if err != nil || len(changes) == 0 {
+		return priority, err
+	}
+	if strings.TrimSpace(fields.NamaPriority) == "" {
+		return nil, &domain.ValidationError{Fields: map[string]string{"nama_priority": "is required"}}
+	}
+
+	priority.NamaPriority = fields.NamaPriority
+	if err := s.repo.UpdateTicketPriority(priority); err != nil {
+		return nil, err
+	}
+
+	log.Printf("[ticket-priority] priority %d changed: %s", priority.ID, describeFieldChanges(changes))
+	return priority, nil
+}
+
 func (s *appService) DeleteTicketPriority(id int) error {
 	return s.repo.DeleteTicketPriority(id)
 }
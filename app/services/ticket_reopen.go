@@ -0,0 +1,64 @@
+package services
+
+import (
+	"app/domain"
+	"app/domain/models"
+	"errors"
+	"fmt"
+	"log"
+	"strings"
+
+	"gorm.io/gorm"
+)
+
+// ReopenTicket moves a resolved or closed ticket back to open through the reopen workflow
+// event when its problem came back. Only the owner can reopen a ticket, the reason is
+// logged and sent to the current assignee.
+func (s *appService) ReopenTicket(id int, reason string, claim models.User) (*models.Ticket, error) {
+	reason = strings.TrimSpace(reason)
+	if reason == "" {
+		return nil, &domain.ValidationError{Fields: map[string]string{"reason": "is required"}}
+	}
+
+	ticket, err := s.repo.GetTicketByID(id)
+	if err != nil {
+		return nil, fmt.Errorf("%w: ticket %d", domain.ErrTicketNotFound, id)
+	}
+	if ticket.UserID != claim.ID {
+		return nil, domain.ErrNotTicketOwner
+	}
+	if ticket.MergedIntoID != nil {
+		return nil, fmt.Errorf("%w: reopen ticket %d instead", domain.ErrTicketAlreadyMerged, *ticket.MergedIntoID)
+	}
+
+	fromStatus := ticketStatusName(ticket)
+	if err := s.applyTicketEvent(ticket, models.WorkflowEventReopen, claim); err != nil {
+		return nil, err
+	}
+	ticket.ReopenCount++
+
+	// The SLA clocks restart with the new status, like for any other status change
+	if err := s.repo.UpdateTicket(ticket); err != nil {
+		return nil, err
+	}
+
+	if err := s.recordStatusChange(ticket, fromStatus, claim); err != nil {
+		return nil, err
+	}
+	if err := s.recordTicketLog(ticket.ID, claim, models.TicketLogTicketReopened, fmt.Sprintf("Ticket reopened by customer (%d times). Reason: %s", ticket.ReopenCount, reason)); err != nil {
+		return nil, err
+	}
+
+	// The assignee hears about it even after they stopped watching the ticket
+	var assignee *models.User
+	assignment, err := s.repo.GetTicketAssignmentByTicketID(ticket.ID)
+	switch {
+	case err == nil:
+		assignee = assignment.Admin
+	case !errors.Is(err, gorm.ErrRecordNotFound):
+		log.Printf("Failed to load the assignee of ticket #%s: %v", ticket.KodeTiket, err)
+	}
+	s.notifyTicketWatchers(ticket, TicketNotificationReopen, claim, fmt.Sprintf("Tiket dibuka kembali oleh pelanggan (dibuka kembali %d kali). Alasan: %s", ticket.ReopenCount, reason), assignee)
+
+	return s.GetTicketByID(ticket.ID)
+}
@@ -1,6 +1,12 @@
 package services
 
-import "app/domain/models"
+import (
+	"app/domain"
+	"app/domain/models"
+	"fmt"
+	"log"
+	"strings"
+)
 
 func (s *appService) CreateTicketStatus(status *models.TicketStatus) error {
 	return s.repo.CreateTicketStatus(status)
@@ -18,6 +24,50 @@ func (s *appService) UpdateTicketStatus(status *models.TicketStatus) error {
 	return s.repo.UpdateTicketStatus(status)
 }
 
+// PatchTicketStatus applies a JSON merge patch to a status
+func (s *appService) PatchTicketStatus(id int, patch []byte) (*models.TicketStatus, error) {
+	status, err := s.repo.GetTicketStatusByID(id)
+	if err != nil {
+		return nil, fmt.Errorf("%w: status %d", domain.ErrRecordNotFound, id)
+	}
+
+	fields := struct {
+		NamaStatus string `json:"nama_status"`
+		IsInitial  bool   `json:"is_initial"`
+		IsTerminal bool   `json:"is_terminal"`
+		PausesSLA  bool   `json:"pauses_sla"`
+		IsResolved bool   `json:"is_resolved"`
+		IsPending  bool   `json:"is_pending"`
+	}{
+		NamaStatus: status.NamaStatus,
+		IsInitial:  status.IsInitial,
+		IsTerminal: status.IsTerminal,
+		PausesSLA:  status.PausesSLA,
+		IsResolved: status.IsResolved,
+		IsPending:  status.IsPending,
+	}
+	changes, err := applyMergePatch(&fields, patch)
+	if err != nil || len(changes) == 0 {
+		return status, err
+	}
+	if strings.TrimSpace(fields.NamaStatus) == "" {
+		return nil, &domain.ValidationError{Fields: map[string]string{"nama_status": "is required"}}
+	}
+
+	status.NamaStatus = fields.NamaStatus
+	status.IsInitial = fields.IsInitial
+	status.IsTerminal = fields.IsTerminal
+	status.PausesSLA = fields.PausesSLA
+	status.IsResolved = fields.IsResolved
+	status.IsPending = fields.IsPending
+	if err := s.repo.UpdateTicketStatus(status); err != nil {
+		return nil, err
+	}
+
+	log.Printf("[ticket-status] status %d changed: %s", status.ID, describeFieldChanges(changes))
+	return status, nil
+}
+
 func (s *appService) DeleteTicketStatus(id int) error {
 	return s.repo.DeleteTicketStatus(id)
 }
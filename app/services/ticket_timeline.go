@@ -0,0 +1,48 @@
+package services
+
+import (
+	"app/domain"
+	"app/domain/models"
+	"fmt"
+)
+
+// GetTicketTimeline returns the comments, logs, attachments, assignments and status changes
+// of a ticket as one stream, oldest first. Types narrows the stream to some event types,
+// the caller only ever gets the types visible to its role.
+func (s *appService) GetTicketTimeline(ticketID int, types []domain.TimelineEventType, limit int, cursor string, claim models.User) ([]domain.TimelineEvent, string, error) {
+	if _, err := s.repo.GetTicketByID(ticketID); err != nil {
+		return nil, "", fmt.Errorf("%w: ticket %d", domain.ErrTicketNotFound, ticketID)
+	}
+
+	visible := domain.VisibleTimelineEventTypes(claim.Role)
+	if len(types) > 0 {
+		var validation domain.ValidationError
+		requested := make([]domain.TimelineEventType, 0, len(types))
+		for _, eventType := range types {
+			switch {
+			case !containsTimelineEventType(domain.TimelineEventTypes, eventType):
+				validation.Add("type", fmt.Sprintf("unknown event type %q", eventType))
+			case containsTimelineEventType(visible, eventType):
+				requested = append(requested, eventType)
+			}
+		}
+		if err := validation.OrNil(); err != nil {
+			return nil, "", err
+		}
+		visible = requested
+	}
+	if len(visible) == 0 {
+		return []domain.TimelineEvent{}, "", nil
+	}
+
+	return s.repo.GetTicketTimeline(ticketID, visible, limit, cursor)
+}
+
+func containsTimelineEventType(types []domain.TimelineEventType, eventType domain.TimelineEventType) bool {
+	for _, candidate := range types {
+		if candidate == eventType {
+			return true
+		}
+	}
+	return false
+}
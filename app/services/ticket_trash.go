@@ -0,0 +1,68 @@
+package services
+
+import (
+	"app/domain"
+	"app/domain/models"
+	"context"
+	"fmt"
+	"log"
+	"time"
+)
+
+// DeleteTicket moves a ticket to the trash, it is restorable until it is purged
+func (s *appService) DeleteTicket(id int, claim models.User) error {
+	ticket, err := s.repo.GetTicketByID(id)
+	if err != nil {
+		return fmt.Errorf("%w: ticket %d", domain.ErrTicketNotFound, id)
+	}
+	if err := s.repo.DeleteTicket(ticket.ID); err != nil {
+		return err
+	}
+
+	return s.recordTicketLog(ticket.ID, claim, models.TicketLogTicketDeleted, "Ticket deleted")
+}
+
+func (s *appService) GetDeletedTickets() ([]models.Ticket, error) {
+	return s.repo.GetDeletedTickets()
+}
+
+// RestoreTicket brings a ticket back from the trash
+func (s *appService) RestoreTicket(id int, claim models.User) (*models.Ticket, error) {
+	if err := s.repo.RestoreTicket(id); err != nil {
+		return nil, fmt.Errorf("%w: no deleted ticket %d", domain.ErrTicketNotFound, id)
+	}
+
+	if err := s.recordTicketLog(id, claim, models.TicketLogTicketRestored, "Ticket restored"); err != nil {
+		return nil, err
+	}
+	return s.GetTicketByID(id)
+}
+
+// PurgeDeletedTickets permanently deletes the tickets that were deleted longer than the
+// retention period ago, together with their attachment files in object storage
+func (s *appService) PurgeDeletedTickets(retention time.Duration) error {
+	tickets, err := s.repo.GetTicketsDeletedBefore(time.Now().Add(-retention))
+	if err != nil {
+		return err
+	}
+
+	for _, ticket := range tickets {
+		filePaths, err := s.repo.PurgeTicket(ticket.ID)
+		if err != nil {
+			log.Printf("[ticket-purge] failed to purge ticket #%s: %v", ticket.KodeTiket, err)
+			continue
+		}
+
+		// The records are gone, a file that fails to delete is only reported
+		for _, filePath := range filePaths {
+			ctx, cancel := context.WithTimeout(context.Background(), s.timeout)
+			if err := s.s3Repo.DeleteFile(ctx, filePath); err != nil {
+				log.Printf("[ticket-purge] failed to delete file %s of ticket #%s: %v", filePath, ticket.KodeTiket, err)
+			}
+			cancel()
+		}
+		log.Printf("[ticket-purge] purged ticket #%s with %d attachments", ticket.KodeTiket, len(filePaths))
+	}
+
+	return nil
+}
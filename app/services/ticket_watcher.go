@@ -0,0 +1,279 @@
+package services
+
+import (
+	"app/domain"
+	"app/domain/models"
+	"bytes"
+	"errors"
+	"fmt"
+	"html/template"
+	"log"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+// TicketNotificationType is the kind of change watchers are notified about, it is also
+// the "event" of the ticket_notification websocket frame
+type TicketNotificationType string
+
+const (
+	TicketNotificationComment      TicketNotificationType = "comment"
+	TicketNotificationStatusChange TicketNotificationType = "status_change"
+	TicketNotificationAssignment   TicketNotificationType = "assignment"
+	TicketNotificationAutoClose    TicketNotificationType = "auto_close_warning"
+	TicketNotificationReopen       TicketNotificationType = "reopen"
+	TicketNotificationWake         TicketNotificationType = "wake"
+	TicketNotificationEscalation   TicketNotificationType = "escalation"
+)
+
+func (s *appService) WatchTicket(ticketID int, claim models.User) error {
+	if _, err := s.repo.GetTicketByID(ticketID); err != nil {
+		return fmt.Errorf("%w: ticket %d", domain.ErrTicketNotFound, ticketID)
+	}
+	return s.addTicketWatcher(ticketID, claim.ID)
+}
+
+func (s *appService) UnwatchTicket(ticketID int, claim models.User) error {
+	return s.repo.RemoveTicketWatcher(ticketID, claim.ID)
+}
+
+func (s *appService) GetTicketWatchers(ticketID int) ([]models.TicketWatcher, error) {
+	return s.repo.GetTicketWatchers(ticketID)
+}
+
+func (s *appService) addTicketWatcher(ticketID int, userID uint64) error {
+	return s.repo.AddTicketWatcher(&models.TicketWatcher{
+		TicketID:  ticketID,
+		UserID:    userID,
+		CreatedAt: time.Now(),
+	})
+}
+
+// notifyTicketWatchers emails the watchers of a ticket and pushes a ticket_notification
+// frame to the connected ones. The actor is not notified of their own change. It runs
+// in the background so a slow mail server never delays the request. Extra recipients
+// are notified even when they do not watch the ticket.
+func (s *appService) notifyTicketWatchers(ticket *models.Ticket, kind TicketNotificationType, actor models.User, message string, extra ...*models.User) {
+	ticketID, kodeTiket, judul := ticket.ID, ticket.KodeTiket, ticket.Judul
+	statusID := ticket.StatusID
+
+	// Watchers are loaded outside of a transaction, once the change is committed
+	base := s.base()
+	s.onCommit(func() {
+		go base.sendTicketNotifications(ticketID, kodeTiket, judul, statusID, kind, actor, message, extra...)
+	})
+}
+
+// sendTicketNotifications delivers a watcher notification, see notifyTicketWatchers
+func (s *appService) sendTicketNotifications(ticketID int, kodeTiket, judul string, statusID int, kind TicketNotificationType, actor models.User, message string, extra ...*models.User) {
+	watchers, err := s.repo.GetTicketWatchers(ticketID)
+	if err != nil {
+		log.Printf("Failed to load watchers of ticket #%s: %v", kodeTiket, err)
+		return
+	}
+
+	recipients := make([]*models.User, 0, len(watchers)+len(extra))
+	notified := make(map[uint64]bool)
+	for _, watcher := range watchers {
+		if watcher.User != nil && !notified[watcher.UserID] {
+			recipients = append(recipients, watcher.User)
+			notified[watcher.UserID] = true
+		}
+	}
+	for _, user := range extra {
+		if user != nil && !notified[user.ID] {
+			recipients = append(recipients, user)
+			notified[user.ID] = true
+		}
+	}
+
+	payload := ticketNotificationPayload(ticketID, kodeTiket, judul, statusID, kind, actor, message)
+	for _, recipient := range recipients {
+		if recipient.ID == actor.ID {
+			continue
+		}
+
+		s.pushTicketNotification(recipient.ID, payload)
+
+		if err := s.sendTicketNotificationEmail(recipient, kodeTiket, judul, kind, message); err != nil {
+			log.Printf("Failed to send %s notification for ticket #%s to %s: %v", kind, kodeTiket, recipient.Email, err)
+		}
+	}
+}
+
+// notifyTicketAssignee pushes a ticket_notification frame to the assignee of the ticket when
+// they are connected. Unlike notifyTicketWatchers nobody is emailed.
+func (s *appService) notifyTicketAssignee(ticket *models.Ticket, kind TicketNotificationType, actor models.User, message string) {
+	payload := ticketNotificationPayload(ticket.ID, ticket.KodeTiket, ticket.Judul, ticket.StatusID, kind, actor, message)
+	ticketID, kodeTiket := ticket.ID, ticket.KodeTiket
+
+	base := s.base()
+	s.onCommit(func() {
+		go func() {
+			assignment, err := base.repo.GetTicketAssignmentByTicketID(ticketID)
+			if err != nil {
+				if !errors.Is(err, gorm.ErrRecordNotFound) {
+					log.Printf("Failed to load the assignee of ticket #%s: %v", kodeTiket, err)
+				}
+				return
+			}
+			if uint64(assignment.AdminID) != actor.ID {
+				base.pushTicketNotification(uint64(assignment.AdminID), payload)
+			}
+		}()
+	})
+}
+
+func ticketNotificationPayload(ticketID int, kodeTiket, judul string, statusID int, kind TicketNotificationType, actor models.User, message string) map[string]interface{} {
+	return map[string]interface{}{
+		"event":      kind,
+		"id_ticket":  ticketID,
+		"kode_tiket": kodeTiket,
+		"judul":      judul,
+		"id_status":  statusID,
+		"message":    message,
+		"actor_id":   actor.ID,
+		"actor_name": actor.Username,
+	}
+}
+
+// pushTicketNotification sends a ticket_notification frame to the user when connected
+func (s *appService) pushTicketNotification(userID uint64, payload map[string]interface{}) {
+	s.hub.Mu.RLock()
+	client := s.hub.Clients[userID]
+	s.hub.Mu.RUnlock()
+	if client != nil {
+		s.sendDirect(client, "ticket_notification", payload)
+	}
+}
+
+type TicketNotificationEmailData struct {
+	UserName    string
+	TicketId    string
+	TicketTitle string
+	Heading     string
+	Message     string
+	Date        string
+	CurrentYear int
+}
+
+var ticketNotificationHeadings = map[TicketNotificationType]string{
+	TicketNotificationComment:      "Komentar Baru pada Tiket",
+	TicketNotificationStatusChange: "Status Tiket Diperbarui",
+	TicketNotificationAssignment:   "Tiket Ditugaskan",
+	TicketNotificationAutoClose:    "Tiket Akan Ditutup Otomatis",
+	TicketNotificationReopen:       "Tiket Dibuka Kembali",
+	TicketNotificationEscalation:   "Tiket Dieskalasi",
+}
+
+func (s *appService) sendTicketNotificationEmail(user *models.User, ticketId, ticketTitle string, kind TicketNotificationType, message string) error {
+	heading := ticketNotificationHeadings[kind]
+	data := TicketNotificationEmailData{
+		UserName:    user.Username,
+		TicketId:    ticketId,
+		TicketTitle: ticketTitle,
+		Heading:     heading,
+		Message:     message,
+		Date:        time.Now().Format("02 January 2006, 15:04"),
+		CurrentYear: time.Now().Year(),
+	}
+
+	htmlBody, err := renderEmailTemplate(ticketNotificationEmailTemplate, data)
+	if err != nil {
+		return fmt.Errorf("failed to render email template: %v", err)
+	}
+
+	return s.sendEmail(user.Email, fmt.Sprintf("%s #%s", heading, ticketId), htmlBody)
+}
+
+func renderEmailTemplate(tmpl string, data interface{}) (string, error) {
+	t, err := template.New("email").Parse(tmpl)
+	if err != nil {
+		return "", err
+	}
+
+	var buf bytes.Buffer
+	if err := t.Execute(&buf, data); err != nil {
+		return "", err
+	}
+
+	return buf.String(), nil
+}
+
+const ticketNotificationEmailTemplate = `<!DOCTYPE html>
+<html lang="id">
+<head>
+    <meta charset="UTF-8" />
+    <meta name="viewport" content="width=device-width, initial-scale=1.0" />
+    <title>{{.Heading}}</title>
+</head>
+
+<body style="margin:0; padding:0; background-color:#f3f4f6; font-family:Arial,Helvetica,sans-serif;">
+
+    <center style="width:100%; padding:20px 0; background-color:#f3f4f6;">
+
+        <table width="600" style="width:600px; max-width:600px; background:#ffffff; border-radius:6px; border-collapse:collapse;">
+
+            <!-- HEADER -->
+            <tr>
+                <td style="background:#f59e0b; padding:30px; text-align:center; color:#ffffff;">
+                    <h1 style="margin:0; font-size:24px; font-weight:bold;">{{.Heading}}</h1>
+                    <p style="margin:8px 0 0; font-size:14px;">Anda mengikuti tiket ini</p>
+                </td>
+            </tr>
+
+            <!-- BODY -->
+            <tr>
+                <td style="padding:30px; font-size:14px; color:#374151;">
+
+                    <p style="margin:0 0 15px;">
+                        Halo <strong>{{.UserName}}</strong>,
+                    </p>
+
+                    <!-- TICKET INFO BOX -->
+                    <table width="100%" style="width:100%; border-collapse:collapse; background:#f9fafb; border:1px solid #e5e7eb; border-radius:4px;">
+                        <tr>
+                            <td style="padding:12px; border-bottom:1px solid #e5e7eb;">
+                                <strong style="color:#6b7280;">Nomor Tiket:</strong>
+                                <span style="float:right; color:#111827;">#{{.TicketId}}</span>
+                            </td>
+                        </tr>
+
+                        <tr>
+                            <td style="padding:12px; border-bottom:1px solid #e5e7eb;">
+                                <strong style="color:#6b7280;">Judul:</strong>
+                                <span style="float:right; color:#111827;">{{.TicketTitle}}</span>
+                            </td>
+                        </tr>
+
+                        <tr>
+                            <td style="padding:12px;">
+                                <strong style="color:#6b7280;">Tanggal:</strong>
+                                <span style="float:right; color:#111827;">{{.Date}}</span>
+                            </td>
+                        </tr>
+                    </table>
+
+                    <div style="margin-top:25px; background:#f9fafb; border:1px solid #e5e7eb; padding:15px; border-radius:4px; color:#111827; line-height:1.6; white-space:pre-wrap;">{{.Message}}</div>
+
+                </td>
+            </tr>
+
+            <!-- FOOTER -->
+            <tr>
+                <td style="padding:25px 30px; text-align:center; font-size:12px; color:#6b7280;">
+                    <strong>SecondCycle Help Center</strong>
+                    <br><br>
+                    <span style="color:#9ca3af;">
+                        © {{.CurrentYear}} SecondCycle. Email ini dikirim karena Anda mengikuti tiket ini.
+                    </span>
+                </td>
+            </tr>
+
+        </table>
+
+    </center>
+
+</body>
+</html>`
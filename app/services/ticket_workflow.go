@@ -0,0 +1,114 @@
+package services
+
+import (
+	"app/domain"
+	"app/domain/models"
+	"errors"
+	"fmt"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+func (s *appService) CreateTicketStatusTransition(transition *models.TicketStatusTransition) error {
+	if _, err := s.repo.GetTicketStatusByID(transition.FromStatusID); err != nil {
+		return fmt.Errorf("from status not found: %v", err)
+	}
+	if _, err := s.repo.GetTicketStatusByID(transition.ToStatusID); err != nil {
+		return fmt.Errorf("to status not found: %v", err)
+	}
+	if transition.Event == "" {
+		transition.Event = models.WorkflowEventManual
+	}
+	return s.repo.CreateTicketStatusTransition(transition)
+}
+
+func (s *appService) GetTicketStatusTransitions(fromStatusID int) ([]models.TicketStatusTransition, error) {
+	return s.repo.GetTicketStatusTransitions(fromStatusID)
+}
+
+func (s *appService) DeleteTicketStatusTransition(id int) error {
+	return s.repo.DeleteTicketStatusTransition(id)
+}
+
+// initialTicketStatusID returns the status flagged as initial in the workflow
+func (s *appService) initialTicketStatusID() (int, error) {
+	status, err := s.repo.GetInitialTicketStatus()
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return 0, domain.ErrNoInitialStatus
+		}
+		return 0, err
+	}
+	return status.ID, nil
+}
+
+// applyTicketEvent moves the ticket along the first transition configured for event
+// from its current status. The ticket is only mutated, callers persist it.
+func (s *appService) applyTicketEvent(ticket *models.Ticket, event models.WorkflowEvent, claim models.User) error {
+	transition, err := s.repo.GetTicketStatusTransitionByEvent(ticket.StatusID, event)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return fmt.Errorf("%w: no %q transition from status %d", domain.ErrInvalidStatusTransition, event, ticket.StatusID)
+		}
+		return err
+	}
+	return s.applyTransition(ticket, transition, claim)
+}
+
+// changeTicketStatus validates an explicit status change against the manual transitions.
+// The ticket is only mutated, callers persist it.
+func (s *appService) changeTicketStatus(ticket *models.Ticket, toStatusID int, claim models.User) error {
+	transition, err := s.repo.GetTicketStatusTransition(ticket.StatusID, toStatusID, models.WorkflowEventManual)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return fmt.Errorf("%w: from status %d to status %d", domain.ErrInvalidStatusTransition, ticket.StatusID, toStatusID)
+		}
+		return err
+	}
+	return s.applyTransition(ticket, transition, claim)
+}
+
+func (s *appService) applyTransition(ticket *models.Ticket, transition *models.TicketStatusTransition, claim models.User) error {
+	if !transitionAllowsRole(transition, claim.Role) {
+		return fmt.Errorf("%w: role %q cannot move ticket from status %d to status %d", domain.ErrTransitionForbidden, claim.Role, transition.FromStatusID, transition.ToStatusID)
+	}
+
+	now := time.Now()
+	if ticket.StatusID != transition.ToStatusID {
+		// A new status restarts the auto-close window
+		ticket.StatusChangedAt = &now
+		ticket.AutoCloseWarnedAt = nil
+	}
+	ticket.StatusID = transition.ToStatusID
+	ticket.Status = transition.ToStatus
+	ticket.TanggalDiperbarui = now
+
+	// Leaving the pending status ends the snooze whatever moved the ticket
+	if !ticketIsPending(ticket) {
+		ticket.PendingUntil = nil
+		ticket.PendingReason = ""
+	}
+
+	// Due timestamps follow every status change
+	s.refreshTicketSLA(ticket, transition, now)
+	return nil
+}
+
+// transitionAllowsRole checks the role guard of a transition. An empty guard allows every
+// role and background jobs acting as models.SystemUser pass every guard, an empty role
+// never passes.
+func transitionAllowsRole(transition *models.TicketStatusTransition, role models.UserRole) bool {
+	switch {
+	case role == "":
+		return false
+	case role == models.RoleSystem, len(transition.AllowedRoles) == 0:
+		return true
+	}
+	for _, allowed := range transition.AllowedRoles {
+		if allowed == role {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,33 @@
+package domain
+
+// BulkTicketAction is the change applied by a bulk ticket operation
+type BulkTicketAction string
+
+const (
+	BulkTicketActionStatus   BulkTicketAction = "status"
+	BulkTicketActionPriority BulkTicketAction = "priority"
+	BulkTicketActionCategory BulkTicketAction = "category"
+	BulkTicketActionAssign   BulkTicketAction = "assign"
+	BulkTicketActionDelete   BulkTicketAction = "delete"
+)
+
+// BulkTicketOperation applies one action to a list of tickets. Only the value of the
+// chosen action is used.
+type BulkTicketOperation struct {
+	TicketIDs  []int
+	Action     BulkTicketAction
+	StatusID   int
+	PriorityID int
+	CategoryID int
+	AdminID    int
+}
+
+// BulkTicketResult is the outcome of a bulk operation for a single ticket. Status is the
+// HTTP status the single ticket endpoint would have answered a failure with.
+type BulkTicketResult struct {
+	TicketID int    `json:"id_ticket"`
+	Success  bool   `json:"success"`
+	Status   int    `json:"status,omitempty"`
+	Error    string `json:"error,omitempty"`
+	Err      error  `json:"-"`
+}
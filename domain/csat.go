@@ -0,0 +1,45 @@
+package domain
+
+import "time"
+
+// CSATGroupBy selects what the CSAT report is aggregated by
+type CSATGroupBy string
+
+const (
+	CSATGroupByAgent    CSATGroupBy = "agent"
+	CSATGroupByCategory CSATGroupBy = "category"
+	CSATGroupByPeriod   CSATGroupBy = "period"
+)
+
+// CSATPeriod is the length of the periods of a CSAT report grouped by period
+type CSATPeriod string
+
+const (
+	CSATPeriodDay   CSATPeriod = "day"
+	CSATPeriodWeek  CSATPeriod = "week"
+	CSATPeriodMonth CSATPeriod = "month"
+)
+
+// CSATFilter selects the answered surveys of a CSAT report, zero values disable a filter
+type CSATFilter struct {
+	GroupBy CSATGroupBy
+	Period  CSATPeriod
+
+	// From and To bound the time the surveys were answered
+	From time.Time
+	To   time.Time
+
+	AssigneeID uint64
+	CategoryID int
+}
+
+// CSATAggregate is one row of a CSAT report. Key is the assignee ID, the category ID or the
+// start date of the period, Score is the share of satisfied responses in percent.
+type CSATAggregate struct {
+	Key           string  `json:"key"`
+	Label         string  `json:"label"`
+	Responses     int     `json:"responses"`
+	AverageRating float64 `json:"average_rating"`
+	Satisfied     int     `json:"satisfied"`
+	Score         float64 `json:"csat_score"`
+}
@@ -0,0 +1,53 @@
+package domain
+
+import "errors"
+
+var (
+	ErrNoInitialStatus         = errors.New("no initial ticket status configured")
+	ErrInvalidStatusTransition = errors.New("status transition is not allowed by the ticket workflow")
+	ErrTransitionForbidden     = errors.New("your role is not allowed to perform this status transition")
+	ErrNotTicketOwner          = errors.New("only the owner of the ticket can do this")
+	ErrNotViewOwner            = errors.New("only the owner of the view can do this")
+	ErrTicketAccessDenied      = errors.New("you are not allowed to access this ticket")
+	ErrTicketNotFound          = errors.New("ticket not found")
+	ErrTicketCodeExists        = errors.New("a ticket with this code already exists")
+	ErrRecordNotFound          = errors.New("record not found")
+	ErrVersionConflict         = errors.New("the record was changed by someone else, reload it and try again")
+	ErrTicketMergeSelf         = errors.New("a ticket cannot be merged into itself")
+	ErrTicketAlreadyMerged     = errors.New("ticket has already been merged into another ticket")
+	ErrInvalidTicketLink       = errors.New("invalid ticket link")
+	ErrTicketLinkExists        = errors.New("tickets are already linked")
+	ErrTicketLinkNotFound      = errors.New("ticket link not found")
+	ErrTagNotFound             = errors.New("tag not found")
+	ErrInvalidSurveyToken      = errors.New("invalid survey link")
+	ErrSurveyExpired           = errors.New("the survey link has expired")
+	ErrSurveyAnswered          = errors.New("the survey has already been answered")
+)
+
+// ValidationError reports invalid input per field, handlers return the fields as the
+// validation map of the response
+type ValidationError struct {
+	Fields map[string]string
+}
+
+func (e *ValidationError) Error() string {
+	return "validation failed"
+}
+
+// Add records a problem with a field, keeping the first one reported
+func (e *ValidationError) Add(field, message string) {
+	if e.Fields == nil {
+		e.Fields = make(map[string]string)
+	}
+	if _, exists := e.Fields[field]; !exists {
+		e.Fields[field] = message
+	}
+}
+
+// OrNil returns the error only when a field was reported
+func (e *ValidationError) OrNil() error {
+	if len(e.Fields) == 0 {
+		return nil
+	}
+	return e
+}
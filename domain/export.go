@@ -0,0 +1,61 @@
+package domain
+
+import "time"
+
+// TicketExportFormat is the file format tickets are exported to
+type TicketExportFormat string
+
+const (
+	TicketExportCSV  TicketExportFormat = "csv"
+	TicketExportXLSX TicketExportFormat = "xlsx"
+)
+
+// TicketExportRow is a ticket with the names of its joined records, as exported
+type TicketExportRow struct {
+	ID                int
+	KodeTiket         string
+	Judul             string
+	TipePengaduan     string
+	Username          string
+	Category          string
+	Priority          string
+	Status            string
+	Assignee          string
+	ReopenCount       int
+	TanggalDibuat     time.Time
+	TanggalDiperbarui time.Time
+}
+
+// TicketExportColumns are the header of an export, in the order of TicketExportRow.Values
+var TicketExportColumns = []string{
+	"id_ticket",
+	"kode_tiket",
+	"judul",
+	"tipe_pengaduan",
+	"username",
+	"category",
+	"priority",
+	"status",
+	"assignee",
+	"reopen_count",
+	"tanggal_dibuat",
+	"tanggal_diperbarui",
+}
+
+// Values returns the cells of the row in the order of TicketExportColumns
+func (row TicketExportRow) Values() []interface{} {
+	return []interface{}{
+		row.ID,
+		row.KodeTiket,
+		row.Judul,
+		row.TipePengaduan,
+		row.Username,
+		row.Category,
+		row.Priority,
+		row.Status,
+		row.Assignee,
+		row.ReopenCount,
+		row.TanggalDibuat,
+		row.TanggalDiperbarui,
+	}
+}
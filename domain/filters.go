@@ -0,0 +1,47 @@
+package domain
+
+import (
+	"app/domain/models"
+	"time"
+)
+
+// TicketFilter holds the ticket list filters, zero values disable a filter
+type TicketFilter struct {
+	TipePengaduan string
+	StatusID      int
+	PriorityID    int
+	CategoryID    int
+
+	// KodeTiket keeps tickets whose code starts with it, case-insensitive
+	KodeTiket string
+
+	// AssigneeID keeps tickets assigned to that user
+	AssigneeID uint64
+
+	// MinReopenCount keeps tickets reopened at least that many times
+	MinReopenCount int
+
+	// IncludePending keeps snoozed tickets, they are left out of the queues unless
+	// asked for or filtered by status
+	IncludePending bool
+
+	// SLAState keeps only tickets that are at risk or breached, SLAAtRiskWindow is
+	// how close to a due timestamp a ticket counts as at risk
+	SLAState        models.SLAState
+	SLAAtRiskWindow time.Duration
+
+	Tags TagFilter
+
+	// CustomFields matches custom field values by key, compared as text
+	CustomFields map[string]string
+
+	// Sort orders the ticket list, newest first when empty. A cursor only continues the
+	// list in the order it was returned for.
+	Sort models.TicketSort
+}
+
+// TagFilter keeps records carrying any (or with MatchAll, every one) of the tags
+type TagFilter struct {
+	TagIDs   []int
+	MatchAll bool
+}
@@ -0,0 +1,71 @@
+package domain
+
+// TicketImportField is a ticket field a CSV column is mapped to
+type TicketImportField string
+
+const (
+	TicketImportKodeTiket         TicketImportField = "kode_tiket"
+	TicketImportUser              TicketImportField = "user"
+	TicketImportJudul             TicketImportField = "judul"
+	TicketImportDeskripsi         TicketImportField = "deskripsi"
+	TicketImportCategory          TicketImportField = "category"
+	TicketImportPriority          TicketImportField = "priority"
+	TicketImportStatus            TicketImportField = "status"
+	TicketImportTipePengaduan     TicketImportField = "tipe_pengaduan"
+	TicketImportTanggalDibuat     TicketImportField = "tanggal_dibuat"
+	TicketImportTanggalDiperbarui TicketImportField = "tanggal_diperbarui"
+	TicketImportAssignee          TicketImportField = "assignee"
+	TicketImportComment           TicketImportField = "comment"
+	TicketImportCommentUser       TicketImportField = "comment_user"
+	TicketImportCommentDate       TicketImportField = "comment_date"
+)
+
+// TicketImportFields are the fields a CSV column can be mapped to
+var TicketImportFields = []TicketImportField{
+	TicketImportKodeTiket,
+	TicketImportUser,
+	TicketImportJudul,
+	TicketImportDeskripsi,
+	TicketImportCategory,
+	TicketImportPriority,
+	TicketImportStatus,
+	TicketImportTipePengaduan,
+	TicketImportTanggalDibuat,
+	TicketImportTanggalDiperbarui,
+	TicketImportAssignee,
+	TicketImportComment,
+	TicketImportCommentUser,
+	TicketImportCommentDate,
+}
+
+// RequiredTicketImportFields have to be mapped to a column of every import
+var RequiredTicketImportFields = []TicketImportField{
+	TicketImportUser,
+	TicketImportJudul,
+	TicketImportCategory,
+	TicketImportPriority,
+	TicketImportStatus,
+	TicketImportTanggalDibuat,
+}
+
+// TicketImportMapping maps ticket fields to CSV column headers. Fields left out are read
+// from the column named like the field.
+type TicketImportMapping map[TicketImportField]string
+
+// TicketImportRowError is a problem with one CSV row, Row counts the header as row 1
+type TicketImportRowError struct {
+	Row     int    `json:"row"`
+	Field   string `json:"field,omitempty"`
+	Message string `json:"message"`
+}
+
+// TicketImportResult summarizes an import. With errors nothing was imported, a dry run
+// never imports and tells what an import would create.
+type TicketImportResult struct {
+	DryRun      bool                   `json:"dry_run"`
+	Rows        int                    `json:"rows"`
+	Tickets     int                    `json:"tickets"`
+	Comments    int                    `json:"comments"`
+	Assignments int                    `json:"assignments"`
+	Errors      []TicketImportRowError `json:"errors"`
+}
@@ -5,19 +5,33 @@ import "app/domain/models"
 func GetAllModels() []interface{} {
 	return []interface{}{
 		&models.User{},
+		&models.Tag{},
 		&models.Conversation{},
 		&models.Message{},
 		&models.AdminAvailability{},
 		&models.AdminConversationState{},
 		// Master tables first
 		&models.TicketCategory{},
+		&models.CustomFieldDefinition{},
 		&models.TicketPriority{},
 		&models.TicketStatus{},
+		&models.TicketStatusTransition{},
+		&models.BusinessCalendar{},
+		&models.BusinessHour{},
+		&models.Holiday{},
+		&models.SLAPolicy{},
+		&models.EscalationRule{},
+		&models.TicketCodeSequence{},
 		// Then transaction tables
 		&models.Ticket{},
 		&models.TicketComment{},
 		&models.TicketAttachment{},
 		&models.TicketAssignment{},
 		&models.TicketLog{},
+		&models.TicketLink{},
+		&models.TicketWatcher{},
+		&models.CSATSurvey{},
+		&models.TicketEscalation{},
+		&models.SavedTicketView{},
 	}
 }
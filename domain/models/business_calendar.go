@@ -0,0 +1,32 @@
+package models
+
+// BusinessCalendar describes when the support team works. SLA policies without a
+// calendar use the default calendar, or wall-clock time when none is flagged default.
+type BusinessCalendar struct {
+	ID           int    `json:"id_calendar" gorm:"column:id_calendar;primaryKey"`
+	NamaCalendar string `json:"nama_calendar" gorm:"column:nama_calendar;type:varchar(100);not null"`
+	Timezone     string `json:"timezone" gorm:"column:timezone;type:varchar(64);not null;default:'Asia/Jakarta'"`
+	IsDefault    bool   `json:"is_default" gorm:"column:is_default;default:false"`
+
+	// Relasi
+	Hours    []BusinessHour `json:"hours" gorm:"foreignKey:CalendarID;constraint:OnDelete:CASCADE"`
+	Holidays []Holiday      `json:"holidays" gorm:"foreignKey:CalendarID;constraint:OnDelete:CASCADE"`
+}
+
+// BusinessHour is a working window on a weekday (0 = Sunday ... 6 = Saturday), times are HH:MM
+// in the calendar timezone
+type BusinessHour struct {
+	ID         int    `json:"id_business_hour" gorm:"column:id_business_hour;primaryKey"`
+	CalendarID int    `json:"id_calendar" gorm:"column:id_calendar;not null;index"`
+	Weekday    int    `json:"weekday" gorm:"column:weekday;not null"`
+	StartTime  string `json:"start_time" gorm:"column:start_time;type:varchar(5);not null" example:"08:00"`
+	EndTime    string `json:"end_time" gorm:"column:end_time;type:varchar(5);not null" example:"17:00"`
+}
+
+// Holiday is a full non-working day of a calendar, Tanggal is YYYY-MM-DD
+type Holiday struct {
+	ID          int    `json:"id_holiday" gorm:"column:id_holiday;primaryKey"`
+	CalendarID  int    `json:"id_calendar" gorm:"column:id_calendar;not null;uniqueIndex:idx_calendar_holiday"`
+	Tanggal     string `json:"tanggal" gorm:"column:tanggal;type:varchar(10);not null;uniqueIndex:idx_calendar_holiday" example:"2026-08-17"`
+	NamaHoliday string `json:"nama_holiday" gorm:"column:nama_holiday;type:varchar(100)"`
+}
@@ -15,6 +15,7 @@ type Conversation struct {
 	Customer   User                    `json:"customer" gorm:"foreignKey:CustomerID"`
 	Admin      User                    `json:"admin" gorm:"foreignKey:AdminID"`
 	AdminState *AdminConversationState `json:"admin_state" gorm:"foreignKey:ConversationID"`
+	Tags       []Tag                   `json:"tags" gorm:"many2many:conversation_tags"`
 }
 
 type ConversationStatus string
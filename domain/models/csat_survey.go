@@ -0,0 +1,32 @@
+package models
+
+import "time"
+
+// CSATSurvey asks the ticket owner to rate the support after the ticket was resolved. The
+// assignee and category are kept as they were at resolution time for the reports.
+type CSATSurvey struct {
+	ID          int        `json:"id_survey" gorm:"column:id_survey;primaryKey"`
+	TicketID    int        `json:"id_ticket" gorm:"column:id_ticket;not null;index"`
+	UserID      uint64     `json:"id_user" gorm:"column:id_user;not null;index"`
+	AssigneeID  *uint64    `json:"id_assignee,omitempty" gorm:"column:id_assignee;index"`
+	CategoryID  int        `json:"id_category" gorm:"column:id_category;not null;index"`
+	SentAt      time.Time  `json:"sent_at" gorm:"column:sent_at;default:CURRENT_TIMESTAMP"`
+	ExpiresAt   time.Time  `json:"expires_at" gorm:"column:expires_at;not null"`
+	Rating      *int       `json:"rating,omitempty" gorm:"column:rating;check:rating BETWEEN 1 AND 5"`
+	Comment     string     `json:"comment,omitempty" gorm:"column:comment;type:text"`
+	RespondedAt *time.Time `json:"responded_at,omitempty" gorm:"column:responded_at;index"`
+
+	// Relasi
+	Ticket   *Ticket         `json:"ticket,omitempty" gorm:"foreignKey:TicketID"`
+	User     *User           `json:"user,omitempty" gorm:"foreignKey:UserID"`
+	Assignee *User           `json:"assignee,omitempty" gorm:"foreignKey:AssigneeID"`
+	Category *TicketCategory `json:"category,omitempty" gorm:"foreignKey:CategoryID"`
+}
+
+// CSAT ratings range from 1 (very dissatisfied) to 5 (very satisfied), ratings of at least
+// CSATSatisfiedRating count as satisfied
+const (
+	CSATMinRating       = 1
+	CSATMaxRating       = 5
+	CSATSatisfiedRating = 4
+)
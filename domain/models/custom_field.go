@@ -0,0 +1,65 @@
+package models
+
+import (
+	"database/sql/driver"
+	"encoding/json"
+	"errors"
+	"regexp"
+)
+
+// CustomFieldKeyPattern keeps keys usable as json keys and cf.<key> list filters
+var CustomFieldKeyPattern = regexp.MustCompile(`^[a-z][a-z0-9_]{0,49}$`)
+
+// CustomFieldDefinition describes an extra field collected on the tickets of a category
+type CustomFieldDefinition struct {
+	ID         int             `json:"id_custom_field" gorm:"column:id_custom_field;primaryKey"`
+	CategoryID int             `json:"id_category" gorm:"column:id_category;not null;uniqueIndex:idx_custom_field_key"`
+	Key        string          `json:"key" gorm:"column:key;type:varchar(50);not null;uniqueIndex:idx_custom_field_key" example:"nomor_pesanan"`
+	Label      string          `json:"label" gorm:"column:label;type:varchar(100)" example:"Nomor Pesanan"`
+	FieldType  CustomFieldType `json:"field_type" gorm:"column:field_type;type:varchar(20);not null" example:"text"`
+	Required   bool            `json:"required" gorm:"column:required;default:false"`
+	Options    []string        `json:"options,omitempty" gorm:"column:options;type:text;serializer:json"` // allowed values of a select field
+	Regex      string          `json:"regex,omitempty" gorm:"column:regex;type:varchar(255)"`             // pattern a text field has to match
+	Urutan     int             `json:"urutan" gorm:"column:urutan;default:0"`                             // display order
+
+	// Relasi
+	Category *TicketCategory `json:"category,omitempty" gorm:"foreignKey:CategoryID"`
+}
+
+type CustomFieldType string
+
+const (
+	CustomFieldText    CustomFieldType = "text"
+	CustomFieldNumber  CustomFieldType = "number"
+	CustomFieldBoolean CustomFieldType = "boolean"
+	CustomFieldDate    CustomFieldType = "date" // YYYY-MM-DD
+	CustomFieldSelect  CustomFieldType = "select"
+)
+
+// CustomFieldValues holds the custom field values of a ticket by key, stored as jsonb
+type CustomFieldValues map[string]interface{}
+
+func (v CustomFieldValues) Value() (driver.Value, error) {
+	if v == nil {
+		return nil, nil
+	}
+	data, err := json.Marshal(v)
+	if err != nil {
+		return nil, err
+	}
+	return string(data), nil
+}
+
+func (v *CustomFieldValues) Scan(value interface{}) error {
+	switch data := value.(type) {
+	case nil:
+		*v = nil
+		return nil
+	case []byte:
+		return json.Unmarshal(data, v)
+	case string:
+		return json.Unmarshal([]byte(data), v)
+	default:
+		return errors.New("unsupported custom field values type")
+	}
+}
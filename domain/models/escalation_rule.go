@@ -0,0 +1,41 @@
+package models
+
+import "time"
+
+// EscalationRule acts on tickets no admin or support user commented on for NoCommentHours.
+// Nil matchers act as wildcards, a rule without a status only matches tickets in a status
+// that is neither terminal, resolved nor pausing the SLA. A matching ticket gets its priority
+// raised, is reassigned and its watchers are notified, as configured, once per rule.
+type EscalationRule struct {
+	ID              int       `json:"id_escalation_rule" gorm:"column:id_escalation_rule;primaryKey"`
+	NamaRule        string    `json:"nama_rule" gorm:"column:nama_rule;type:varchar(100);not null"`
+	StatusID        *int      `json:"status_id,omitempty" gorm:"column:status_id;index"`
+	PriorityID      *int      `json:"priority_id,omitempty" gorm:"column:priority_id;index"`
+	CategoryID      *int      `json:"category_id,omitempty" gorm:"column:category_id;index"`
+	NoCommentHours  int       `json:"no_comment_hours" gorm:"column:no_comment_hours;not null"`
+	RaisePriorityID *int      `json:"raise_priority_id,omitempty" gorm:"column:raise_priority_id"`
+	ReassignToID    *int      `json:"reassign_to_id,omitempty" gorm:"column:reassign_to_id"` // a support user, e.g. a supervisor
+	Notify          bool      `json:"notify" gorm:"column:notify;not null"`                  // true when omitted on create
+	IsActive        bool      `json:"is_active" gorm:"column:is_active;not null"`            // true when omitted on create
+	CreatedAt       time.Time `json:"created_at"`
+	UpdatedAt       time.Time `json:"updated_at"`
+
+	// Relasi
+	Status        *TicketStatus   `json:"status,omitempty" gorm:"foreignKey:StatusID"`
+	Priority      *TicketPriority `json:"priority,omitempty" gorm:"foreignKey:PriorityID"`
+	Category      *TicketCategory `json:"category,omitempty" gorm:"foreignKey:CategoryID"`
+	RaisePriority *TicketPriority `json:"raise_priority,omitempty" gorm:"foreignKey:RaisePriorityID"`
+	ReassignTo    *User           `json:"reassign_to,omitempty" gorm:"foreignKey:ReassignToID"`
+}
+
+// TicketEscalation records that a rule escalated a ticket, a rule acts on a ticket only once
+type TicketEscalation struct {
+	ID          int       `json:"id_ticket_escalation" gorm:"column:id_ticket_escalation;primaryKey"`
+	TicketID    int       `json:"id_ticket" gorm:"column:id_ticket;not null;uniqueIndex:idx_ticket_escalation"`
+	RuleID      int       `json:"id_escalation_rule" gorm:"column:id_escalation_rule;not null;uniqueIndex:idx_ticket_escalation"`
+	EscalatedAt time.Time `json:"escalated_at" gorm:"column:escalated_at;not null"`
+
+	// Relasi
+	Ticket *Ticket         `json:"ticket,omitempty" gorm:"foreignKey:TicketID"`
+	Rule   *EscalationRule `json:"rule,omitempty" gorm:"foreignKey:RuleID"`
+}
@@ -0,0 +1,71 @@
+package models
+
+import (
+	"database/sql/driver"
+	"encoding/json"
+	"errors"
+	"time"
+)
+
+// SavedTicketView is a named ticket list filter of an agent. A shared view is listed for
+// every agent, only its owner can delete it.
+type SavedTicketView struct {
+	ID        int              `json:"id_view" gorm:"column:id_view;primaryKey"`
+	UserID    uint64           `json:"id_user" gorm:"column:id_user;not null;index"`
+	NamaView  string           `json:"nama_view" gorm:"column:nama_view;type:varchar(100);not null"`
+	Filter    TicketViewFilter `json:"filter" gorm:"column:filter;type:jsonb;not null"`
+	Sort      TicketSort       `json:"sort" gorm:"column:sort;type:varchar(20);not null;default:'newest'"`
+	IsShared  bool             `json:"is_shared" gorm:"column:is_shared;not null;default:false;index"`
+	CreatedAt time.Time        `json:"created_at"`
+	UpdatedAt time.Time        `json:"updated_at"`
+
+	// Relasi
+	User *User `json:"user,omitempty" gorm:"foreignKey:UserID"`
+}
+
+// TicketViewFilter holds the ticket list filters of a saved view, named like the query
+// parameters of the ticket list. It is stored as jsonb.
+type TicketViewFilter struct {
+	KodeTiket      string            `json:"code,omitempty"`
+	TipePengaduan  string            `json:"role,omitempty"`
+	StatusID       int               `json:"status,omitempty"`
+	PriorityID     int               `json:"priority,omitempty"`
+	CategoryID     int               `json:"category,omitempty"`
+	MinReopenCount int               `json:"min_reopen_count,omitempty"`
+	IncludePending bool              `json:"include_pending,omitempty"`
+	SLA            SLAState          `json:"sla,omitempty"`
+	TagIDs         []int             `json:"tags,omitempty"`
+	TagsMode       string            `json:"tags_mode,omitempty"` // any (default) or all
+	CustomFields   map[string]string `json:"custom_fields,omitempty"`
+}
+
+func (f TicketViewFilter) Value() (driver.Value, error) {
+	data, err := json.Marshal(f)
+	if err != nil {
+		return nil, err
+	}
+	return string(data), nil
+}
+
+func (f *TicketViewFilter) Scan(value interface{}) error {
+	switch data := value.(type) {
+	case nil:
+		*f = TicketViewFilter{}
+		return nil
+	case []byte:
+		return json.Unmarshal(data, f)
+	case string:
+		return json.Unmarshal([]byte(data), f)
+	default:
+		return errors.New("unsupported ticket view filter type")
+	}
+}
+
+// TicketSort is the order of a ticket list
+type TicketSort string
+
+const (
+	TicketSortNewest  TicketSort = "newest"  // last created first
+	TicketSortOldest  TicketSort = "oldest"  // first created first
+	TicketSortUpdated TicketSort = "updated" // last updated first
+)
@@ -0,0 +1,42 @@
+package models
+
+import "time"
+
+// SLAPolicy defines response and resolution targets in minutes of business time. Nil
+// matchers act as wildcards, the most specific active policy wins and a zero target
+// means no target.
+type SLAPolicy struct {
+	ID                   int       `json:"id_sla_policy" gorm:"column:id_sla_policy;primaryKey"`
+	NamaPolicy           string    `json:"nama_policy" gorm:"column:nama_policy;type:varchar(100);not null"`
+	PriorityID           *int      `json:"priority_id,omitempty" gorm:"column:priority_id;index"`
+	CategoryID           *int      `json:"category_id,omitempty" gorm:"column:category_id;index"`
+	TipePengaduan        *UserRole `json:"tipe_pengaduan,omitempty" gorm:"column:tipe_pengaduan;type:varchar(50)"`
+	CalendarID           *int      `json:"id_calendar,omitempty" gorm:"column:id_calendar"`
+	FirstResponseMinutes int       `json:"first_response_minutes" gorm:"column:first_response_minutes"`
+	NextResponseMinutes  int       `json:"next_response_minutes" gorm:"column:next_response_minutes"`
+	ResolutionMinutes    int       `json:"resolution_minutes" gorm:"column:resolution_minutes"`
+	IsActive             bool      `json:"is_active" gorm:"column:is_active;not null"` // true when omitted on create
+	CreatedAt            time.Time `json:"created_at"`
+	UpdatedAt            time.Time `json:"updated_at"`
+
+	// Relasi
+	Priority *TicketPriority   `json:"priority,omitempty" gorm:"foreignKey:PriorityID"`
+	Category *TicketCategory   `json:"category,omitempty" gorm:"foreignKey:CategoryID"`
+	Calendar *BusinessCalendar `json:"calendar,omitempty" gorm:"foreignKey:CalendarID"`
+}
+
+type SLAState string
+
+const (
+	SLAStateAtRisk   SLAState = "at_risk"
+	SLAStateBreached SLAState = "breached"
+)
+
+// SLATarget names one of the SLA clocks tracked on a ticket
+type SLATarget string
+
+const (
+	SLATargetFirstResponse SLATarget = "first_response"
+	SLATargetNextResponse  SLATarget = "next_response"
+	SLATargetResolution    SLATarget = "resolution"
+)
@@ -0,0 +1,8 @@
+package models
+
+// Tag is a free label attached to tickets and conversations, next to the single category
+type Tag struct {
+	ID      int    `json:"id_tag" gorm:"column:id_tag;primaryKey"`
+	NamaTag string `json:"nama_tag" gorm:"column:nama_tag;type:varchar(50);not null;uniqueIndex"`
+	Warna   string `json:"warna,omitempty" gorm:"column:warna;type:varchar(20)"` // display color, e.g. #ff9900
+}
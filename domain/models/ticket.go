@@ -1,6 +1,13 @@
 package models
 
-import "time"
+import (
+	"time"
+
+	"gorm.io/gorm"
+)
+
+// MaxMergeDepth bounds how many merged tickets are followed to reach the survivor
+const MaxMergeDepth = 10
 
 type Ticket struct {
 	ID                int       `json:"id_ticket" gorm:"column:id_ticket;primaryKey;autoIncrement"`
@@ -14,14 +21,51 @@ type Ticket struct {
 	TipePengaduan     UserRole  `json:"tipe_pengaduan" gorm:"type:varchar(50);check:tipe_pengaduan IN ('admin', 'seller', 'customer')"`
 	TanggalDibuat     time.Time `json:"tanggal_dibuat" gorm:"default:CURRENT_TIMESTAMP"`
 	TanggalDiperbarui time.Time `json:"tanggal_diperbarui" gorm:"default:CURRENT_TIMESTAMP"`
+	MergedIntoID      *int      `json:"merged_into_id,omitempty" gorm:"column:merged_into_id;index"` // set when this ticket was merged into a primary ticket
+
+	// StatusChangedAt is when the ticket entered its current status, it starts the auto-close window
+	StatusChangedAt   *time.Time `json:"status_changed_at,omitempty" gorm:"column:status_changed_at;index"`
+	AutoCloseWarnedAt *time.Time `json:"auto_close_warned_at,omitempty" gorm:"column:auto_close_warned_at"`
+
+	// PendingUntil parks the ticket in a pending status until it wakes up, PendingReason
+	// tells what support is waiting for (e.g. the courier or the seller)
+	PendingUntil  *time.Time `json:"pending_until,omitempty" gorm:"column:pending_until;index"`
+	PendingReason string     `json:"pending_reason,omitempty" gorm:"column:pending_reason;type:text"`
+
+	// ReopenCount is how often the owner reopened the ticket after it was resolved
+	ReopenCount int `json:"reopen_count" gorm:"column:reopen_count;not null;default:0;index"`
+
+	// Version is raised on every update, writes carrying an older version are rejected
+	Version int `json:"version" gorm:"column:version;not null;default:1"`
+
+	// DeletedAt hides a deleted ticket until it is restored or purged after the retention period
+	DeletedAt gorm.DeletedAt `json:"deleted_at,omitempty" gorm:"column:deleted_at;index" swaggertype:"string"`
+
+	// CustomFields holds the values of the custom fields defined for the category
+	CustomFields CustomFieldValues `json:"custom_fields,omitempty" gorm:"column:custom_fields;type:jsonb"`
+
+	// SLA tracking, due timestamps are computed from the matching SLAPolicy
+	SLAPolicyID             *int       `json:"sla_policy_id,omitempty" gorm:"column:sla_policy_id;index"`
+	FirstResponseDueAt      *time.Time `json:"first_response_due_at,omitempty" gorm:"column:first_response_due_at;index"`
+	FirstRespondedAt        *time.Time `json:"first_responded_at,omitempty" gorm:"column:first_responded_at"`
+	NextResponseDueAt       *time.Time `json:"next_response_due_at,omitempty" gorm:"column:next_response_due_at;index"`
+	ResolutionDueAt         *time.Time `json:"resolution_due_at,omitempty" gorm:"column:resolution_due_at;index"`
+	SLAPausedAt             *time.Time `json:"sla_paused_at,omitempty" gorm:"column:sla_paused_at"`
+	FirstResponseBreachedAt *time.Time `json:"first_response_breached_at,omitempty" gorm:"column:first_response_breached_at"`
+	NextResponseBreachedAt  *time.Time `json:"next_response_breached_at,omitempty" gorm:"column:next_response_breached_at"`
+	ResolutionBreachedAt    *time.Time `json:"resolution_breached_at,omitempty" gorm:"column:resolution_breached_at"`
+	SLAAtRisk               bool       `json:"sla_at_risk" gorm:"-"`
+	SLABreached             bool       `json:"sla_breached" gorm:"-"`
 
 	// Relasi - Add references to match custom column names
 	User        User               `gorm:"foreignKey:UserID"`
 	Category    *TicketCategory    `json:"category,omitempty" gorm:"foreignKey:CategoryID"`
 	Priority    *TicketPriority    `json:"priority,omitempty" gorm:"foreignKey:PriorityID"`
 	Status      *TicketStatus      `json:"status,omitempty" gorm:"foreignKey:StatusID"`
+	SLAPolicy   *SLAPolicy         `json:"sla_policy,omitempty" gorm:"foreignKey:SLAPolicyID"`
 	Comments    []TicketComment    `json:"comments,omitempty" gorm:"foreignKey:TicketID"`
 	Attachments []TicketAttachment `json:"attachments,omitempty" gorm:"foreignKey:TicketID"`
 	Assignments []TicketAssignment `json:"assignments,omitempty" gorm:"foreignKey:TicketID"`
 	Logs        []TicketLog        `json:"logs,omitempty" gorm:"foreignKey:TicketID"`
+	Tags        []Tag              `json:"tags,omitempty" gorm:"many2many:ticket_tags"`
 }
@@ -8,6 +8,7 @@ type TicketAssignment struct {
 	AdminID           int       `json:"id_admin" gorm:"column:id_admin"`
 	PriorityID        *int      `json:"priority_id,omitempty" gorm:"column:priority_id"`
 	TanggalDitugaskan time.Time `json:"tanggal_ditugaskan" gorm:"column:tanggal_ditugaskan;default:CURRENT_TIMESTAMP"`
+	Version           int       `json:"version" gorm:"column:version;not null;default:1"` // raised on every update
 
 	// Relasi
 	Ticket   *Ticket         `json:"ticket,omitempty" gorm:"foreignKey:TicketID"`
@@ -0,0 +1,9 @@
+package models
+
+// TicketCodeSequence is the last number handed out for a ticket code prefix in a year. It
+// is only advanced inside the transaction creating the ticket, so the codes have no gaps.
+type TicketCodeSequence struct {
+	Prefix    string `json:"prefix" gorm:"column:prefix;type:varchar(20);primaryKey"`
+	Year      int    `json:"year" gorm:"column:year;primaryKey;autoIncrement:false"`
+	LastValue int    `json:"last_value" gorm:"column:last_value;not null;default:0"`
+}
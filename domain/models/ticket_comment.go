@@ -8,6 +8,10 @@ type TicketComment struct {
 	UserID        int       `json:"id_user" gorm:"index"`
 	IsiPesan      string    `json:"isi_pesan" gorm:"column:isi_pesan"`
 	TanggalDibuat time.Time `json:"tanggal_dibuat" gorm:"column:tanggal_dibuat;default:CURRENT_TIMESTAMP"`
+	Version       int       `json:"version" gorm:"column:version;not null;default:1"` // raised on every update
+
+	// CascadeToChildren posts the same comment on the open child tickets, not stored
+	CascadeToChildren bool `json:"cascade_to_children,omitempty" gorm:"-"`
 
 	// Relasi
 	Ticket *Ticket `json:"ticket,omitempty" gorm:"foreignKey:TicketID;"`
@@ -0,0 +1,32 @@
+package models
+
+import "time"
+
+// TicketLink relates two tickets. For LinkTypeParent the source ticket is the parent of
+// the target, for LinkTypeBlocks the source blocks the target and LinkTypeRelated has
+// no direction.
+type TicketLink struct {
+	ID             int            `json:"id_link" gorm:"column:id_link;primaryKey"`
+	SourceTicketID int            `json:"id_ticket_source" gorm:"column:id_ticket_source;not null;uniqueIndex:idx_ticket_link"`
+	TargetTicketID int            `json:"id_ticket_target" gorm:"column:id_ticket_target;not null;index;uniqueIndex:idx_ticket_link"`
+	LinkType       TicketLinkType `json:"link_type" gorm:"column:link_type;type:varchar(20);not null"`
+	CreatedBy      *int           `json:"created_by,omitempty" gorm:"column:created_by"`
+	CreatedAt      time.Time      `json:"created_at" gorm:"column:created_at;default:CURRENT_TIMESTAMP"`
+
+	// Relasi
+	SourceTicket *Ticket `json:"source_ticket,omitempty" gorm:"foreignKey:SourceTicketID"`
+	TargetTicket *Ticket `json:"target_ticket,omitempty" gorm:"foreignKey:TargetTicketID"`
+}
+
+type TicketLinkType string
+
+const (
+	LinkTypeParent  TicketLinkType = "parent"
+	LinkTypeRelated TicketLinkType = "related"
+	LinkTypeBlocks  TicketLinkType = "blocks"
+
+	// LinkTypeChild and LinkTypeBlockedBy describe a link from the source ticket's side,
+	// they are stored as the reversed parent and blocks links
+	LinkTypeChild     TicketLinkType = "child"
+	LinkTypeBlockedBy TicketLinkType = "blocked_by"
+)
@@ -3,13 +3,55 @@ package models
 import "time"
 
 type TicketLog struct {
-	ID     int       `json:"id_log" gorm:"column:id_log;primaryKey"`
-	TicketID  int       `json:"id_ticket" gorm:"column:id_ticket;index"`
-	Aktivitas string    `json:"aktivitas" gorm:"column:aktivitas"`
-	UserID    int       `json:"id_user" gorm:"column:id_user"`
-	Waktu     time.Time `json:"waktu" gorm:"column:waktu;default:CURRENT_TIMESTAMP"`
+	ID        int             `json:"id_log" gorm:"column:id_log;primaryKey"`
+	TicketID  int             `json:"id_ticket" gorm:"column:id_ticket;index"`
+	Aktivitas string          `json:"aktivitas" gorm:"column:aktivitas"`
+	Action    TicketLogAction `json:"action" gorm:"column:action;type:varchar(50);not null;default:'note';index"`
+	Field     string          `json:"field,omitempty" gorm:"column:field;type:varchar(100)"`
+	OldValue  string          `json:"old_value,omitempty" gorm:"column:old_value;type:text"`
+	NewValue  string          `json:"new_value,omitempty" gorm:"column:new_value;type:text"`
+	IsSystem  bool            `json:"is_system" gorm:"column:is_system;not null;default:false"` // written by the services, read-only
+	UserID    *int            `json:"id_user" gorm:"column:id_user"`                            // nil for entries written by the system
+	Waktu     time.Time       `json:"waktu" gorm:"column:waktu;default:CURRENT_TIMESTAMP"`
 
 	// Relasi
 	Ticket *Ticket `json:"ticket,omitempty" gorm:"foreignKey:TicketID;"`
 	User   *User   `json:"user" gorm:"foreignKey:UserID"`
 }
+
+// TicketLogAction tells which mutation a log entry records, notes are written by hand
+type TicketLogAction string
+
+const (
+	TicketLogNote              TicketLogAction = "note"
+	TicketLogTicketCreated     TicketLogAction = "ticket_created"
+	TicketLogTicketUpdated     TicketLogAction = "ticket_updated"
+	TicketLogStatusChanged     TicketLogAction = "status_changed"
+	TicketLogTicketDeleted     TicketLogAction = "ticket_deleted"
+	TicketLogTicketRestored    TicketLogAction = "ticket_restored"
+	TicketLogTicketMerged      TicketLogAction = "ticket_merged"
+	TicketLogTicketReopened    TicketLogAction = "ticket_reopened"
+	TicketLogTicketSnoozed     TicketLogAction = "ticket_snoozed"
+	TicketLogTicketWoken       TicketLogAction = "ticket_woken"
+	TicketLogTicketImported    TicketLogAction = "ticket_imported"
+	TicketLogBulkUpdated       TicketLogAction = "bulk_updated"
+	TicketLogAssignmentCreated TicketLogAction = "assignment_created"
+	TicketLogAssignmentUpdated TicketLogAction = "assignment_updated"
+	TicketLogAssignmentDeleted TicketLogAction = "assignment_deleted"
+	TicketLogCommentCreated    TicketLogAction = "comment_created"
+	TicketLogCommentUpdated    TicketLogAction = "comment_updated"
+	TicketLogCommentDeleted    TicketLogAction = "comment_deleted"
+	TicketLogAttachmentCreated TicketLogAction = "attachment_created"
+	TicketLogAttachmentUpdated TicketLogAction = "attachment_updated"
+	TicketLogAttachmentDeleted TicketLogAction = "attachment_deleted"
+	TicketLogLinkAdded         TicketLogAction = "link_added"
+	TicketLogLinkRemoved       TicketLogAction = "link_removed"
+	TicketLogTagAdded          TicketLogAction = "tag_added"
+	TicketLogTagRemoved        TicketLogAction = "tag_removed"
+	TicketLogSLABreached       TicketLogAction = "sla_breached"
+	TicketLogEscalated         TicketLogAction = "ticket_escalated"
+	TicketLogAutoCloseWarning  TicketLogAction = "auto_close_warning"
+	TicketLogAutoClosed        TicketLogAction = "auto_closed"
+	TicketLogCSATSent          TicketLogAction = "csat_sent"
+	TicketLogCSATRated         TicketLogAction = "csat_rated"
+)
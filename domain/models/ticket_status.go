@@ -3,6 +3,16 @@ package models
 type TicketStatus struct {
 	ID         int    `json:"id_status" gorm:"column:id_status;primaryKey"`
 	NamaStatus string `json:"nama_status" gorm:"column:nama_status;type:varchar(50)"`
+	IsInitial  bool   `json:"is_initial" gorm:"column:is_initial;default:false"`
+	IsTerminal bool   `json:"is_terminal" gorm:"column:is_terminal;default:false"`
+	// PausesSLA stops the SLA clocks while a ticket stays in this status (e.g. resolved, closed)
+	PausesSLA bool `json:"pauses_sla" gorm:"column:pauses_sla;default:false"`
+	// IsResolved marks the status a ticket enters once support solved it, the customer is
+	// then asked to rate the support
+	IsResolved bool `json:"is_resolved" gorm:"column:is_resolved;default:false"`
+	// IsPending marks the status a snoozed ticket waits in until it wakes up
+	IsPending bool `json:"is_pending" gorm:"column:is_pending;default:false"`
 
-	Tickets []Ticket `json:"tickets,omitempty" gorm:"foreignKey:StatusID;"`
+	Tickets     []Ticket                 `json:"tickets,omitempty" gorm:"foreignKey:StatusID;"`
+	Transitions []TicketStatusTransition `json:"transitions,omitempty" gorm:"foreignKey:FromStatusID;"`
 }
@@ -0,0 +1,39 @@
+package models
+
+// TicketStatusTransition is an edge of the ticket workflow: a ticket in FromStatus
+// may move to ToStatus when Event happens and the actor has one of AllowedRoles
+// (an empty list allows every role).
+type TicketStatusTransition struct {
+	ID           int           `json:"id_transition" gorm:"column:id_transition;primaryKey"`
+	FromStatusID int           `json:"from_status_id" gorm:"column:from_status_id;not null;uniqueIndex:idx_ticket_status_transition"`
+	ToStatusID   int           `json:"to_status_id" gorm:"column:to_status_id;not null;uniqueIndex:idx_ticket_status_transition"`
+	Event        WorkflowEvent `json:"event" gorm:"column:event;type:varchar(50);not null;default:'manual';uniqueIndex:idx_ticket_status_transition"`
+	AllowedRoles []UserRole    `json:"allowed_roles" gorm:"column:allowed_roles;type:text;serializer:json"`
+
+	// Relasi
+	FromStatus *TicketStatus `json:"from_status,omitempty" gorm:"foreignKey:FromStatusID"`
+	ToStatus   *TicketStatus `json:"to_status,omitempty" gorm:"foreignKey:ToStatusID"`
+}
+
+type WorkflowEvent string
+
+const (
+	// WorkflowEventManual is a status change requested explicitly through the ticket API
+	WorkflowEventManual WorkflowEvent = "manual"
+	// WorkflowEventAssign fires when a ticket is assigned to a support user
+	WorkflowEventAssign WorkflowEvent = "assign"
+	// WorkflowEventComment fires when support replies to a ticket
+	WorkflowEventComment WorkflowEvent = "comment"
+	// WorkflowEventMerge fires on a duplicate ticket when it is merged into a primary ticket
+	WorkflowEventMerge WorkflowEvent = "merge"
+	// WorkflowEventCustomerReply fires when the ticket owner comments on the ticket
+	WorkflowEventCustomerReply WorkflowEvent = "customer_reply"
+	// WorkflowEventAutoClose fires when the customer did not reply within the confirmation window
+	WorkflowEventAutoClose WorkflowEvent = "auto_close"
+	// WorkflowEventReopen fires when the ticket owner reopens a ticket whose problem came back
+	WorkflowEventReopen WorkflowEvent = "reopen"
+	// WorkflowEventSnooze fires when support parks a ticket until a wake-up time
+	WorkflowEventSnooze WorkflowEvent = "snooze"
+	// WorkflowEventWake fires when a snoozed ticket's timer expires or the customer replies
+	WorkflowEventWake WorkflowEvent = "wake"
+)
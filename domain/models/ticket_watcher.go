@@ -0,0 +1,16 @@
+package models
+
+import "time"
+
+// TicketWatcher is a user following a ticket, watchers are notified of comments,
+// status changes and assignments
+type TicketWatcher struct {
+	ID        int       `json:"id_watcher" gorm:"column:id_watcher;primaryKey"`
+	TicketID  int       `json:"id_ticket" gorm:"column:id_ticket;not null;uniqueIndex:idx_ticket_watcher"`
+	UserID    uint64    `json:"id_user" gorm:"column:id_user;not null;uniqueIndex:idx_ticket_watcher;index"`
+	CreatedAt time.Time `json:"created_at" gorm:"column:created_at;default:CURRENT_TIMESTAMP"`
+
+	// Relasi
+	Ticket *Ticket `json:"ticket,omitempty" gorm:"foreignKey:TicketID"`
+	User   *User   `json:"user,omitempty" gorm:"foreignKey:UserID"`
+}
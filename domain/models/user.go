@@ -27,4 +27,11 @@ const (
 	RoleSeller   UserRole = "seller"
 	RoleCustomer UserRole = "customer"
 	RoleSupport  UserRole = "support"
+
+	// RoleSystem is the role of SystemUser, no stored user has it
+	RoleSystem UserRole = "system"
 )
+
+// SystemUser is the actor of background jobs. It has no user ID and passes the role guards
+// of the ticket workflow.
+var SystemUser = User{Role: RoleSystem}
@@ -0,0 +1,30 @@
+package domain
+
+import "app/domain/models"
+
+// CanAccessTicket is the ownership policy for a ticket and everything attached to it:
+// admins may access any ticket, other users only the tickets they own or are assigned to.
+// assignment is nil for an unassigned ticket.
+func CanAccessTicket(user models.User, ticket *models.Ticket, assignment *models.TicketAssignment) bool {
+	switch {
+	case user.Role == models.RoleAdmin:
+		return true
+	case user.ID == 0 || ticket == nil:
+		return false
+	case ticket.UserID == user.ID:
+		return true
+	case assignment != nil && uint64(assignment.AdminID) == user.ID:
+		return true
+	default:
+		return false
+	}
+}
+
+// CanWatchTicket is the policy for following a ticket: admins and support users may follow
+// any ticket, other users the tickets they may access, see CanAccessTicket
+func CanWatchTicket(user models.User, ticket *models.Ticket, assignment *models.TicketAssignment) bool {
+	if user.Role == models.RoleSupport {
+		return user.ID != 0 && ticket != nil
+	}
+	return CanAccessTicket(user, ticket, assignment)
+}
@@ -0,0 +1,69 @@
+package domain
+
+import (
+	"app/domain/models"
+	"testing"
+)
+
+func TestCanAccessTicket(t *testing.T) {
+	ticket := &models.Ticket{ID: 1, UserID: 10}
+	assignment := &models.TicketAssignment{TicketID: 1, AdminID: 20}
+
+	tests := []struct {
+		name       string
+		user       models.User
+		ticket     *models.Ticket
+		assignment *models.TicketAssignment
+		want       bool
+	}{
+		{"admin", models.User{ID: 1, Role: models.RoleAdmin}, ticket, assignment, true},
+		{"admin without ticket", models.User{ID: 1, Role: models.RoleAdmin}, nil, nil, true},
+		{"owner", models.User{ID: 10, Role: models.RoleCustomer}, ticket, assignment, true},
+		{"owner of unassigned ticket", models.User{ID: 10, Role: models.RoleCustomer}, ticket, nil, true},
+		{"assignee", models.User{ID: 20, Role: models.RoleSupport}, ticket, assignment, true},
+		{"unrelated customer", models.User{ID: 30, Role: models.RoleCustomer}, ticket, assignment, false},
+		{"unrelated seller", models.User{ID: 30, Role: models.RoleSeller}, ticket, assignment, false},
+		{"unrelated support", models.User{ID: 30, Role: models.RoleSupport}, ticket, assignment, false},
+		{"support on unassigned ticket", models.User{ID: 20, Role: models.RoleSupport}, ticket, nil, false},
+		{"zero user ID", models.User{Role: models.RoleCustomer}, &models.Ticket{ID: 2}, &models.TicketAssignment{TicketID: 2}, false},
+		{"nil ticket", models.User{ID: 10, Role: models.RoleCustomer}, nil, assignment, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := CanAccessTicket(tt.user, tt.ticket, tt.assignment); got != tt.want {
+				t.Errorf("CanAccessTicket() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestCanWatchTicket(t *testing.T) {
+	ticket := &models.Ticket{ID: 1, UserID: 10}
+	assignment := &models.TicketAssignment{TicketID: 1, AdminID: 20}
+
+	tests := []struct {
+		name       string
+		user       models.User
+		ticket     *models.Ticket
+		assignment *models.TicketAssignment
+		want       bool
+	}{
+		{"admin", models.User{ID: 1, Role: models.RoleAdmin}, ticket, assignment, true},
+		{"unrelated support", models.User{ID: 30, Role: models.RoleSupport}, ticket, assignment, true},
+		{"support on unassigned ticket", models.User{ID: 30, Role: models.RoleSupport}, ticket, nil, true},
+		{"support without ticket", models.User{ID: 30, Role: models.RoleSupport}, nil, nil, false},
+		{"owning seller", models.User{ID: 10, Role: models.RoleSeller}, ticket, assignment, true},
+		{"assigned seller", models.User{ID: 20, Role: models.RoleSeller}, ticket, assignment, true},
+		{"unrelated seller", models.User{ID: 30, Role: models.RoleSeller}, ticket, assignment, false},
+		{"unrelated customer", models.User{ID: 30, Role: models.RoleCustomer}, ticket, assignment, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := CanWatchTicket(tt.user, tt.ticket, tt.assignment); got != tt.want {
+				t.Errorf("CanWatchTicket() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
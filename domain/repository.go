@@ -8,16 +8,20 @@ import (
 )
 
 type AppRepository interface {
+	// WithTransaction runs fn with a repository bound to a single database transaction
+	WithTransaction(fn func(repo AppRepository) error) error
+
 	// User operations
 	GetUserByID(id uint64) (*models.User, error)
+	GetUserByUsernameOrEmail(login string) (*models.User, error)
 	CreateUser(user *models.User) error
 	GetUsersByRole(role models.UserRole) ([]models.User, error)
 
 	// Conversation operations
 	CreateConversation(ctx context.Context, conversation *models.Conversation) error
 	GetConversationByID(conversationID uint64) (*models.Conversation, error)
-	GetAdminConversations(adminID uint64) ([]models.Conversation, error)
-	GetCustomerConversations(userID uint64) ([]models.Conversation, error)
+	GetAdminConversations(adminID uint64, tags TagFilter) ([]models.Conversation, error)
+	GetCustomerConversations(userID uint64, tags TagFilter) ([]models.Conversation, error)
 	UpdateConversationLastMessage(conversationID uint64) error
 	CloseConversation(ctx context.Context, conversationID uint64) error
 	ReopenConversation(ctx context.Context, conversationID uint64) error
@@ -46,6 +50,7 @@ type AppRepository interface {
 	// Ticket notifications
 	GetOpenTicketCountsByType() (customerCount int, sellerCount int, err error)
 	GetTicketStatistics() (total int, inProgress int, resolved int, priorityCounts map[int]int, err error)
+	GetTicketReopenStatistics() (reopenedTickets int, totalReopens int, err error)
 
 	// Ticket Category
 	CreateTicketCategory(category *models.TicketCategory) error
@@ -55,6 +60,13 @@ type AppRepository interface {
 	DeleteTicketCategory(id int) error
 	GetTicketsPaginated(limit, offset int) ([]models.Ticket, int, error)
 
+	// Custom Field
+	CreateCustomFieldDefinition(definition *models.CustomFieldDefinition) error
+	GetCustomFieldDefinitions(categoryID int) ([]models.CustomFieldDefinition, error)
+	GetCustomFieldDefinitionByID(id int) (*models.CustomFieldDefinition, error)
+	UpdateCustomFieldDefinition(definition *models.CustomFieldDefinition) error
+	DeleteCustomFieldDefinition(id int) error
+
 	// Ticket Priority
 	CreateTicketPriority(priority *models.TicketPriority) error
 	GetTicketPriorities() ([]models.TicketPriority, error)
@@ -68,15 +80,107 @@ type AppRepository interface {
 	GetTicketStatusByID(id int) (*models.TicketStatus, error)
 	UpdateTicketStatus(status *models.TicketStatus) error
 	DeleteTicketStatus(id int) error
+	GetInitialTicketStatus() (*models.TicketStatus, error)
+
+	// Ticket Workflow
+	CreateTicketStatusTransition(transition *models.TicketStatusTransition) error
+	GetTicketStatusTransitions(fromStatusID int) ([]models.TicketStatusTransition, error)
+	GetTicketStatusTransition(fromStatusID, toStatusID int, event models.WorkflowEvent) (*models.TicketStatusTransition, error)
+	GetTicketStatusTransitionByEvent(fromStatusID int, event models.WorkflowEvent) (*models.TicketStatusTransition, error)
+	DeleteTicketStatusTransition(id int) error
+	SeedTicketWorkflow() error
 
 	// Ticket
 	CreateTicket(ticket *models.Ticket) error
 	GetTickets() ([]models.Ticket, error)
 	GetTicketByID(id int) (*models.Ticket, error)
+	GetTicketByCode(code string) (*models.Ticket, error)
+	NextTicketCodeSequence(prefix string, year int) (int, error)
+	AdvanceTicketCodeSequence(prefix string, year, sequence int) error
 	GetTicketsByUserID(userID int) ([]models.Ticket, error)
 	UpdateTicket(ticket *models.Ticket) error
 	DeleteTicket(id int) error
-	GetTicketsCursor(limit int, cursor string, tipePengaduan string, statusID, priorityID, categoryID int) ([]models.Ticket, string, error)
+	GetDeletedTickets() ([]models.Ticket, error)
+	RestoreTicket(id int) error
+	GetTicketsDeletedBefore(cutoff time.Time) ([]models.Ticket, error)
+	PurgeTicket(id int) ([]string, error)
+	GetTicketsAwaitingAutoClose(resolvedBefore time.Time) ([]models.Ticket, error)
+	MarkTicketAutoCloseWarned(ticketID int, at time.Time) (bool, error)
+	GetTicketsToWake(now time.Time) ([]models.Ticket, error)
+	MoveTicketRecords(fromTicketID, toTicketID int) error
+	GetTicketsCursor(limit int, cursor string, filter TicketFilter) ([]models.Ticket, string, error)
+	SearchTickets(query string, limit int, cursor string, filter TicketFilter) ([]TicketSearchResult, string, error)
+	CreateTicketSearchIndexes() error
+	StreamTicketExport(filter TicketFilter, fn func(row TicketExportRow) error) error
+	CountTickets(filter TicketFilter) (int64, error)
+	CountTicketsPerFilter(filters []TicketFilter) ([]int64, error)
+
+	// Tag
+	CreateTag(tag *models.Tag) error
+	GetTags() ([]models.Tag, error)
+	GetTagByID(id int) (*models.Tag, error)
+	UpdateTag(tag *models.Tag) error
+	DeleteTag(id int) error
+	AddTicketTag(ticketID int, tag *models.Tag) error
+	RemoveTicketTag(ticketID int, tagID int) error
+	AddConversationTag(conversationID uint64, tag *models.Tag) error
+	RemoveConversationTag(conversationID uint64, tagID int) error
+
+	// Ticket Watcher
+	AddTicketWatcher(watcher *models.TicketWatcher) error
+	RemoveTicketWatcher(ticketID int, userID uint64) error
+	GetTicketWatchers(ticketID int) ([]models.TicketWatcher, error)
+
+	// Ticket Link
+	CreateTicketLink(link *models.TicketLink) error
+	GetTicketLinkByID(id int) (*models.TicketLink, error)
+	GetTicketLinksByTicketID(ticketID int) ([]models.TicketLink, error)
+	GetTicketLinkBetween(ticketID, otherTicketID int) (*models.TicketLink, error)
+	GetParentTicketID(ticketID int) (*int, error)
+	GetChildTickets(parentID int) ([]models.Ticket, error)
+	DeleteTicketLink(id int) error
+
+	// SLA Policy
+	CreateSLAPolicy(policy *models.SLAPolicy) error
+	GetSLAPolicies() ([]models.SLAPolicy, error)
+	GetActiveSLAPolicies() ([]models.SLAPolicy, error)
+	GetSLAPolicyByID(id int) (*models.SLAPolicy, error)
+	UpdateSLAPolicy(policy *models.SLAPolicy) error
+	DeleteSLAPolicy(id int) error
+	GetTicketsWithUnrecordedSLABreach(now time.Time) ([]models.Ticket, error)
+	MarkTicketSLABreached(ticketID int, target models.SLATarget, at time.Time) (bool, error)
+
+	// Escalation Rule
+	CreateEscalationRule(rule *models.EscalationRule) error
+	GetEscalationRules() ([]models.EscalationRule, error)
+	GetActiveEscalationRules() ([]models.EscalationRule, error)
+	GetEscalationRuleByID(id int) (*models.EscalationRule, error)
+	UpdateEscalationRule(rule *models.EscalationRule) error
+	DeleteEscalationRule(id int) error
+	GetTicketsToEscalate(rule *models.EscalationRule, idleSince time.Time) ([]models.Ticket, error)
+	RecordTicketEscalation(ticketID, ruleID int, at time.Time) (bool, error)
+
+	// Saved Ticket View
+	CreateSavedTicketView(view *models.SavedTicketView) error
+	GetSavedTicketViews(userID uint64) ([]models.SavedTicketView, error)
+	GetSavedTicketViewByID(id int) (*models.SavedTicketView, error)
+	DeleteSavedTicketView(id int) error
+
+	// CSAT Survey
+	CreateCSATSurvey(survey *models.CSATSurvey) error
+	GetCSATSurveyByID(id int) (*models.CSATSurvey, error)
+	AnswerCSATSurvey(id int, rating int, comment string, at time.Time) (bool, error)
+	GetCSATAggregates(filter CSATFilter) ([]CSATAggregate, error)
+
+	// Business Calendar
+	CreateBusinessCalendar(calendar *models.BusinessCalendar) error
+	GetBusinessCalendars() ([]models.BusinessCalendar, error)
+	GetBusinessCalendarByID(id int) (*models.BusinessCalendar, error)
+	GetDefaultBusinessCalendar() (*models.BusinessCalendar, error)
+	UpdateBusinessCalendar(calendar *models.BusinessCalendar) error
+	DeleteBusinessCalendar(id int) error
+	CreateHoliday(holiday *models.Holiday) error
+	DeleteHoliday(calendarID, holidayID int) error
 
 	// Ticket Assignment
 	CreateTicketAssignment(assignment *models.TicketAssignment) error
@@ -110,6 +214,9 @@ type AppRepository interface {
 	GetTicketLogs() ([]models.TicketLog, error)
 	GetTicketLogByID(id int) (*models.TicketLog, error)
 	GetTicketLogsByTicketID(ticketID int) ([]models.TicketLog, error)
+
+	// Ticket Timeline
+	GetTicketTimeline(ticketID int, types []TimelineEventType, limit int, cursor string) ([]TimelineEvent, string, error)
 }
 
 type S3Repository interface {
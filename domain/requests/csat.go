@@ -0,0 +1,22 @@
+package requests
+
+import "time"
+
+// CSATAnswerRequest rates the support through a signed survey link
+type CSATAnswerRequest struct {
+	Token   string `json:"token" binding:"required"`
+	Rating  int    `json:"rating" binding:"required" example:"5"`
+	Comment string `json:"comment" example:"Masalah saya cepat diselesaikan"`
+}
+
+// CSATSurveyResponse is the survey a link points to, without the customer details
+type CSATSurveyResponse struct {
+	SurveyID    int        `json:"id_survey"`
+	KodeTiket   string     `json:"kode_tiket"`
+	Judul       string     `json:"judul"`
+	AgentName   string     `json:"agent_name,omitempty"`
+	ExpiresAt   time.Time  `json:"expires_at"`
+	Rating      *int       `json:"rating,omitempty"`
+	Comment     string     `json:"comment,omitempty"`
+	RespondedAt *time.Time `json:"responded_at,omitempty"`
+}
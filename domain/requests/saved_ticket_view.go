@@ -0,0 +1,12 @@
+package requests
+
+import "app/domain/models"
+
+// CreateSavedTicketViewRequest is a ticket list filter an agent saves under a name, a
+// shared view is listed for every agent
+type CreateSavedTicketViewRequest struct {
+	NamaView string                  `json:"nama_view" binding:"required"`
+	Filter   models.TicketViewFilter `json:"filter"`
+	Sort     models.TicketSort       `json:"sort"` // newest (default), oldest or updated
+	IsShared bool                    `json:"is_shared"`
+}
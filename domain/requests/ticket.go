@@ -1,6 +1,9 @@
 package requests
 
-import "app/domain/models"
+import (
+	"app/domain/models"
+	"time"
+)
 
 type TicketCreateRequest struct {
 	KodeTiket  string `json:"kode_tiket" example:"TCKT-001"`
@@ -9,7 +12,9 @@ type TicketCreateRequest struct {
 	Deskripsi  string `json:"deskripsi" example:"Cannot access my account after password reset"`
 	CategoryID int    `json:"id_category" example:"1" description:"1=Technical Issue, 2=Account Problem, 3=Payment Issue"`
 	PriorityID int    `json:"id_priority" example:"2" description:"1=Low, 2=Medium, 3=High, 4=Critical"`
-	StatusID   int    `json:"id_status" example:"1" description:"1=Open, 2=In Progress, 3=Resolved, 4=Closed"`
+	StatusID   int    `json:"id_status" example:"1" description:"Target status, must be allowed by the ticket workflow (0 keeps the current status)"`
+	// CustomFields holds the values of the custom fields defined for the category, by key
+	CustomFields models.CustomFieldValues `json:"custom_fields,omitempty" swaggertype:"object"`
 	// Note: tipe_pengaduan will be auto-filled based on authenticated user's role
 	TipePengaduan models.UserRole `json:"tipe_pengaduan,omitempty" swaggerignore:"true"`
 }
@@ -27,4 +32,84 @@ type TicketResponse struct {
 	TipePengaduan     models.UserRole `json:"tipe_pengaduan"`
 	TanggalDibuat     string `json:"tanggal_dibuat"`
 	TanggalDiperbarui string `json:"tanggal_diperbarui"`
+	Version           int    `json:"version"`
+	ReopenCount       int    `json:"reopen_count"`
+	PendingUntil      *time.Time `json:"pending_until,omitempty"`
+	PendingReason     string     `json:"pending_reason,omitempty"`
+	SLA               *TicketSLAResponse `json:"sla,omitempty"`
+	MergedIntoID      *int               `json:"merged_into_id,omitempty"`
+	Links             []TicketLinkResponse `json:"links,omitempty"`
+	Tags              []models.Tag         `json:"tags,omitempty"`
+	CustomFields      models.CustomFieldValues `json:"custom_fields,omitempty" swaggertype:"object"`
+	DeletedAt         *time.Time               `json:"deleted_at,omitempty"`
+}
+
+// TicketMergeRequest names the duplicate ticket merged into the ticket in the path
+type TicketMergeRequest struct {
+	SecondaryTicketID int `json:"id_ticket_secondary" binding:"required" example:"42"`
+}
+
+// TicketReopenRequest explains why the problem of a resolved ticket came back
+type TicketReopenRequest struct {
+	Reason string `json:"reason" binding:"required" example:"The payment failed again after the fix"`
+}
+
+// TicketSnoozeRequest parks a ticket until pending_until while support waits on someone else
+type TicketSnoozeRequest struct {
+	PendingUntil time.Time `json:"pending_until" binding:"required" example:"2026-01-31T09:00:00+07:00"`
+	Reason       string    `json:"reason" binding:"required" example:"Waiting for the courier to confirm the delivery"`
+}
+
+// TicketBulkRequest applies one action to a list of up to 500 distinct tickets, only the field of the
+// chosen action is used
+type TicketBulkRequest struct {
+	TicketIDs  []int  `json:"ticket_ids" binding:"required,min=1,max=500,unique" example:"1,2,3"`
+	Action     string `json:"action" binding:"required" enums:"status,priority,category,assign,delete" example:"status"`
+	StatusID   int    `json:"id_status,omitempty" example:"2"`
+	PriorityID int    `json:"id_priority,omitempty" example:"3"`
+	CategoryID int    `json:"id_category,omitempty" example:"1"`
+	AdminID    int    `json:"id_admin,omitempty" example:"2"`
+}
+
+// TicketSearchResponse represents a ticket search hit with its relevance and highlighted snippet,
+// the snippet is escaped HTML with the matches wrapped in <mark>
+type TicketSearchResponse struct {
+	TicketResponse
+	Rank    float64 `json:"rank"`
+	Snippet string  `json:"snippet"`
+}
+
+// TicketSLAResponse represents the SLA due timestamps and flags of a ticket
+type TicketSLAResponse struct {
+	PolicyID           *int       `json:"sla_policy_id,omitempty"`
+	FirstResponseDueAt *time.Time `json:"first_response_due_at,omitempty"`
+	FirstRespondedAt   *time.Time `json:"first_responded_at,omitempty"`
+	NextResponseDueAt  *time.Time `json:"next_response_due_at,omitempty"`
+	ResolutionDueAt    *time.Time `json:"resolution_due_at,omitempty"`
+	Paused             bool       `json:"paused"`
+	AtRisk             bool       `json:"at_risk"`
+	Breached           bool       `json:"breached"`
+}
+
+// TicketLinkCreateRequest links another ticket, link_type is what that ticket is to
+// the ticket in the path: parent, child, related, blocks or blocked_by
+type TicketLinkCreateRequest struct {
+	TicketID int                   `json:"id_ticket" binding:"required" example:"42"`
+	LinkType models.TicketLinkType `json:"link_type" binding:"required" example:"child"`
+}
+
+// TicketLinkResponse represents a linked ticket, link_type is what the linked ticket is
+// to the ticket it is listed on
+type TicketLinkResponse struct {
+	LinkID    int                   `json:"id_link"`
+	LinkType  models.TicketLinkType `json:"link_type"`
+	TicketID  int                   `json:"id_ticket"`
+	KodeTiket string                `json:"kode_tiket"`
+	Judul     string                `json:"judul"`
+	StatusID  int                   `json:"id_status"`
+}
+
+// TagAssignRequest names the tag added to a ticket or conversation
+type TagAssignRequest struct {
+	TagID int `json:"id_tag" binding:"required" example:"1"`
 }
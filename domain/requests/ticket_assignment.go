@@ -15,6 +15,7 @@ type TicketAssignmentResponse struct {
 	AdminID           int                 `json:"id_admin"`
 	PriorityID        *int                `json:"priority_id,omitempty"`
 	TanggalDitugaskan string              `json:"tanggal_ditugaskan"`
+	Version           int                 `json:"version"`
 	Ticket            *TicketResponse     `json:"ticket,omitempty"`
 	Admin             *UserSimpleResponse `json:"admin,omitempty"`
 	Priority          *PriorityResponse   `json:"priority,omitempty"`
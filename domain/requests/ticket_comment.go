@@ -6,6 +6,10 @@ import "time"
 type CreateTicketCommentRequest struct {
 	TicketID int    `json:"ticket_id" binding:"required"`
 	IsiPesan string `json:"isi_pesan" binding:"required"`
+	// CascadeToChildren posts the comment on the open child tickets as well, moving them
+	// through the same workflow transition (e.g. resolving a parent resolves its children).
+	// Children the author may not access are skipped.
+	CascadeToChildren bool `json:"cascade_to_children" example:"false"`
 }
 
 // UpdateTicketCommentRequest is used for updating a ticket comment
@@ -46,4 +50,5 @@ type TicketCommentResponse struct {
 	UserID        int       `json:"id_user"`
 	IsiPesan      string    `json:"isi_pesan"`
 	TanggalDibuat time.Time `json:"tanggal_dibuat"`
+	Version       int       `json:"version"`
 }
@@ -0,0 +1,8 @@
+package requests
+
+// CreateTicketLogRequest is a note an admin adds to the activity log of a ticket, the
+// entries of the mutations themselves are written by the system
+type CreateTicketLogRequest struct {
+	TicketID  int    `json:"id_ticket" binding:"required"`
+	Aktivitas string `json:"aktivitas" binding:"required"`
+}
@@ -0,0 +1,9 @@
+package domain
+
+import "app/domain/models"
+
+// SavedTicketViewSummary is a saved view with the live count of the tickets it matches
+type SavedTicketViewSummary struct {
+	models.SavedTicketView
+	Count int64 `json:"count"`
+}
@@ -0,0 +1,12 @@
+package domain
+
+import "app/domain/models"
+
+// TicketSearchResult is a ticket matched by full-text search with its rank and
+// a highlighted fragment of the matching text. Snippet is HTML, the text is escaped
+// and the matches are wrapped in <mark>.
+type TicketSearchResult struct {
+	Ticket  models.Ticket
+	Rank    float64
+	Snippet string
+}
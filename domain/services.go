@@ -4,7 +4,10 @@ import (
 	"app/domain/models"
 	"app/helpers"
 	"context"
+	"io"
 	"mime/multipart"
+	"time"
+
 	"github.com/gin-gonic/gin"
 )
 
@@ -22,7 +25,7 @@ type AppService interface {
 	ServeWebSocket(ctx *gin.Context)
 
 	// Conversation management
-	GetConversations(claim models.User) helpers.Response
+	GetConversations(claim models.User, tags TagFilter) helpers.Response
 	GetConversationByID(conversationID uint64) (*models.Conversation, error)
 	CreateCustomerConversation(ctx context.Context, claim models.User) helpers.Response
 	CloseConversation(ctx context.Context, claim models.User, id string) helpers.Response
@@ -39,13 +42,22 @@ type AppService interface {
 	GetTicketCategories() ([]models.TicketCategory, error)
 	GetTicketCategoryByID(id int) (*models.TicketCategory, error)
 	UpdateTicketCategory(category *models.TicketCategory) error
+	PatchTicketCategory(id int, patch []byte) (*models.TicketCategory, error)
 	DeleteTicketCategory(id int) error
 
+	// Custom Field
+	CreateCustomFieldDefinition(definition *models.CustomFieldDefinition) error
+	GetCustomFieldDefinitions(categoryID int) ([]models.CustomFieldDefinition, error)
+	GetCustomFieldDefinitionByID(id int) (*models.CustomFieldDefinition, error)
+	UpdateCustomFieldDefinition(definition *models.CustomFieldDefinition) error
+	DeleteCustomFieldDefinition(categoryID, id int) error
+
 	// Ticket Priority
 	CreateTicketPriority(priority *models.TicketPriority) error
 	GetTicketPriorities() ([]models.TicketPriority, error)
 	GetTicketPriorityByID(id int) (*models.TicketPriority, error)
 	UpdateTicketPriority(priority *models.TicketPriority) error
+	PatchTicketPriority(id int, patch []byte) (*models.TicketPriority, error)
 	DeleteTicketPriority(id int) error
 
 	// Ticket Status
@@ -53,50 +65,133 @@ type AppService interface {
 	GetTicketStatuses() ([]models.TicketStatus, error)
 	GetTicketStatusByID(id int) (*models.TicketStatus, error)
 	UpdateTicketStatus(status *models.TicketStatus) error
+	PatchTicketStatus(id int, patch []byte) (*models.TicketStatus, error)
 	DeleteTicketStatus(id int) error
 
+	// Ticket Workflow
+	CreateTicketStatusTransition(transition *models.TicketStatusTransition) error
+	GetTicketStatusTransitions(fromStatusID int) ([]models.TicketStatusTransition, error)
+	DeleteTicketStatusTransition(id int) error
+
 	// Ticket
 	CreateTicket(ticket *models.Ticket) error
 	GetTickets() ([]models.Ticket, error)
 	GetTicketsPaginated(limit, offset int) ([]models.Ticket, int, error)
 	GetTicketByID(id int) (*models.Ticket, error)
+	GetTicketByCode(code string) (*models.Ticket, error)
 	GetTicketsByUserID(userID int) ([]models.Ticket, error)
-	GetTicketsCursor(limit int, cursor string, tipePengaduan string, statusID, priorityID, categoryID int) ([]models.Ticket, string, error)
-	UpdateTicket(ticket *models.Ticket) error
-	DeleteTicket(id int) error
+	GetTicketsCursor(limit int, cursor string, filter TicketFilter) ([]models.Ticket, string, error)
+	SearchTickets(query string, limit int, cursor string, filter TicketFilter, claim models.User) ([]TicketSearchResult, string, error)
+	ExportTickets(filter TicketFilter, format TicketExportFormat, w io.Writer) error
+	UpdateTicket(ticket *models.Ticket, claim models.User) error
+	PatchTicket(id int, patch []byte, version int, claim models.User) (*models.Ticket, error)
+	DeleteTicket(id int, claim models.User) error
+	GetDeletedTickets() ([]models.Ticket, error)
+	RestoreTicket(id int, claim models.User) (*models.Ticket, error)
+	PurgeDeletedTickets(retention time.Duration) error
+	AutoCloseResolvedTickets(days, warningDays int) error
+	ReopenTicket(id int, reason string, claim models.User) (*models.Ticket, error)
+	SnoozeTicket(id int, until time.Time, reason string, claim models.User) (*models.Ticket, error)
+	WakeTicket(id int, claim models.User) (*models.Ticket, error)
+	WakePendingTickets() error
+	MergeTickets(primaryID, secondaryID int, claim models.User) (*models.Ticket, error)
+	BulkUpdateTickets(op BulkTicketOperation, claim models.User) ([]BulkTicketResult, error)
+	ImportTickets(file io.Reader, mapping TicketImportMapping, dryRun bool, claim models.User) (*TicketImportResult, error)
+
+	// Tag
+	CreateTag(tag *models.Tag) error
+	GetTags() ([]models.Tag, error)
+	GetTagByID(id int) (*models.Tag, error)
+	UpdateTag(tag *models.Tag) error
+	DeleteTag(id int) error
+	AddTicketTag(ticketID, tagID int, claim models.User) (*models.Tag, error)
+	RemoveTicketTag(ticketID, tagID int, claim models.User) error
+	AddConversationTag(conversationID uint64, tagID int) (*models.Tag, error)
+	RemoveConversationTag(conversationID uint64, tagID int) error
+
+	// Ticket Watcher
+	WatchTicket(ticketID int, claim models.User) error
+	UnwatchTicket(ticketID int, claim models.User) error
+	GetTicketWatchers(ticketID int) ([]models.TicketWatcher, error)
+
+	// Ticket Link
+	CreateTicketLink(ticketID, otherTicketID int, linkType models.TicketLinkType, claim models.User) (*models.TicketLink, error)
+	GetTicketLinks(ticketID int) ([]models.TicketLink, error)
+	DeleteTicketLink(ticketID, linkID int, claim models.User) error
+
+	// SLA Policy
+	CreateSLAPolicy(policy *models.SLAPolicy) error
+	GetSLAPolicies() ([]models.SLAPolicy, error)
+	GetSLAPolicyByID(id int) (*models.SLAPolicy, error)
+	UpdateSLAPolicy(policy *models.SLAPolicy) error
+	DeleteSLAPolicy(id int) error
+	CheckSLABreaches() error
+
+	// Escalation Rule
+	CreateEscalationRule(rule *models.EscalationRule) error
+	GetEscalationRules() ([]models.EscalationRule, error)
+	GetEscalationRuleByID(id int) (*models.EscalationRule, error)
+	UpdateEscalationRule(rule *models.EscalationRule) error
+	DeleteEscalationRule(id int) error
+	EscalateTickets() error
+
+	// Saved Ticket View
+	CreateSavedTicketView(view *models.SavedTicketView, claim models.User) error
+	GetSavedTicketViews(claim models.User) ([]SavedTicketViewSummary, error)
+	DeleteSavedTicketView(id int, claim models.User) error
+	RunSavedTicketView(id int, limit int, cursor string, claim models.User) (*SavedTicketViewSummary, []models.Ticket, string, error)
+
+	// CSAT Survey
+	GetCSATSurvey(token string) (*models.CSATSurvey, error)
+	AnswerCSATSurvey(token string, rating int, comment string) (*models.CSATSurvey, error)
+	GetCSATReport(filter CSATFilter) ([]CSATAggregate, error)
+
+	// Business Calendar
+	CreateBusinessCalendar(calendar *models.BusinessCalendar) error
+	GetBusinessCalendars() ([]models.BusinessCalendar, error)
+	GetBusinessCalendarByID(id int) (*models.BusinessCalendar, error)
+	UpdateBusinessCalendar(calendar *models.BusinessCalendar) error
+	DeleteBusinessCalendar(id int) error
+	CreateHoliday(holiday *models.Holiday) error
+	DeleteHoliday(calendarID, holidayID int) error
 
 	// Ticket Assignment
-	CreateTicketAssignment(assignment *models.TicketAssignment) error
+	CreateTicketAssignment(assignment *models.TicketAssignment, claim models.User) error
 	GetTicketAssignments() ([]models.TicketAssignment, error)
 	GetTicketAssignmentByID(id int) (*models.TicketAssignment, error)
-	UpdateTicketAssignment(assignment *models.TicketAssignment) error
-	DeleteTicketAssignment(id int) error
+	UpdateTicketAssignment(assignment *models.TicketAssignment, claim models.User) error
+	PatchTicketAssignment(id int, patch []byte, version int, claim models.User) (*models.TicketAssignment, error)
+	DeleteTicketAssignment(id int, claim models.User) error
 	GetTicketAssignmentsByAdminIDCursor(adminID int, limit int, cursor string, statusName string) ([]models.TicketAssignment, string, error)
 	GetAssignedTicketCountByAdminID(adminID int) (int, error)
 	GetAssignedTicketCountByAdminIDAndStatus(adminID int, statusID int) (int, error)
 
 	// Ticket Attachment
-	CreateTicketAttachment(ticketID int, file *multipart.FileHeader) (*models.TicketAttachment, error)
+	CreateTicketAttachment(ticketID int, file *multipart.FileHeader, claim models.User) (*models.TicketAttachment, error)
 	GetTicketAttachments() ([]models.TicketAttachment, error)
 	GetTicketAttachmentByID(id int) (*models.TicketAttachment, string, error)
 	GetTicketAttachmentsByTicketID(ticketID int) ([]models.TicketAttachment, error)
-	UpdateTicketAttachment(id int, ticketID *int, file *multipart.FileHeader) (*models.TicketAttachment, error)
-	DeleteTicketAttachment(id int) error
+	UpdateTicketAttachment(id int, ticketID *int, file *multipart.FileHeader, claim models.User) (*models.TicketAttachment, error)
+	DeleteTicketAttachment(id int, claim models.User) error
 
 	// Ticket Comment
 	CreateTicketComment(comment *models.TicketComment) error
 	GetTicketComments() ([]models.TicketComment, error)
 	GetTicketCommentByID(id int) (*models.TicketComment, error)
 	GetTicketCommentsByTicketID(ticketID int) ([]models.TicketComment, error)
-	UpdateTicketComment(comment *models.TicketComment) error
-	DeleteTicketComment(id int) error
+	UpdateTicketComment(comment *models.TicketComment, claim models.User) error
+	PatchTicketComment(id int, patch []byte, version int, claim models.User) (*models.TicketComment, error)
+	DeleteTicketComment(id int, claim models.User) error
 
 	// Ticket Log
-	CreateTicketLog(log *models.TicketLog) error
+	CreateTicketLog(ticketID int, aktivitas string, claim models.User) (*models.TicketLog, error)
 	GetTicketLogs() ([]models.TicketLog, error)
 	GetTicketLogByID(id int) (*models.TicketLog, error)
 	GetTicketLogsByTicketID(ticketID int) ([]models.TicketLog, error)
 
+	// Ticket Timeline
+	GetTicketTimeline(ticketID int, types []TimelineEventType, limit int, cursor string, claim models.User) ([]TimelineEvent, string, error)
+
 	// Email
-	SendTicketCommentEmail(toEmail, userName, ticketId, ticketTitle, resolution string) error
+	SendTicketCommentEmail(toEmail, userName, ticketId, ticketTitle, resolution, surveyURL string) error
 }
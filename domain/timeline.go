@@ -0,0 +1,58 @@
+package domain
+
+import (
+	"app/domain/models"
+	"time"
+)
+
+// TimelineEventType is the kind of record a timeline event was built from
+type TimelineEventType string
+
+const (
+	TimelineEventComment      TimelineEventType = "comment"
+	TimelineEventLog          TimelineEventType = "log"
+	TimelineEventAttachment   TimelineEventType = "attachment"
+	TimelineEventAssignment   TimelineEventType = "assignment"
+	TimelineEventStatusChange TimelineEventType = "status_change"
+)
+
+// TimelineEventTypes lists every event type in the order they sort at the same instant
+var TimelineEventTypes = []TimelineEventType{
+	TimelineEventAssignment,
+	TimelineEventAttachment,
+	TimelineEventComment,
+	TimelineEventLog,
+	TimelineEventStatusChange,
+}
+
+// VisibleTimelineEventTypes are the event types a role may see on a ticket timeline. The
+// audit log and the assignments are internal to the support team.
+func VisibleTimelineEventTypes(role models.UserRole) []TimelineEventType {
+	if role == models.RoleAdmin || role == models.RoleSupport {
+		return TimelineEventTypes
+	}
+	return []TimelineEventType{TimelineEventAttachment, TimelineEventComment, TimelineEventStatusChange}
+}
+
+// TimelineActor is the public part of the user behind an event
+type TimelineActor struct {
+	ID       uint64          `json:"id"`
+	Username string          `json:"username"`
+	Role     models.UserRole `json:"role"`
+}
+
+// TimelineEvent is one entry of the ticket timeline, ID is the ID of the comment, log,
+// attachment or assignment it was built from. System entries have no actor.
+type TimelineEvent struct {
+	Type       TimelineEventType      `json:"type"`
+	ID         int                    `json:"id"`
+	OccurredAt time.Time              `json:"occurred_at"`
+	Actor      *TimelineActor         `json:"actor,omitempty"`
+	Content    string                 `json:"content,omitempty"`
+	Action     models.TicketLogAction `json:"action,omitempty"`
+	Field      string                 `json:"field,omitempty"`
+	OldValue   string                 `json:"old_value,omitempty"`
+	NewValue   string                 `json:"new_value,omitempty"`
+	FilePath   string                 `json:"file_path,omitempty"`
+	Assignee   *TimelineActor         `json:"assignee,omitempty"`
+}
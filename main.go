@@ -14,6 +14,7 @@ import (
 	"os"
 	"strconv"
 	"time"
+	_ "time/tzdata" // business calendars need timezones in minimal images
 
 	"github.com/gin-contrib/cors"
 	"github.com/gin-gonic/gin"
@@ -52,6 +53,12 @@ func main() {
 	db := helpers.ConnectDB()
 	helpers.MigrateDB(db, domain.GetAllModels()...)
 	repo := repositories.NewAppRepository(db)
+	if err := repo.SeedTicketWorkflow(); err != nil {
+		log.Printf("Error seeding default ticket workflow: %v", err)
+	}
+	if err := repo.CreateTicketSearchIndexes(); err != nil {
+		log.Printf("Error creating ticket search indexes: %v", err)
+	}
 
 	// Add S3 repository initialization
 	s3Repo := s3.NewS3Repository(timeoutContext)
@@ -69,8 +76,8 @@ func main() {
 	ginEngine.Use(cors.New(cors.Config{
 		AllowOrigins:     []string{"http://localhost:3000", "http://localhost:3001", os.Getenv("APP_URL")},
 		AllowMethods:     []string{"GET", "POST", "PUT", "PATCH", "DELETE", "OPTIONS"},
-		AllowHeaders:     []string{"Origin", "Content-Type", "Accept", "Authorization"},
-		ExposeHeaders:    []string{"Content-Length"},
+		AllowHeaders:     []string{"Origin", "Content-Type", "Accept", "Authorization", "If-Match"},
+		ExposeHeaders:    []string{"Content-Length", "ETag"},
 		AllowCredentials: true,
 		AllowWebSockets:  true,
 		MaxAge:           12 * time.Hour,
@@ -89,6 +96,22 @@ func main() {
 	// Start cleanup job for expired messages
 	go startMessageCleanupJob(repo)
 
+	// Start SLA breach detection job
+	go startSLABreachJob(service)
+
+	// Start purge job for tickets deleted longer than the retention period ago
+	go startTicketPurgeJob(service, ticketPurgeRetention())
+
+	// Start auto-close job for resolved tickets without a customer reply
+	autoCloseDays, autoCloseWarningDays := ticketAutoCloseWindow()
+	go startTicketAutoCloseJob(service, autoCloseDays, autoCloseWarningDays)
+
+	// Start wake-up job for snoozed tickets whose timer expired
+	go startTicketWakeJob(service)
+
+	// Start escalation job for tickets nobody commented on in time
+	go startTicketEscalationJob(service)
+
 	port := os.Getenv("APP_PORT")
 	ginEngine.Run(":" + port)
 }
@@ -106,3 +129,88 @@ func startMessageCleanupJob(repo domain.AppRepository) {
 		}
 	}
 }
+
+// startSLABreachJob periodically records tickets whose SLA targets passed their due timestamp
+func startSLABreachJob(service domain.AppService) {
+	ticker := time.NewTicker(5 * time.Minute)
+	defer ticker.Stop()
+
+	for range ticker.C {
+		if err := service.CheckSLABreaches(); err != nil {
+			log.Printf("Error checking SLA breaches: %v", err)
+		}
+	}
+}
+
+// ticketPurgeRetention is how long deleted tickets stay restorable, TICKET_PURGE_RETENTION_DAYS defaults to 30
+func ticketPurgeRetention() time.Duration {
+	days, err := strconv.Atoi(os.Getenv("TICKET_PURGE_RETENTION_DAYS"))
+	if err != nil || days <= 0 {
+		days = 30
+	}
+	return time.Duration(days) * 24 * time.Hour
+}
+
+// startTicketPurgeJob runs daily to permanently delete tickets past the retention period
+func startTicketPurgeJob(service domain.AppService, retention time.Duration) {
+	ticker := time.NewTicker(24 * time.Hour)
+	defer ticker.Stop()
+
+	for range ticker.C {
+		if err := service.PurgeDeletedTickets(retention); err != nil {
+			log.Printf("Error purging deleted tickets: %v", err)
+		}
+	}
+}
+
+// ticketAutoCloseWindow is how many working days a resolved ticket waits for a customer reply
+// before it is closed, TICKET_AUTO_CLOSE_DAYS defaults to 7. The owner is warned
+// TICKET_AUTO_CLOSE_WARNING_DAYS working days (default 1, 0 disables the warning) before the
+// ticket closes.
+func ticketAutoCloseWindow() (days, warningDays int) {
+	days, err := strconv.Atoi(os.Getenv("TICKET_AUTO_CLOSE_DAYS"))
+	if err != nil || days <= 0 {
+		days = 7
+	}
+	warningDays, err = strconv.Atoi(os.Getenv("TICKET_AUTO_CLOSE_WARNING_DAYS"))
+	if err != nil || warningDays < 0 {
+		warningDays = 1
+	}
+	return days, warningDays
+}
+
+// startTicketAutoCloseJob runs hourly to warn about and close resolved tickets
+func startTicketAutoCloseJob(service domain.AppService, days, warningDays int) {
+	ticker := time.NewTicker(time.Hour)
+	defer ticker.Stop()
+
+	for range ticker.C {
+		if err := service.AutoCloseResolvedTickets(days, warningDays); err != nil {
+			log.Printf("Error auto-closing resolved tickets: %v", err)
+		}
+	}
+}
+
+// startTicketWakeJob runs every minute to move snoozed tickets back to open
+func startTicketWakeJob(service domain.AppService) {
+	ticker := time.NewTicker(time.Minute)
+	defer ticker.Stop()
+
+	for range ticker.C {
+		if err := service.WakePendingTickets(); err != nil {
+			log.Printf("Error waking pending tickets: %v", err)
+		}
+	}
+}
+
+// startTicketEscalationJob runs every 5 minutes to apply the escalation rules
+func startTicketEscalationJob(service domain.AppService) {
+	ticker := time.NewTicker(5 * time.Minute)
+	defer ticker.Stop()
+
+	for range ticker.C {
+		if err := service.EscalateTickets(); err != nil {
+			log.Printf("Error escalating tickets: %v", err)
+		}
+	}
+}